@@ -1,13 +1,20 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/summerwind/h2spec"
 	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/selfcheck"
+	"github.com/summerwind/h2spec/spec"
+	"golang.org/x/net/http2"
 )
 
 var (
@@ -19,7 +26,7 @@ func main() {
 	var cmd = &cobra.Command{
 		Use:   "h2spec [spec...]",
 		Short: "Conformance testing tool for HTTP/2 implementation",
-		Long:  "Conformance testing tool for HTTP/2 implementation.",
+		Long:  "Conformance testing tool for HTTP/2 implementation.\n\nRun \"h2spec selfcheck [spec...]\" to test h2spec itself against the built-in reference server.",
 		RunE:  run,
 	}
 
@@ -29,15 +36,50 @@ func main() {
 	flags := cmd.Flags()
 	flags.StringP("host", "h", "127.0.0.1", "Target host")
 	flags.IntP("port", "p", 0, "Target port")
+	flags.BoolP("ipv4", "4", false, "Connect only over IPv4")
+	flags.BoolP("ipv6", "6", false, "Connect only over IPv6")
 	flags.StringP("path", "P", "/", "Target path")
+	flags.String("request-file", "", "Path of a file of HTTP/1.1 style request used as the normal request of the test cases")
+	flags.String("resource-path", "", "Target path of a large resource for flow control tests")
+	flags.StringSlice("multiplex-paths", nil, "Target paths requested on the concurrent streams in the multiplexing test cases")
+	flags.String("upload-path", "", "Target path of an endpoint that accepts large request bodies, which enables the upload test case")
+	flags.Int("upload-size", 1048576, "Size of the request body uploaded in the flow control tests")
+	flags.String("echo-path", "", "Target path of an endpoint that echoes request headers")
+	flags.String("trailers-path", "", "Target path of an endpoint that sends trailers in the response")
+	flags.String("websocket-path", "", "Target path of a WebSocket endpoint for WebSocket over HTTP/2 tests")
 	flags.IntP("timeout", "o", 2, "Time seconds to test timeout")
+	flags.Int("run-timeout", 0, "Time seconds after which the run is aborted (0 for no limit)")
+	flags.Float64("timeout-scale", 1, "Multiplier applied to the timeouts declared by test cases")
+	flags.Int("retries", 0, "Number of times to retry a test case failed with timeout or error")
+	flags.Int("repeat", 1, "Number of times to run each test case to detect flaky results")
+	flags.Bool("fail-on-inconclusive", false, "Exit with failure when a test case is inconclusive")
+	flags.String("fail-on-severity", "", "Exit with failure only when a test case of the severity (low, medium or high) or higher has failed")
 	flags.Int("max-header-length", 4000, "Maximum length of HTTP header")
 	flags.StringP("junit-report", "j", "", "Path for JUnit test report")
+	flags.String("csv", "", "Path for CSV test report")
+	flags.StringArray("output", nil, "Path for test report whose format is inferred from the extension (json, xml, html, tap, csv or md), which can be repeated")
+	flags.String("event-log", "", "Path for JSON lines event log")
+	flags.StringArray("label", nil, "Label of the run written to the metadata of the reports as key=value, which can be repeated")
+	flags.Uint32("header-table-size", 0, "Value of SETTINGS_HEADER_TABLE_SIZE sent in the connection preface")
+	flags.Uint32("enable-push", 0, "Value of SETTINGS_ENABLE_PUSH sent in the connection preface")
+	flags.Uint32("initial-window-size", 0, "Value of SETTINGS_INITIAL_WINDOW_SIZE sent in the connection preface")
+	flags.String("spec", "", "Rule set of HTTP/2 to test against (7540 or 9113)")
 	flags.BoolP("strict", "S", false, "Run all test cases including strict test cases")
+	flags.Float64("settings-ack-delay", 1, "Time seconds over the round-trip time that SETTINGS ACK may take in strict mode, which can be fractional")
 	flags.Bool("dryrun", false, "Display only the title of test cases")
 	flags.BoolP("tls", "t", false, "Connect over TLS")
 	flags.BoolP("insecure", "k", false, "Don't verify server's certificate")
 	flags.BoolP("verbose", "v", false, "Output verbose log")
+	flags.Bool("include-flood", false, "Run the flood resilience test cases")
+	flags.Int("flood-streams", 1000, "Number of streams opened in the flood test cases")
+	flags.Int("flood-frames", 10000, "Number of frames sent in the flood test cases")
+	flags.Int("flood-bytes", 16777216, "Number of bytes sent in the CONTINUATION flood test cases")
+	flags.Bool("include-slow", false, "Run the slow attack resilience test cases")
+	flags.StringSlice("include-tags", nil, "Run only the test cases that have any of the tags")
+	flags.StringSlice("exclude-tags", nil, "Skip the test cases that have any of the tags")
+	flags.Int("slow-interval", 5, "Time seconds between frames in the slow test cases")
+	flags.Int("slow-duration", 300, "Time seconds to wait for the server to react in the slow test cases")
+	flags.Bool("include-resumption", false, "Run the TLS session resumption test cases")
 	flags.Bool("version", false, "Display version information and exit")
 	flags.Bool("help", false, "Display this help and exit")
 
@@ -71,16 +113,139 @@ func run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	ipv4, err := flags.GetBool("ipv4")
+	if err != nil {
+		return err
+	}
+
+	ipv6, err := flags.GetBool("ipv6")
+	if err != nil {
+		return err
+	}
+
+	ipVersion := 0
+	switch {
+	case ipv4 && ipv6:
+		return fmt.Errorf("--ipv4 and --ipv6 cannot be specified together")
+	case ipv4:
+		ipVersion = 4
+	case ipv6:
+		ipVersion = 6
+	}
+
 	path, err := flags.GetString("path")
 	if err != nil {
 		return err
 	}
 
+	requestFile, err := flags.GetString("request-file")
+	if err != nil {
+		return err
+	}
+
+	// The path of the request file replaces the target path.
+	var request *config.Request
+	if requestFile != "" {
+		if flags.Changed("path") {
+			return fmt.Errorf("--request-file and --path cannot be specified together")
+		}
+
+		request, err = config.LoadRequest(requestFile)
+		if err != nil {
+			return err
+		}
+		path = request.Path
+	}
+
+	resourcePath, err := flags.GetString("resource-path")
+	if err != nil {
+		return err
+	}
+
+	multiplexPaths, err := flags.GetStringSlice("multiplex-paths")
+	if err != nil {
+		return err
+	}
+
+	uploadPath, err := flags.GetString("upload-path")
+	if err != nil {
+		return err
+	}
+
+	uploadSize, err := flags.GetInt("upload-size")
+	if err != nil {
+		return err
+	}
+
+	echoPath, err := flags.GetString("echo-path")
+	if err != nil {
+		return err
+	}
+
+	trailersPath, err := flags.GetString("trailers-path")
+	if err != nil {
+		return err
+	}
+
+	webSocketPath, err := flags.GetString("websocket-path")
+	if err != nil {
+		return err
+	}
+
 	timeout, err := flags.GetInt("timeout")
 	if err != nil {
 		return err
 	}
 
+	runTimeout, err := flags.GetInt("run-timeout")
+	if err != nil {
+		return err
+	}
+
+	timeoutScale, err := flags.GetFloat64("timeout-scale")
+	if err != nil {
+		return err
+	}
+
+	retries, err := flags.GetInt("retries")
+	if err != nil {
+		return err
+	}
+
+	repeat, err := flags.GetInt("repeat")
+	if err != nil {
+		return err
+	}
+
+	failOnInconclusive, err := flags.GetBool("fail-on-inconclusive")
+	if err != nil {
+		return err
+	}
+
+	failOnSeverity, err := flags.GetString("fail-on-severity")
+	if err != nil {
+		return err
+	}
+
+	if failOnSeverity != "" {
+		_, err = spec.ParseSeverity(failOnSeverity)
+		if err != nil {
+			return err
+		}
+	}
+
+	profile, err := flags.GetString("spec")
+	if err != nil {
+		return err
+	}
+
+	if profile != "" {
+		_, err = spec.ParseProfile(profile)
+		if err != nil {
+			return err
+		}
+	}
+
 	maxHeaderLen, err := flags.GetInt("max-header-length")
 	if err != nil {
 		return err
@@ -91,11 +256,71 @@ func run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	csvReport, err := flags.GetString("csv")
+	if err != nil {
+		return err
+	}
+
+	outputs, err := flags.GetStringArray("output")
+	if err != nil {
+		return err
+	}
+
+	eventLog, err := flags.GetString("event-log")
+	if err != nil {
+		return err
+	}
+
+	labelFlags, err := flags.GetStringArray("label")
+	if err != nil {
+		return err
+	}
+
+	labels := map[string]string{}
+	for _, label := range labelFlags {
+		i := strings.Index(label, "=")
+		if i <= 0 {
+			return fmt.Errorf("invalid label: %s (key=value)", label)
+		}
+		labels[label[:i]] = label[i+1:]
+	}
+
+	// The settings are sent only if they are specified.
+	clientSettings := []http2.Setting{}
+	settingFlags := []struct {
+		name string
+		id   http2.SettingID
+	}{
+		{"header-table-size", http2.SettingHeaderTableSize},
+		{"enable-push", http2.SettingEnablePush},
+		{"initial-window-size", http2.SettingInitialWindowSize},
+	}
+	for _, sf := range settingFlags {
+		if !flags.Changed(sf.name) {
+			continue
+		}
+
+		val, err := flags.GetUint32(sf.name)
+		if err != nil {
+			return err
+		}
+
+		clientSettings = append(clientSettings, http2.Setting{ID: sf.id, Val: val})
+	}
+
 	strict, err := flags.GetBool("strict")
 	if err != nil {
 		return err
 	}
 
+	settingsAckDelay, err := flags.GetFloat64("settings-ack-delay")
+	if err != nil {
+		return err
+	}
+	if settingsAckDelay <= 0 {
+		return fmt.Errorf("--settings-ack-delay must be positive: %v", settingsAckDelay)
+	}
+
 	dryRun, err := flags.GetBool("dryrun")
 	if err != nil {
 		return err
@@ -116,6 +341,56 @@ func run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	includeFlood, err := flags.GetBool("include-flood")
+	if err != nil {
+		return err
+	}
+
+	floodStreams, err := flags.GetInt("flood-streams")
+	if err != nil {
+		return err
+	}
+
+	floodFrames, err := flags.GetInt("flood-frames")
+	if err != nil {
+		return err
+	}
+
+	floodBytes, err := flags.GetInt("flood-bytes")
+	if err != nil {
+		return err
+	}
+
+	includeSlow, err := flags.GetBool("include-slow")
+	if err != nil {
+		return err
+	}
+
+	includeResumption, err := flags.GetBool("include-resumption")
+	if err != nil {
+		return err
+	}
+
+	includeTags, err := flags.GetStringSlice("include-tags")
+	if err != nil {
+		return err
+	}
+
+	excludeTags, err := flags.GetStringSlice("exclude-tags")
+	if err != nil {
+		return err
+	}
+
+	slowInterval, err := flags.GetInt("slow-interval")
+	if err != nil {
+		return err
+	}
+
+	slowDuration, err := flags.GetInt("slow-duration")
+	if err != nil {
+		return err
+	}
+
 	if port == 0 {
 		if tls {
 			port = 443
@@ -125,26 +400,109 @@ func run(cmd *cobra.Command, args []string) error {
 	}
 
 	c := &config.Config{
-		Host:         host,
-		Port:         port,
-		Path:         path,
-		Timeout:      time.Duration(timeout) * time.Second,
-		MaxHeaderLen: maxHeaderLen,
-		JUnitReport:  junitReport,
-		Strict:       strict,
-		DryRun:       dryRun,
-		TLS:          tls,
-		Insecure:     insecure,
-		Verbose:      verbose,
-		Sections:     args,
-	}
-
-	success, err := h2spec.Run(c)
+		Host:               host,
+		Port:               port,
+		IPVersion:          ipVersion,
+		Path:               path,
+		ResourcePath:       resourcePath,
+		MultiplexPaths:     multiplexPaths,
+		UploadPath:         uploadPath,
+		UploadSize:         uploadSize,
+		EchoPath:           echoPath,
+		TrailersPath:       trailersPath,
+		WebSocketPath:      webSocketPath,
+		Timeout:            time.Duration(timeout) * time.Second,
+		RunTimeout:         time.Duration(runTimeout) * time.Second,
+		TimeoutScale:       timeoutScale,
+		Retries:            retries,
+		Repeat:             repeat,
+		FailOnInconclusive: failOnInconclusive,
+		FailOnSeverity:     failOnSeverity,
+		Profile:            profile,
+		MaxHeaderLen:       maxHeaderLen,
+		JUnitReport:        junitReport,
+		CSVReport:          csvReport,
+		Outputs:            outputs,
+		EventLog:           eventLog,
+		Strict:             strict,
+		SettingsAckDelay:   time.Duration(settingsAckDelay * float64(time.Second)),
+		DryRun:             dryRun,
+		TLS:                tls,
+		Insecure:           insecure,
+		Verbose:            verbose,
+		IncludeFlood:       includeFlood,
+		FloodStreams:       floodStreams,
+		FloodFrames:        floodFrames,
+		FloodBytes:         floodBytes,
+		IncludeSlow:        includeSlow,
+		SlowInterval:       time.Duration(slowInterval) * time.Second,
+		SlowDuration:       time.Duration(slowDuration) * time.Second,
+		IncludeResumption:  includeResumption,
+		Sections:           args,
+		IncludeTags:        includeTags,
+		ExcludeTags:        excludeTags,
+		ClientSettings:     clientSettings,
+		Request:            request,
+		Labels:             labels,
+		Version:            VERSION,
+		Commit:             COMMIT,
+	}
+
+	// Ctrl-C or SIGTERM cancels the run, so that the connection of the running
+	// test case is closed and the completed test cases are reported.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// "h2spec selfcheck [spec...]" runs the specs against the
+	// reference server instead of the target. It is not a subcommand
+	// since the arguments of the root command are the specs.
+	if len(args) > 0 && args[0] == "selfcheck" {
+		c.Sections = args[1:]
+		return runSelfCheck(ctx, c)
+	}
+
+	success, err := h2spec.RunContext(ctx, c)
+	if ierr, ok := err.(*h2spec.InterruptedError); ok {
+		fmt.Println(ierr)
+		os.Exit(130)
+	}
+
+	if terr, ok := err.(*h2spec.RunTimeoutError); ok {
+		fmt.Println(terr)
+		os.Exit(124)
+	}
+
+	// The error of the reports is shown after the console output.
+	if err != nil {
+		return err
+	}
+
 	if !success {
 		os.Exit(1)
 	}
 
-	return err
+	return nil
+}
+
+func runSelfCheck(ctx context.Context, c *config.Config) error {
+	err := selfcheck.Run(ctx, c)
+	switch err := err.(type) {
+	case nil:
+		fmt.Println("Selfcheck passed")
+	case *h2spec.InterruptedError:
+		fmt.Println(err)
+		os.Exit(130)
+	case *h2spec.RunTimeoutError:
+		fmt.Println(err)
+		os.Exit(124)
+	case *selfcheck.Error:
+		fmt.Println(err)
+		os.Exit(1)
+	default:
+		return err
+	}
+
+	return nil
 }
 
 func version() {
@@ -1,10 +1,15 @@
 package config
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
+	"net"
+	"strconv"
 	"strings"
 	"time"
+
+	"golang.org/x/net/http2"
 )
 
 const (
@@ -15,28 +20,146 @@ const (
 
 // Config represents the configuration of h2spec.
 type Config struct {
-	Host         string
-	Port         int
-	Path         string
-	Timeout      time.Duration
-	MaxHeaderLen int
-	JUnitReport  string
-	Strict       bool
-	DryRun       bool
-	TLS          bool
-	Insecure     bool
-	Verbose      bool
-	Sections     []string
-	targetMap    map[string]bool
-	CertFile     string
-	CertKeyFile  string
-	Exec         string
-	FromPort     int
+	Host               string
+	Port               int
+	IPVersion          int
+	Path               string
+	ResourcePath       string
+	MultiplexPaths     []string
+	UploadPath         string
+	UploadSize         int
+	EchoPath           string
+	TrailersPath       string
+	WebSocketPath      string
+	Timeout            time.Duration
+	RunTimeout         time.Duration
+	TimeoutScale       float64
+	Retries            int
+	Repeat             int
+	FailOnInconclusive bool
+	FailOnSeverity     string
+	MaxHeaderLen       int
+	JUnitReport        string
+	CSVReport          string
+	Outputs            []string
+	EventLog           string
+	Profile            string
+	Strict             bool
+	SettingsAckDelay   time.Duration
+	DryRun             bool
+	TLS                bool
+	Insecure           bool
+	Verbose            bool
+	IncludeFlood       bool
+	FloodStreams       int
+	FloodFrames        int
+	FloodBytes         int
+	IncludeSlow        bool
+	SlowInterval       time.Duration
+	SlowDuration       time.Duration
+	IncludeResumption  bool
+	Sections           []string
+	IncludeTags        []string
+	ExcludeTags        []string
+	ClientSettings     []http2.Setting
+	targetMap          map[string]bool
+	CertFile           string
+	CertKeyFile        string
+	Exec               string
+	FromPort           int
+
+	// Dialer establishes the transport connection to the target. The
+	// connection is made over TCP by net.Dialer if it is nil.
+	Dialer Dialer
+
+	// Request is the canonical request loaded from the request file,
+	// or nil if it is not specified.
+	Request *Request
+
+	// SessionCache is the cache of TLS sessions shared by the
+	// connections, which resume the session of the previous connection.
+	// The session is not resumed if it is nil.
+	SessionCache tls.ClientSessionCache
+
+	// Detection is the result of the capability detection of the run,
+	// which is set by spec.DetectCapabilities, so that the runs with
+	// their own configurations do not share it.
+	Detection interface{}
+
+	// Labels is the labels of the run given as key=value, which are
+	// written to the metadata of the reports.
+	Labels map[string]string
+
+	// Version and Commit are the version and the commit of the build
+	// of h2spec, which are written to the metadata of the reports.
+	Version string
+	Commit  string
+}
+
+// Dialer establishes the transport connection to the target, which
+// replaces the TCP connection made by default.
+type Dialer interface {
+	DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// DialerFunc is an adapter to use the function as a Dialer.
+type DialerFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// DialContext calls f(ctx, network, addr).
+func (f DialerFunc) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return f(ctx, network, addr)
+}
+
+// TLSWrapper is implemented by a Dialer that establishes TLS on the
+// transport connection by itself. The TLS client of crypto/tls is used
+// if the Dialer does not implement it.
+type TLSWrapper interface {
+	WrapTLS(ctx context.Context, conn net.Conn, config *tls.Config) (net.Conn, error)
+}
+
+// EarlyDataWrapper is implemented by a Dialer that establishes TLS 1.3
+// on the transport connection resuming the session with 0-RTT early
+// data, which crypto/tls does not support. The early data is sent in
+// the first flight of the client, and whether the server accepted it is
+// returned. If the server rejected it, the returned connection is
+// established with the full handshake without the early data.
+type EarlyDataWrapper interface {
+	WrapTLSWithEarlyData(ctx context.Context, conn net.Conn, config *tls.Config, earlyData []byte) (net.Conn, bool, error)
 }
 
 // Addr returns the string concatinated with hostname and port number.
 func (c *Config) Addr() string {
-	return fmt.Sprintf("%s:%d", c.Host, c.Port)
+	return net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+}
+
+// Network returns the network to connect to the target. The connection
+// is made over the address family of IPVersion if it is 4 or 6, or over
+// either of them otherwise.
+func (c *Config) Network() string {
+	switch c.IPVersion {
+	case 4:
+		return "tcp4"
+	case 6:
+		return "tcp6"
+	default:
+		return "tcp"
+	}
+}
+
+// TestTimeout returns the timeout of a test case that declares the
+// specified timeout. The declared timeout is multiplied by the timeout
+// scale, and the global timeout is used if it is not declared.
+func (c *Config) TestTimeout(d time.Duration) time.Duration {
+	if d == 0 {
+		return c.Timeout
+	}
+
+	scale := c.TimeoutScale
+	if scale <= 0 {
+		scale = 1
+	}
+
+	return time.Duration(float64(d) * scale)
 }
 
 func (c *Config) Scheme() string {
@@ -55,6 +178,7 @@ func (c *Config) TLSConfig() (*tls.Config, error) {
 
 	config := tls.Config{
 		InsecureSkipVerify: c.Insecure,
+		ClientSessionCache: c.SessionCache,
 	}
 
 	if config.NextProtos == nil {
@@ -72,6 +196,44 @@ func (c *Config) TLSConfig() (*tls.Config, error) {
 	return &config, nil
 }
 
+// TagsSelected returns true if the test case that has the specified
+// tags is selected by the tag filters. The test case needs one of the
+// included tags if any tag is included, and must not have any of the
+// excluded tags. The "flood", "slow" and "resumption" tags are excluded
+// unless they are included by IncludeTags, IncludeFlood, IncludeSlow or
+// IncludeResumption.
+func (c *Config) TagsSelected(tags []string) bool {
+	if len(c.IncludeTags) > 0 && !containsAny(c.IncludeTags, tags) {
+		return false
+	}
+
+	excluded := append([]string{}, c.ExcludeTags...)
+	if !c.IncludeFlood && !containsAny(c.IncludeTags, []string{"flood"}) {
+		excluded = append(excluded, "flood")
+	}
+	if !c.IncludeSlow && !containsAny(c.IncludeTags, []string{"slow"}) {
+		excluded = append(excluded, "slow")
+	}
+	if !c.IncludeResumption && !containsAny(c.IncludeTags, []string{"resumption"}) {
+		excluded = append(excluded, "resumption")
+	}
+
+	return !containsAny(excluded, tags)
+}
+
+// containsAny returns true if the list contains any of the values.
+func containsAny(list, values []string) bool {
+	for _, v := range values {
+		for _, item := range list {
+			if item == v {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // RunMode returns a run mode of specified the section number.
 // This is used to decide whether to run test cases.
 func (c *Config) RunMode(section string) int {
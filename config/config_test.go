@@ -1,6 +1,9 @@
 package config
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestRunMode(t *testing.T) {
 	tests := []struct {
@@ -87,3 +90,95 @@ func TestRunMode(t *testing.T) {
 		}
 	}
 }
+
+func TestTestTimeout(t *testing.T) {
+	tests := []struct {
+		timeout  time.Duration
+		scale    float64
+		declared time.Duration
+		expected time.Duration
+	}{
+		{timeout: 2 * time.Second, scale: 1, declared: 0, expected: 2 * time.Second},
+		{timeout: 2 * time.Second, scale: 3, declared: 0, expected: 2 * time.Second},
+		{timeout: 2 * time.Second, scale: 1, declared: 10 * time.Second, expected: 10 * time.Second},
+		{timeout: 2 * time.Second, scale: 0.5, declared: 10 * time.Second, expected: 5 * time.Second},
+		{timeout: 2 * time.Second, scale: 0, declared: 10 * time.Second, expected: 10 * time.Second},
+	}
+
+	for i, tt := range tests {
+		c := Config{
+			Timeout:      tt.timeout,
+			TimeoutScale: tt.scale,
+		}
+
+		timeout := c.TestTimeout(tt.declared)
+		if tt.expected != timeout {
+			t.Errorf("#%d timeout - expect: %v, got: %v", i, tt.expected, timeout)
+		}
+	}
+}
+
+func TestTagsSelected(t *testing.T) {
+	tests := []struct {
+		include      []string
+		exclude      []string
+		includeFlood bool
+		tags         []string
+		selected     bool
+	}{
+		{tags: []string{}, selected: true},
+		{tags: []string{"hpack"}, selected: true},
+		{tags: []string{"flood"}, selected: false},
+		{tags: []string{"slow"}, selected: false},
+		{includeFlood: true, tags: []string{"flood"}, selected: true},
+		{include: []string{"flood"}, tags: []string{"flood"}, selected: true},
+		{include: []string{"flood"}, tags: []string{"slow"}, selected: false},
+		{tags: []string{"resumption"}, selected: false},
+		{include: []string{"resumption"}, tags: []string{"resumption"}, selected: true},
+		{include: []string{"hpack"}, tags: []string{}, selected: false},
+		{include: []string{"hpack"}, tags: []string{"hpack", "needs-echo"}, selected: true},
+		{exclude: []string{"needs-echo"}, tags: []string{"hpack", "needs-echo"}, selected: false},
+		{include: []string{"hpack"}, exclude: []string{"needs-echo"}, tags: []string{"hpack", "needs-echo"}, selected: false},
+	}
+
+	for i, tt := range tests {
+		c := Config{
+			IncludeTags:  tt.include,
+			ExcludeTags:  tt.exclude,
+			IncludeFlood: tt.includeFlood,
+		}
+
+		selected := c.TagsSelected(tt.tags)
+		if tt.selected != selected {
+			t.Errorf("#%d selected - expect: %v, got: %v", i, tt.selected, selected)
+		}
+	}
+}
+
+func TestAddrAndNetwork(t *testing.T) {
+	tests := []struct {
+		host      string
+		ipVersion int
+		addr      string
+		network   string
+	}{
+		{host: "127.0.0.1", addr: "127.0.0.1:443", network: "tcp"},
+		{host: "::1", ipVersion: 6, addr: "[::1]:443", network: "tcp6"},
+		{host: "example.com", ipVersion: 4, addr: "example.com:443", network: "tcp4"},
+	}
+
+	for i, tt := range tests {
+		c := Config{
+			Host:      tt.host,
+			Port:      443,
+			IPVersion: tt.ipVersion,
+		}
+
+		if addr := c.Addr(); tt.addr != addr {
+			t.Errorf("#%d addr - expect: %v, got: %v", i, tt.addr, addr)
+		}
+		if network := c.Network(); tt.network != network {
+			t.Errorf("#%d network - expect: %v, got: %v", i, tt.network, network)
+		}
+	}
+}
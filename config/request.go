@@ -0,0 +1,142 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"golang.org/x/net/http2/hpack"
+)
+
+// maxRequestBodySize is the maximum size of the body of the canonical
+// request, which is sent within the initial flow-control window.
+const maxRequestBodySize = 65535
+
+// connectionSpecificFields is the header fields of HTTP/1.1 that are
+// not allowed in HTTP/2.
+var connectionSpecificFields = []string{
+	"connection",
+	"keep-alive",
+	"proxy-connection",
+	"transfer-encoding",
+	"upgrade",
+}
+
+// Request represents the canonical request that is sent by the test
+// cases which only need a normal request to exercise the server.
+type Request struct {
+	Method string
+	Path   string
+
+	// Authority is the value of "host" header field, or empty if the
+	// authority of the target is used.
+	Authority string
+
+	// Headers is the regular header fields of the request. The names
+	// are lowercase, and "host" and "content-length" are not included.
+	Headers []hpack.HeaderField
+
+	Body []byte
+}
+
+// LoadRequest reads the canonical request from the file of the
+// specified path.
+func LoadRequest(path string) (*Request, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := ParseRequest(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+
+	return req, nil
+}
+
+// ParseRequest parses the text of a HTTP/1.1 style request, which
+// consists of the request line, the header fields, a blank line and the
+// body. The HTTP version of the request line may be omitted. The
+// pseudo-header fields are derived from the request line and "host"
+// header field, and "content-length" header field is derived from the
+// body when the request is sent. The lines of the head may end with
+// either CRLF or LF, and the body is kept as it is.
+func ParseRequest(data []byte) (*Request, error) {
+	lines, body := splitRequest(data)
+
+	if len(body) > maxRequestBodySize {
+		return nil, fmt.Errorf("body of %d octets exceeds %d octets", len(body), maxRequestBodySize)
+	}
+
+	if len(lines) == 0 || lines[0] == "" {
+		return nil, fmt.Errorf("request line is missing")
+	}
+
+	parts := strings.Fields(lines[0])
+	if len(parts) < 2 || len(parts) > 3 || (len(parts) == 3 && !strings.HasPrefix(parts[2], "HTTP/")) {
+		return nil, fmt.Errorf("invalid request line: %q", lines[0])
+	}
+	if !strings.HasPrefix(parts[1], "/") && parts[1] != "*" {
+		return nil, fmt.Errorf("invalid request target: %q", parts[1])
+	}
+
+	req := &Request{
+		Method: parts[0],
+		Path:   parts[1],
+		Body:   body,
+	}
+
+	for i, line := range lines[1:] {
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			return nil, fmt.Errorf("line %d: folded header field is not supported", i+2)
+		}
+
+		colon := strings.Index(line, ":")
+		if colon <= 0 {
+			return nil, fmt.Errorf("line %d: invalid header field: %q", i+2, line)
+		}
+
+		name := strings.ToLower(strings.TrimSpace(line[:colon]))
+		value := strings.TrimSpace(line[colon+1:])
+
+		switch {
+		case strings.HasPrefix(name, ":"):
+			return nil, fmt.Errorf("line %d: pseudo-header field %q is derived from the request line", i+2, name)
+		case containsAny(connectionSpecificFields, []string{name}):
+			return nil, fmt.Errorf("line %d: connection-specific header field %q is not allowed in HTTP/2", i+2, name)
+		case name == "host":
+			req.Authority = value
+		case name == "content-length":
+			// The content-length is derived from the body.
+		default:
+			req.Headers = append(req.Headers, hpack.HeaderField{Name: name, Value: value})
+		}
+	}
+
+	return req, nil
+}
+
+// splitRequest splits the text of the request into the lines of the
+// head without the line endings and the body following the blank line.
+// The body is nil if there is no blank line.
+func splitRequest(data []byte) ([]string, []byte) {
+	lines := []string{}
+	for len(data) > 0 {
+		line := data
+		rest := []byte(nil)
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			line, rest = data[:i], data[i+1:]
+		}
+		line = bytes.TrimSuffix(line, []byte("\r"))
+
+		if len(line) == 0 {
+			return lines, rest
+		}
+		lines = append(lines, string(line))
+		data = rest
+	}
+
+	return lines, nil
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestParseRequest(t *testing.T) {
+	data := "POST /api?x=1 HTTP/1.1\r\n" +
+		"Host: example.com\r\n" +
+		"Authorization: Bearer token\r\n" +
+		"Content-Type: application/json\r\n" +
+		"Content-Length: 100\r\n" +
+		"\r\n" +
+		"{\"a\":1}"
+
+	req, err := ParseRequest([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if req.Method != "POST" || req.Path != "/api?x=1" || req.Authority != "example.com" {
+		t.Errorf("unexpected request line: %s %s (authority:%s)", req.Method, req.Path, req.Authority)
+	}
+
+	if len(req.Headers) != 2 || req.Headers[0].Name != "authorization" || req.Headers[1].Value != "application/json" {
+		t.Errorf("unexpected header fields: %v", req.Headers)
+	}
+
+	if string(req.Body) != "{\"a\":1}" {
+		t.Errorf("unexpected body: %q", req.Body)
+	}
+}
+
+func TestParseRequestWithoutBody(t *testing.T) {
+	req, err := ParseRequest([]byte("GET /\nx-test: ok\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if req.Method != "GET" || req.Path != "/" || len(req.Headers) != 1 || len(req.Body) != 0 {
+		t.Errorf("unexpected request: %+v", req)
+	}
+}
+
+func TestParseRequestError(t *testing.T) {
+	tests := []string{
+		"",
+		"GET\n",
+		"GET / HTTP/1.1 extra\n",
+		"GET http://example.com/ HTTP/1.1\n",
+		"GET /\ninvalid\n",
+		"GET /\n:path: /other\n",
+		"GET /\nConnection: close\n",
+		"GET /\nx-test: a\n  b\n",
+	}
+
+	for _, data := range tests {
+		_, err := ParseRequest([]byte(data))
+		if err == nil {
+			t.Errorf("%q: expected error", data)
+		}
+	}
+}
+
+func TestParseRequestBodyWithCRLF(t *testing.T) {
+	req, err := ParseRequest([]byte("POST /\r\nx-test: ok\r\n\r\na\r\nb\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(req.Headers) != 1 || req.Headers[0].Value != "ok" {
+		t.Errorf("unexpected header fields: %v", req.Headers)
+	}
+
+	if string(req.Body) != "a\r\nb\r\n" {
+		t.Errorf("unexpected body: %q", req.Body)
+	}
+}
@@ -0,0 +1,68 @@
+package flood
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+	"golang.org/x/net/http2"
+)
+
+func RapidReset() *spec.TestGroup {
+	tg := NewTestGroup("1", "Rapid Reset")
+
+	// The HTTP/2 protocol allows a denial of service (server resource
+	// consumption) because request cancellation can reset many
+	// streams quickly, as exploited in the wild in August through
+	// October 2023. (CVE-2023-44487)
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends HEADERS frames immediately followed by RST_STREAM frames on many streams",
+		Requirement: "The endpoint SHOULD keep serving the connection or close it with GOAWAY frame deliberately.",
+		Timeout:     floodTimeout,
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			started := time.Now()
+
+			reset := 0
+			for ; reset < c.FloodStreams; reset++ {
+				hp := http2.HeadersFrameParam{
+					StreamID:      streamID,
+					EndStream:     true,
+					EndHeaders:    true,
+					BlockFragment: conn.EncodeHeaders(headers),
+				}
+
+				err = conn.WriteHeaders(hp)
+				if err == nil {
+					err = conn.WriteRSTStream(streamID, http2.ErrCodeCancel)
+				}
+				if err != nil {
+					break
+				}
+
+				streamID += 2
+			}
+			metrics := fmt.Sprintf("%d streams reset in %s", reset, time.Since(started).Round(time.Millisecond))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyFloodResilience(conn, streamID, started, metrics)
+		},
+	})
+
+	return tg
+}
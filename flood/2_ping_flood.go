@@ -0,0 +1,36 @@
+package flood
+
+import (
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+)
+
+func PingFlood() *spec.TestGroup {
+	tg := NewTestGroup("2", "Ping Flood")
+
+	// Some HTTP/2 implementations are vulnerable to ping floods,
+	// potentially leading to a denial of service. The attacker sends
+	// continual pings to an HTTP/2 peer, causing the peer to build an
+	// internal queue of responses. Depending on how efficiently this
+	// data is queued, this can consume excess CPU, memory, or both.
+	// (CVE-2019-9512)
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends PING frames continuously without reading the responses",
+		Requirement: "The endpoint MUST react within bounded time once the client resumes reading.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			data := [8]byte{'h', '2', 's', 'p', 'e', 'c'}
+			sent, metrics := flood(c, conn, c.FloodFrames, func() error {
+				return conn.WritePing(false, data)
+			})
+
+			return spec.VerifyFloodAcks(conn, spec.EventPingFrame, sent, metrics)
+		},
+	})
+
+	return tg
+}
@@ -0,0 +1,41 @@
+package flood
+
+import (
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+)
+
+func SettingsFlood() *spec.TestGroup {
+	tg := NewTestGroup("3", "Settings Flood")
+
+	// Some HTTP/2 implementations are vulnerable to a settings flood,
+	// potentially leading to denial of service. The attacker sends a
+	// stream of SETTINGS frames to the peer. Since the RFC requires
+	// that the peer reply with one acknowledgement per SETTINGS frame,
+	// an empty SETTINGS frame is almost equivalent in behavior to a
+	// ping. Depending on how efficiently this data is queued, this can
+	// consume excess CPU, memory, or both. (CVE-2019-9515)
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends empty SETTINGS frames continuously without reading the ACKs",
+		Requirement: "The endpoint MUST react within bounded time once the client resumes reading.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// The small send buffer makes the write block as soon as
+			// the server stops reading, instead of queueing the frames
+			// on the client side.
+			conn.SetWriteBuffer(4096)
+
+			sent, metrics := flood(c, conn, c.FloodFrames, func() error {
+				return conn.WriteSettings()
+			})
+
+			return spec.VerifyFloodAcks(conn, spec.EventSettingsFrame, sent, metrics)
+		},
+	})
+
+	return tg
+}
@@ -0,0 +1,58 @@
+package flood
+
+import (
+	"time"
+
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+	"golang.org/x/net/http2"
+)
+
+func EmptyFramesFlood() *spec.TestGroup {
+	tg := NewTestGroup("4", "Empty Frames Flood")
+
+	// Some HTTP/2 implementations are vulnerable to a flood of empty
+	// frames, potentially leading to a denial of service. The attacker
+	// sends a stream of frames with an empty payload and without the
+	// end-of-stream flag. These frames can be DATA, HEADERS,
+	// CONTINUATION and/or PUSH_PROMISE. The peer spends time processing
+	// each frame disproportionate to attack bandwidth. This can consume
+	// excess CPU. (CVE-2019-9518)
+	//
+	// Note: Empty DATA frames do not consume the flow-control window,
+	// so the flow control does not limit this flood.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends empty DATA frames continuously on a stream",
+		Requirement: "The endpoint MUST respond to the request or reset the stream or connection within bounded time.",
+		Timeout:     floodTimeout,
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers[0].Value = "POST"
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			started := time.Now()
+			_, metrics := flood(c, conn, c.FloodFrames, func() error {
+				return conn.WriteData(streamID, false, []byte{})
+			})
+			conn.WriteData(streamID, true, []byte{})
+
+			return spec.VerifyFloodResilience(conn, streamID, started, metrics)
+		},
+	})
+
+	return tg
+}
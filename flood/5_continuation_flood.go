@@ -0,0 +1,127 @@
+package flood
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+	"golang.org/x/net/http2"
+)
+
+func ContinuationFlood() *spec.TestGroup {
+	tg := NewTestGroup("5", "CONTINUATION Flood")
+
+	// An attacker can send an unbounded number of CONTINUATION frames
+	// without the END_HEADERS flag. The header block cannot be
+	// processed until it ends, so an implementation that buffers the
+	// header block without limit can run out of memory.
+	// (CVE-2024-27316)
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends CONTINUATION frames continuously without END_HEADERS flag",
+		Requirement: "The endpoint MUST terminate the connection before the header block grows without limit.",
+		Timeout:     floodTimeout,
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    false,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			// Each CONTINUATION frame contains a header field.
+			fragment := spec.LiteralHeaderField("x-dummy", spec.DummyString(100))
+			count := c.FloodBytes / (9 + len(fragment))
+
+			sent, metrics := flood(c, conn, count, func() error {
+				return conn.WriteContinuation(streamID, false, fragment)
+			})
+			metrics = fmt.Sprintf("%s, %d bytes sent", metrics, sent*(9+len(fragment)))
+
+			// The server that reads the whole flood before the
+			// termination buffers the header block up to the
+			// bytes sent, which is reported as a warning.
+			err = spec.VerifyFloodTermination(conn, metrics)
+			if info, ok := err.(*spec.TestInfo); ok && sent == count {
+				err = &spec.TestWarning{Reason: fmt.Sprintf("Whole flood absorbed, then %s", info.Message)}
+			}
+
+			return err
+		},
+	})
+
+	// A server that receives a larger header block than it is willing
+	// to handle can send an HTTP 431 (Request Header Fields Too Large)
+	// status code [RFC6585]. A client can discard responses that it
+	// cannot process. The header block MUST be processed to ensure a
+	// consistent connection state, unless the connection is closed.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends CONTINUATION frames exceeding SETTINGS_MAX_HEADER_LIST_SIZE",
+		Requirement: "The endpoint MUST reject the request or terminate the connection.",
+		Timeout:     floodTimeout,
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			maxHeaderListSize, ok := conn.Settings[http2.SettingMaxHeaderListSize]
+			if !ok {
+				maxHeaderListSize = uint32(65536)
+			}
+
+			headers := spec.CommonHeaders(c)
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    false,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			// Each CONTINUATION frame contains a header field, whose
+			// size is the sum of its name length, its value length
+			// and 32 octets.
+			name := "x-dummy"
+			value := spec.DummyString(1000)
+			fragment := spec.LiteralHeaderField(name, value)
+			count := int(maxHeaderListSize)/(len(name)+len(value)+32) + 1
+
+			sent, metrics := flood(c, conn, count, func() error {
+				return conn.WriteContinuation(streamID, false, fragment)
+			})
+			conn.WriteContinuation(streamID, true, fragment)
+			metrics = fmt.Sprintf("%s, %d bytes sent", metrics, (sent+1)*(9+len(fragment)))
+
+			hp = http2.HeadersFrameParam{
+				StreamID:      streamID + 2,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			// The server that accepts the request has absorbed the
+			// header list exceeding its own limit.
+			err = spec.VerifyOversizedHeaderList(conn, streamID, streamID+2)
+			if info, ok := err.(*spec.TestInfo); ok && strings.HasPrefix(info.Message, "Accepted") {
+				err = &spec.TestWarning{Reason: info.Message}
+			}
+
+			return withMetrics(err, metrics)
+		},
+	})
+
+	return tg
+}
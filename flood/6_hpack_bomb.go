@@ -0,0 +1,102 @@
+package flood
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+func HPACKBomb() *spec.TestGroup {
+	tg := NewTestGroup("6", "HPACK Bomb")
+
+	// An attacker can insert a large header field into the dynamic
+	// table and then refer to it repeatedly with indexed header field
+	// representations of a single octet. The decoded header list
+	// becomes much larger than the header block on the wire, so an
+	// implementation that does not limit the size of the decoded
+	// header list can run out of memory. (CVE-2016-6581)
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a header block referring to a large dynamic table entry repeatedly",
+		Requirement: "The endpoint MUST limit the size of the decoded header list.",
+		Timeout:     floodTimeout,
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			tableSize, ok := conn.Settings[http2.SettingHeaderTableSize]
+			if !ok {
+				tableSize = uint32(4096)
+			}
+
+			maxHeaderListSize, ok := conn.Settings[http2.SettingMaxHeaderListSize]
+			if !ok {
+				maxHeaderListSize = uint32(65536)
+			}
+
+			// The size of the entry is the sum of its name length, its
+			// value length and 32 octets, so that the entry fills the
+			// dynamic table.
+			name := "x-dummy"
+			value := spec.DummyString(int(tableSize) - len(name) - 32)
+			entrySize := len(name) + len(value) + 32
+
+			// The header blocks are encoded without the encoder of the
+			// connection to keep the dynamic table of the server
+			// predictable.
+			headers := spec.CommonHeaders(c)
+			blockFragment := literalHeaderBlock(headers)
+			blockFragment = append(blockFragment, spec.LiteralHeaderFieldWithIndexing(name, value)...)
+			conn.WriteHeaderBlock(streamID, true, blockFragment)
+
+			// The entry is the first entry in the dynamic table, which
+			// is referred with the index 62.
+			count := int(maxHeaderListSize)/entrySize + 1
+			if count < 4096 {
+				count = 4096
+			}
+
+			blockFragment = literalHeaderBlock(headers)
+			for i := 0; i < count; i++ {
+				blockFragment = append(blockFragment, spec.IndexedHeaderField(62)...)
+			}
+			conn.WriteHeaderBlock(streamID+2, true, blockFragment)
+
+			conn.WriteHeaderBlock(streamID+4, true, literalHeaderBlock(headers))
+
+			decodedSize := count * entrySize
+			for _, hf := range headers {
+				decodedSize += int(hf.Size())
+			}
+			metrics := fmt.Sprintf("%d bytes decoded from %d bytes, expansion ratio %.0fx", decodedSize, len(blockFragment), float64(decodedSize)/float64(len(blockFragment)))
+
+			err = spec.VerifyOversizedHeaderList(conn, streamID+2, streamID+4)
+			if info, ok := err.(*spec.TestInfo); ok && strings.HasPrefix(info.Message, "Accepted") {
+				err = &spec.TestWarning{Reason: info.Message}
+			}
+
+			return withMetrics(err, metrics)
+		},
+	})
+
+	return tg
+}
+
+// literalHeaderBlock returns the header block that contains the
+// specified header fields as literal header field representations
+// without indexing.
+func literalHeaderBlock(headers []hpack.HeaderField) []byte {
+	blockFragment := []byte{}
+	for _, hf := range headers {
+		blockFragment = append(blockFragment, spec.LiteralHeaderField(hf.Name, hf.Value)...)
+	}
+
+	return blockFragment
+}
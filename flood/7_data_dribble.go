@@ -0,0 +1,130 @@
+package flood
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+	"golang.org/x/net/http2"
+)
+
+const (
+	// dribbleInterval is the interval between the WINDOW_UPDATE frames
+	// sent in the data dribble test case.
+	dribbleInterval = 10 * time.Millisecond
+
+	// dribbleCount is the number of the WINDOW_UPDATE frames sent in
+	// the data dribble test case.
+	dribbleCount = 500
+)
+
+func DataDribble() *spec.TestGroup {
+	tg := NewTestGroup("7", "Data Dribble")
+
+	// Some HTTP/2 implementations are vulnerable to window size
+	// manipulation and stream prioritization manipulation, potentially
+	// leading to a denial of service. The attacker requests a large
+	// amount of data from a specified resource over multiple streams.
+	// They manipulate window size and stream priority to force the
+	// server to queue the data in 1-byte chunks. Depending on how
+	// efficiently this data is queued, this can consume excess CPU,
+	// memory, or both. (CVE-2019-9511)
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends WINDOW_UPDATE frames of 1 byte slowly during the response of a large resource",
+		Requirement: "The endpoint MUST send DATA frames in the window or reset the stream or connection within bounded time.",
+		Timeout:     floodTimeout,
+		Tags:        []string{"needs-resource"},
+		Requires:    []spec.Capability{spec.CapabilityLargeResource},
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			// The server can send only 1 byte on the stream until the
+			// next WINDOW_UPDATE frame.
+			setting := http2.Setting{
+				ID:  http2.SettingInitialWindowSize,
+				Val: 1,
+			}
+
+			err := conn.HandshakeWithSettings(setting)
+			if err != nil {
+				return err
+			}
+			conn.WindowUpdate = false
+
+			headers := spec.ResourceHeaders(c)
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			started := time.Now()
+			lastData := started
+			frames := 0
+			bytes := 0
+			metrics := func() string {
+				elapsed := time.Since(started).Round(time.Millisecond)
+				return fmt.Sprintf("%d DATA frames, %d bytes received in %s", frames, bytes, elapsed)
+			}
+
+			sent := 0
+			for sent < dribbleCount && !conn.Closed {
+				ev := conn.WaitEventWithTimeout(dribbleInterval)
+
+				switch event := ev.(type) {
+				case spec.TimeoutEvent:
+					if time.Since(lastData) > conn.Timeout {
+						return &spec.TestError{
+							Expected: []string{
+								fmt.Sprintf("DATA Frame (stream_id:%d)", streamID),
+								fmt.Sprintf("RST_STREAM Frame (stream_id:%d)", streamID),
+								"GOAWAY Frame",
+							},
+							Actual: fmt.Sprintf("Stalled with %d bytes of window (%s)", sent+1-bytes, metrics()),
+						}
+					}
+					conn.WriteWindowUpdate(streamID, 1)
+					sent++
+				case spec.DataFrameEvent:
+					if event.Header().StreamID != streamID {
+						continue
+					}
+					frames++
+					bytes += int(event.Header().Length)
+					lastData = time.Now()
+
+					if event.StreamEnded() {
+						return &spec.TestInfo{Message: fmt.Sprintf("Response completed (%s)", metrics())}
+					}
+				case spec.RSTStreamFrameEvent:
+					if event.Header().StreamID == streamID {
+						return &spec.TestInfo{Message: fmt.Sprintf("Stream reset: %s (%s)", event, metrics())}
+					}
+				case spec.GoAwayFrameEvent:
+					if event.ErrCode == http2.ErrCodeEnhanceYourCalm || event.ErrCode == http2.ErrCodeNo {
+						return &spec.TestInfo{Message: fmt.Sprintf("Terminated deliberately: %s (%s)", event, metrics())}
+					}
+					return &spec.TestWarning{Reason: fmt.Sprintf("Terminated with error: %s (%s)", event, metrics())}
+				}
+			}
+
+			if conn.Closed {
+				return &spec.TestWarning{Reason: fmt.Sprintf("Connection closed (%s)", metrics())}
+			}
+
+			cadence := time.Duration(0)
+			if frames > 0 {
+				cadence = (time.Since(started) / time.Duration(frames)).Round(time.Millisecond)
+			}
+
+			return &spec.TestInfo{
+				Message: fmt.Sprintf("Kept pace: %d WINDOW_UPDATE frames sent, a DATA frame every %s (%s)", sent, cadence, metrics()),
+			}
+		},
+	})
+
+	return tg
+}
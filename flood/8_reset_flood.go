@@ -0,0 +1,66 @@
+package flood
+
+import (
+	"time"
+
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+	"golang.org/x/net/http2"
+)
+
+func ResetFlood() *spec.TestGroup {
+	tg := NewTestGroup("8", "Reset Flood")
+
+	// Some HTTP/2 implementations are vulnerable to a reset flood,
+	// potentially leading to a denial of service. The attacker opens a
+	// number of streams and sends an invalid request over each stream
+	// that should solicit a stream of RST_STREAM frames from the peer.
+	// Depending on how the peer queues the RST_STREAM frames, this can
+	// consume excess memory, CPU, or both. (CVE-2019-9514)
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends invalid requests on many streams without reading the RST_STREAM frames",
+		Requirement: "The endpoint SHOULD keep serving the connection or close it with GOAWAY frame deliberately.",
+		Timeout:     floodTimeout,
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// The connection-specific header field makes the request
+			// malformed, so that the server resets each stream.
+			headers := spec.CommonHeaders(c)
+			headers = append(headers, spec.HeaderField("connection", "keep-alive"))
+
+			started := time.Now()
+			_, metrics := flood(c, conn, c.FloodStreams, func() error {
+				hp := http2.HeadersFrameParam{
+					StreamID:      streamID,
+					EndStream:     true,
+					EndHeaders:    true,
+					BlockFragment: conn.EncodeHeaders(headers),
+				}
+
+				err := conn.WriteHeaders(hp)
+				if err == nil {
+					streamID += 2
+				}
+				return err
+			})
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(spec.CommonHeaders(c)),
+			}
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyFloodResilience(conn, streamID, started, metrics)
+		},
+	})
+
+	return tg
+}
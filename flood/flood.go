@@ -0,0 +1,84 @@
+package flood
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+)
+
+var key = "flood"
+
+// floodTimeout is the timeout of the test cases that wait for the
+// server to react to the flood, which can take longer than the
+// reaction to a single frame.
+const floodTimeout = 10 * time.Second
+
+func NewTestGroup(section string, name string) *spec.TestGroup {
+	return &spec.TestGroup{
+		Key:     key,
+		Section: section,
+		Name:    name,
+	}
+}
+
+func Spec() *spec.TestGroup {
+	tg := &spec.TestGroup{
+		Key:  key,
+		Name: "Flood Resilience of HTTP/2 Servers",
+		Tags: []string{"flood"},
+	}
+
+	tg.AddTestGroup(RapidReset())
+	tg.AddTestGroup(PingFlood())
+	tg.AddTestGroup(SettingsFlood())
+	tg.AddTestGroup(EmptyFramesFlood())
+	tg.AddTestGroup(ContinuationFlood())
+	tg.AddTestGroup(HPACKBomb())
+	tg.AddTestGroup(DataDribble())
+	tg.AddTestGroup(ResetFlood())
+
+	return tg
+}
+
+// flood sends the frames with write until the specified number of
+// frames are sent or the write fails, without reading any frame. The
+// write deadline is set to the timeout so that the test does not hang
+// when the server stops reading from the connection. It returns the
+// number of frames sent and the metrics of the sending.
+func flood(c *config.Config, conn *spec.Conn, count int, write func() error) (int, string) {
+	conn.SetWriteDeadline(time.Now().Add(c.Timeout))
+	defer conn.SetWriteDeadline(time.Time{})
+
+	started := time.Now()
+
+	sent := 0
+	for ; sent < count; sent++ {
+		err := write()
+		if err != nil {
+			break
+		}
+	}
+
+	elapsed := time.Since(started)
+	rate := float64(sent) / elapsed.Seconds()
+	metrics := fmt.Sprintf("%d/%d frames sent in %s, %.0f frames/sec", sent, count, elapsed.Round(time.Millisecond), rate)
+
+	return sent, metrics
+}
+
+// withMetrics appends the metrics to the observed result of the
+// specified verification result.
+func withMetrics(err error, metrics string) error {
+	switch result := err.(type) {
+	case *spec.TestInfo:
+		result.Message = fmt.Sprintf("%s (%s)", result.Message, metrics)
+	case *spec.TestWarning:
+		result.Reason = fmt.Sprintf("%s (%s)", result.Reason, metrics)
+	case *spec.TestError:
+		result.Actual = fmt.Sprintf("%s (%s)", result.Actual, metrics)
+	}
+
+	return err
+}
@@ -27,18 +27,19 @@ func Continuation() *spec.TestGroup {
 				return err
 			}
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			headerBlock := conn.EncodeHeaders(headers)
 
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    false,
 				BlockFragment: headerBlock[:5],
 			}
 
 			conn.WriteHeaders(hp)
 			conn.WriteContinuation(streamID, true, headerBlock[5:])
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -61,12 +62,12 @@ func Continuation() *spec.TestGroup {
 				return err
 			}
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			headerBlock := conn.EncodeHeaders(headers)
 
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    false,
 				BlockFragment: headerBlock[:5],
 			}
@@ -74,6 +75,7 @@ func Continuation() *spec.TestGroup {
 			conn.WriteHeaders(hp)
 			conn.WriteContinuation(streamID, false, headerBlock[5:10])
 			conn.WriteContinuation(streamID, true, headerBlock[10:])
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
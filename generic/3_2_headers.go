@@ -24,14 +24,15 @@ func Headers() *spec.TestGroup {
 				return err
 			}
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: conn.EncodeHeaders(headers),
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -53,15 +54,16 @@ func Headers() *spec.TestGroup {
 				return err
 			}
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: conn.EncodeHeaders(headers),
 				PadLength:     8,
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -91,15 +93,16 @@ func Headers() *spec.TestGroup {
 				Weight:    255,
 			}
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: conn.EncodeHeaders(headers),
 				Priority:      pp,
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -32,14 +32,15 @@ func Priority() *spec.TestGroup {
 			}
 			conn.WritePriority(streamID, pp)
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: conn.EncodeHeaders(headers),
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -67,14 +68,15 @@ func Priority() *spec.TestGroup {
 			}
 			conn.WritePriority(streamID, pp)
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: conn.EncodeHeaders(headers),
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -101,14 +103,15 @@ func Priority() *spec.TestGroup {
 			}
 			conn.WritePriority(streamID+2, pp)
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: conn.EncodeHeaders(headers),
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -135,14 +138,15 @@ func Priority() *spec.TestGroup {
 			}
 			conn.WritePriority(streamID, pp)
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: conn.EncodeHeaders(headers),
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -171,14 +175,15 @@ func Priority() *spec.TestGroup {
 			}
 			conn.WritePriority(streamID+2, pp)
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: conn.EncodeHeaders(headers),
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
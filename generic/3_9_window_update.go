@@ -50,14 +50,15 @@ func WindowUpdate() *spec.TestGroup {
 				return err
 			}
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: conn.EncodeHeaders(headers),
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			conn.WriteWindowUpdate(streamID, 1)
 
@@ -0,0 +1,236 @@
+package generic
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+func ResponseWellFormedness() *spec.TestGroup {
+	tg := NewTestGroup("4.1", "Response Well-Formedness")
+
+	// RFC7540, 4.3:
+	// A receiver MUST terminate the connection with a connection error
+	// (Section 5.4.1) of type COMPRESSION_ERROR if it does not
+	// decompress a header block.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a GET request and decodes the response header block",
+		Requirement: "The response header block MUST be decoded without error.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			res, _, err := requestResponse(c, conn)
+			if err != nil {
+				return err
+			}
+
+			if res.DecodeError != nil {
+				return &spec.TestError{
+					Expected: []string{"Header block decoded successfully"},
+					Actual:   fmt.Sprintf("Decoding error: %v", res.DecodeError),
+				}
+			}
+
+			return nil
+		},
+	})
+
+	// RFC7540, 8.1.2.4:
+	// For HTTP/2 responses, a single ":status" pseudo-header field is
+	// defined that carries the HTTP status code field (see [RFC7231],
+	// Section 6). This pseudo-header field MUST be included in all
+	// responses; otherwise, the response is malformed (Section 8.1.2.6).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a GET request and verifies the \":status\" pseudo-header field of the response",
+		Requirement: "The response MUST contain exactly one \":status\" pseudo-header field.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			res, _, err := requestResponse(c, conn)
+			if err != nil {
+				return err
+			}
+
+			count := 0
+			for _, f := range res.Headers {
+				if f.Name == ":status" {
+					count += 1
+				}
+			}
+
+			if count != 1 {
+				return &spec.TestError{
+					Expected: []string{":status pseudo-header field (count:1)"},
+					Actual:   fmt.Sprintf(":status pseudo-header field (count:%d)", count),
+				}
+			}
+
+			return nil
+		},
+	})
+
+	// RFC7540, 8.1.2.1:
+	// All pseudo-header fields MUST appear in the header block before
+	// regular header fields. Any request or response that contains a
+	// pseudo-header field that appears in a header block after a
+	// regular header field MUST be treated as malformed
+	// (Section 8.1.2.6).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a GET request and verifies the order of the response header fields",
+		Requirement: "The pseudo-header fields MUST appear before regular header fields.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			res, _, err := requestResponse(c, conn)
+			if err != nil {
+				return err
+			}
+
+			regular := false
+			for _, f := range res.Headers {
+				if !strings.HasPrefix(f.Name, ":") {
+					regular = true
+					continue
+				}
+
+				if regular {
+					return &spec.TestError{
+						Expected: []string{"Pseudo-header fields before regular header fields"},
+						Actual:   fmt.Sprintf("Header fields (%s)", fieldNames(res.Headers)),
+					}
+				}
+			}
+
+			return nil
+		},
+	})
+
+	// RFC7540, 8.1.2:
+	// However, header field names MUST be converted to lowercase prior
+	// to their encoding in HTTP/2. A request or response containing
+	// uppercase header field names MUST be treated as malformed
+	// (Section 8.1.2.6).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a GET request and verifies the case of the response header field names",
+		Requirement: "The header field names MUST be in lowercase.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			res, _, err := requestResponse(c, conn)
+			if err != nil {
+				return err
+			}
+
+			for _, f := range append(res.Headers, res.Trailers...) {
+				if f.Name != strings.ToLower(f.Name) {
+					return &spec.TestError{
+						Expected: []string{"Header fields in lowercase"},
+						Actual:   fmt.Sprintf("Header field (name:%s)", f.Name),
+					}
+				}
+			}
+
+			return nil
+		},
+	})
+
+	// RFC7540, 8.1.2.2:
+	// HTTP/2 does not use the Connection header field to indicate
+	// connection-specific header fields; in this protocol,
+	// connection-specific metadata is conveyed by other means. An
+	// endpoint MUST NOT generate an HTTP/2 message containing
+	// connection-specific header fields; any message containing
+	// connection-specific header fields MUST be treated as malformed
+	// (Section 8.1.2.6).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a GET request and verifies the response has no connection-specific header fields",
+		Requirement: "The response MUST NOT contain connection-specific header fields.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			res, _, err := requestResponse(c, conn)
+			if err != nil {
+				return err
+			}
+
+			for _, f := range append(res.Headers, res.Trailers...) {
+				switch strings.ToLower(f.Name) {
+				case "connection", "keep-alive", "proxy-connection", "transfer-encoding", "upgrade":
+					return &spec.TestError{
+						Expected: []string{"No connection-specific header fields"},
+						Actual:   fmt.Sprintf("Header field (name:%s, value:%s)", f.Name, f.Value),
+					}
+				}
+			}
+
+			return nil
+		},
+	})
+
+	// RFC7540, 8.1:
+	// The last frame in the sequence bears an END_STREAM flag, noting
+	// that a HEADERS frame bearing the END_STREAM flag can be followed
+	// by CONTINUATION frames that carry any remaining portions of the
+	// header block.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a GET request and waits for the end of the response",
+		Requirement: "The last frame of the response MUST bear an END_STREAM flag.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			res, actual, err := requestResponse(c, conn)
+			if err != nil {
+				return err
+			}
+
+			if !res.Ended {
+				return &spec.TestError{
+					Expected: []string{
+						fmt.Sprintf("DATA Frame (flags:0x01, stream_id:%d)", res.StreamID),
+						fmt.Sprintf("HEADERS Frame (flags:0x05, stream_id:%d)", res.StreamID),
+					},
+					Actual: actual.String(),
+				}
+			}
+
+			return nil
+		},
+	})
+
+	return tg
+}
+
+// requestResponse sends a GET request to the target path and reads
+// the response. An error is returned if no response header block is
+// received.
+func requestResponse(c *config.Config, conn *spec.Conn) (*spec.Response, spec.Event, error) {
+	var streamID uint32 = 1
+
+	err := conn.Handshake()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	headers := spec.CommonHeaders(c)
+	hp := http2.HeadersFrameParam{
+		StreamID:      streamID,
+		EndStream:     true,
+		EndHeaders:    true,
+		BlockFragment: conn.EncodeHeaders(headers),
+	}
+	conn.WriteHeaders(hp)
+
+	res, actual := spec.ReadResponse(conn, streamID)
+	if res.Headers == nil {
+		return nil, nil, &spec.TestError{
+			Expected: []string{
+				fmt.Sprintf("HEADERS Frame (stream_id:%d)", streamID),
+			},
+			Actual: actual.String(),
+		}
+	}
+
+	return res, actual, nil
+}
+
+// fieldNames returns the names of the header fields joined with
+// a comma.
+func fieldNames(fields []hpack.HeaderField) string {
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		names = append(names, f.Name)
+	}
+	return strings.Join(names, ", ")
+}
@@ -26,7 +26,7 @@ func HTTPMessageExchanges() *spec.TestGroup {
 				return err
 			}
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeadersWithMethod(c, "GET")
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
 				EndStream:     true,
@@ -55,8 +55,7 @@ func HTTPMessageExchanges() *spec.TestGroup {
 				return err
 			}
 
-			headers := spec.CommonHeaders(c)
-			headers[0].Value = "HEAD"
+			headers := spec.RequestHeadersWithMethod(c, "HEAD")
 
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
@@ -87,8 +86,7 @@ func HTTPMessageExchanges() *spec.TestGroup {
 				return err
 			}
 
-			headers := spec.CommonHeaders(c)
-			headers[0].Value = "POST"
+			headers := spec.RequestHeadersWithMethod(c, "POST")
 
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
@@ -120,8 +118,7 @@ func HTTPMessageExchanges() *spec.TestGroup {
 				return err
 			}
 
-			headers := spec.CommonHeaders(c)
-			headers[0].Value = "POST"
+			headers := spec.RequestHeadersWithMethod(c, "POST")
 			headers = append(headers, spec.HeaderField("trailer", "x-test"))
 
 			hp1 := http2.HeadersFrameParam{
@@ -151,5 +148,7 @@ func HTTPMessageExchanges() *spec.TestGroup {
 		},
 	})
 
+	tg.AddTestGroup(ResponseWellFormedness())
+
 	return tg
 }
@@ -27,17 +27,18 @@ func HPACK() *spec.TestGroup {
 			// (user-agent: )
 			rep := []byte("\xba")
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			blockFragment := conn.EncodeHeaders(headers)
 			blockFragment = append(blockFragment, rep...)
 
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: blockFragment,
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -62,17 +63,18 @@ func HPACK() *spec.TestGroup {
 			// (user-agent: h2spec)
 			rep := []byte("\x40\x87\xb5\x05\xb1\x61\xcc\x5a\x93\x84\x9c\x48\xac\xa4")
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			blockFragment := conn.EncodeHeaders(headers)
 			blockFragment = append(blockFragment, rep...)
 
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: blockFragment,
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -97,17 +99,18 @@ func HPACK() *spec.TestGroup {
 			// (user-agent: h2spec)
 			rep := []byte("\x40\x0a\x75\x73\x65\x72\x2d\x61\x67\x65\x6e\x74\x06\x68\x32\x73\x70\x65\x63")
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			blockFragment := conn.EncodeHeaders(headers)
 			blockFragment = append(blockFragment, rep...)
 
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: blockFragment,
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -132,17 +135,18 @@ func HPACK() *spec.TestGroup {
 			// (x-test: h2spec)
 			rep := []byte("\x40\x06\x78\x2d\x74\x65\x73\x74\x06\x68\x32\x73\x70\x65\x63")
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			blockFragment := conn.EncodeHeaders(headers)
 			blockFragment = append(blockFragment, rep...)
 
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: blockFragment,
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -167,17 +171,18 @@ func HPACK() *spec.TestGroup {
 			// (x-test: h2spec)
 			rep := []byte("\x40\x85\xf2\xb2\x4a\x84\xff\x84\x9c\x48\xac\xa4")
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			blockFragment := conn.EncodeHeaders(headers)
 			blockFragment = append(blockFragment, rep...)
 
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: blockFragment,
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -202,17 +207,18 @@ func HPACK() *spec.TestGroup {
 			// (user-agent: h2spec)
 			rep := []byte("\x00\x0a\x75\x73\x65\x72\x2d\x61\x67\x65\x6e\x74\x06\x68\x32\x73\x70\x65\x63")
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			blockFragment := conn.EncodeHeaders(headers)
 			blockFragment = append(blockFragment, rep...)
 
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: blockFragment,
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -237,17 +243,18 @@ func HPACK() *spec.TestGroup {
 			// (user-agent: h2spec)
 			rep := []byte("\x00\x87\xb5\x05\xb1\x61\xcc\x5a\x93\x84\x9c\x48\xac\xa4")
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			blockFragment := conn.EncodeHeaders(headers)
 			blockFragment = append(blockFragment, rep...)
 
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: blockFragment,
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -272,17 +279,18 @@ func HPACK() *spec.TestGroup {
 			// (x-test: h2spec)
 			rep := []byte("\x00\x06\x78\x2d\x74\x65\x73\x74\x06\x68\x32\x73\x70\x65\x63")
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			blockFragment := conn.EncodeHeaders(headers)
 			blockFragment = append(blockFragment, rep...)
 
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: blockFragment,
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -307,17 +315,18 @@ func HPACK() *spec.TestGroup {
 			// (x-test: h2spec)
 			rep := []byte("\x00\x85\xf2\xb2\x4a\x84\xff\x84\x9c\x48\xac\xa4")
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			blockFragment := conn.EncodeHeaders(headers)
 			blockFragment = append(blockFragment, rep...)
 
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: blockFragment,
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -343,17 +352,18 @@ func HPACK() *spec.TestGroup {
 			// (user-agent: h2spec)
 			rep := []byte("\x10\x0a\x75\x73\x65\x72\x2d\x61\x67\x65\x6e\x74\x06\x68\x32\x73\x70\x65\x63")
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			blockFragment := conn.EncodeHeaders(headers)
 			blockFragment = append(blockFragment, rep...)
 
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: blockFragment,
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -379,17 +389,18 @@ func HPACK() *spec.TestGroup {
 			// (user-agent: h2spec)
 			rep := []byte("\x10\x87\xb5\x05\xb1\x61\xcc\x5a\x93\x84\x9c\x48\xac\xa4")
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			blockFragment := conn.EncodeHeaders(headers)
 			blockFragment = append(blockFragment, rep...)
 
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: blockFragment,
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -415,17 +426,18 @@ func HPACK() *spec.TestGroup {
 			// (x-test: h2spec)
 			rep := []byte("\x10\x85\xf2\xb2\x4a\x84\xff\x84\x9c\x48\xac\xa4")
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			blockFragment := conn.EncodeHeaders(headers)
 			blockFragment = append(blockFragment, rep...)
 
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: blockFragment,
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -451,17 +463,18 @@ func HPACK() *spec.TestGroup {
 			// (x-test: h2spec)
 			rep := []byte("\x10\x85\xf2\xb2\x4a\x84\xff\x84\x9c\x48\xac\xa4")
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			blockFragment := conn.EncodeHeaders(headers)
 			blockFragment = append(blockFragment, rep...)
 
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: blockFragment,
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -485,16 +498,17 @@ func HPACK() *spec.TestGroup {
 			// size update with value 128.
 			conn.SetMaxDynamicTableSize(128)
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			blockFragment := conn.EncodeHeaders(headers)
 
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: blockFragment,
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -529,17 +543,18 @@ func HPACK() *spec.TestGroup {
 			// 2 Dynamic Table Size Updates, 128 and 4096.
 			tableSizeUpdate := []byte("\x3f\x61\x3f\xe1\x1f")
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			blockFragment := conn.EncodeHeaders(headers)
 			blockFragment = append(tableSizeUpdate, blockFragment...)
 
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    true,
 				BlockFragment: blockFragment,
 			}
 			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -1,68 +1,223 @@
 package h2spec
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/summerwind/h2spec/client"
 	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/flood"
 	"github.com/summerwind/h2spec/generic"
 	"github.com/summerwind/h2spec/hpack"
 	"github.com/summerwind/h2spec/http2"
 	"github.com/summerwind/h2spec/log"
 	"github.com/summerwind/h2spec/reporter"
+	"github.com/summerwind/h2spec/rfc7838"
+	"github.com/summerwind/h2spec/rfc8336"
+	"github.com/summerwind/h2spec/rfc8441"
+	"github.com/summerwind/h2spec/rfc8446"
+	"github.com/summerwind/h2spec/rfc9113"
+	"github.com/summerwind/h2spec/rfc9218"
+	"github.com/summerwind/h2spec/slow"
 	"github.com/summerwind/h2spec/spec"
 )
 
+// InterruptedError is returned by RunContext when the run has been
+// cancelled before all the test cases are run.
+type InterruptedError struct {
+	// Tests is the number of test cases completed before the run
+	// was cancelled.
+	Tests int
+
+	// ReportError is the error of writing the reports of the run, or
+	// nil if they have been written.
+	ReportError error
+}
+
+func (e *InterruptedError) Error() string {
+	return withReportError(fmt.Sprintf("interrupted after %d tests", e.Tests), e.ReportError)
+}
+
+// RunTimeoutError is returned by RunContext when the run has been
+// aborted by the run timeout of the configuration.
+type RunTimeoutError struct {
+	// Tests is the number of test cases completed before the run
+	// was aborted.
+	Tests int
+
+	// NotAttempted is the number of the selected test cases that
+	// were not run.
+	NotAttempted int
+
+	// ReportError is the error of writing the reports of the run, or
+	// nil if they have been written.
+	ReportError error
+}
+
+func (e *RunTimeoutError) Error() string {
+	msg := fmt.Sprintf("aborted: run timeout after %d tests, %d tests not attempted", e.Tests, e.NotAttempted)
+	return withReportError(msg, e.ReportError)
+}
+
+// withReportError appends the error of writing the reports to the
+// message of the aborted run.
+func withReportError(msg string, err error) string {
+	if err == nil {
+		return msg
+	}
+	return fmt.Sprintf("%s (%v)", msg, err)
+}
+
+// abortRunTimeout is the reason of the abort by the run timeout.
+const abortRunTimeout = "run timeout"
+
+// Run runs the test cases of the server specs.
 func Run(c *config.Config) (bool, error) {
-	total := 0
+	return RunContext(context.Background(), c)
+}
+
+// RunContext runs the test cases of the server specs until the context
+// is done. The progress is reported to the console, the reports
+// specified by the configuration and the specified reporters. When the
+// run is cancelled, the connection of the running test case is closed
+// and the report of the completed test cases is still written, then
+// InterruptedError is returned. The run is also aborted in the same way
+// when the run timeout of the configuration expires, then
+// RunTimeoutError is returned. Either of them carries the error of
+// writing the reports, if any.
+func RunContext(ctx context.Context, c *config.Config, reporters ...spec.Reporter) (bool, error) {
 	success := true
 
+	// The run timeout covers the capability detection as well, and the
+	// timeouts of the test cases remain in force within it.
+	parent := ctx
+	if c.RunTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.RunTimeout)
+		defer cancel()
+	}
+
+	// Any failed test case fails the run unless the minimum severity
+	// is specified.
+	minSeverity := spec.SeverityLow
+	if c.FailOnSeverity != "" {
+		s, err := spec.ParseSeverity(c.FailOnSeverity)
+		if err != nil {
+			return false, err
+		}
+		minSeverity = s
+	}
+
+	// The formats of the output files are checked before the run.
+	var outputs *reporter.OutputReporter
+	if len(c.Outputs) > 0 && !c.DryRun {
+		var err error
+		outputs, err = reporter.NewOutputReporter(c, c.Outputs)
+		if err != nil {
+			return false, err
+		}
+	}
+
 	specs := []*spec.TestGroup{
 		generic.Spec(),
 		http2.Spec(),
 		hpack.Spec(),
+		rfc9113.Spec(),
+		rfc9218.Spec(),
+		rfc7838.Spec(),
+		rfc8336.Spec(),
+		rfc8441.Spec(),
+		rfc8446.Spec(c),
+		flood.Spec(),
+		slow.Spec(),
+	}
+
+	if c.EventLog != "" {
+		err := spec.OpenEventLog(c.EventLog)
+		if err != nil {
+			return false, err
+		}
+		defer spec.CloseEventLog()
+	}
+
+	// A failed detection skips only the test cases that require the
+	// capabilities of the server, so the run continues.
+	var caps *spec.Capabilities
+	if !c.DryRun {
+		caps, _ = spec.DetectCapabilities(ctx, c)
+	}
+
+	rs := spec.Reporters{reporter.NewConsoleReporter(c)}
+	if c.JUnitReport != "" && !c.DryRun {
+		rs = append(rs, reporter.NewJUnitReporter(c, c.JUnitReport))
+	}
+	if c.CSVReport != "" && !c.DryRun {
+		rs = append(rs, reporter.NewCSVReporter(c, c.CSVReport))
 	}
+	if outputs != nil {
+		rs = append(rs, outputs)
+	}
+	rs = append(rs, reporters...)
+
+	rs.RunStarted(specs)
 
 	start := time.Now()
 	for _, s := range specs {
-		s.Test(c)
+		if ctx.Err() != nil {
+			break
+		}
 
-		if s.FailedCount > 0 {
+		s.Test(ctx, c, rs)
+
+		if s.FailedCountBySeverity(minSeverity) > 0 {
 			success = false
 		}
 
-		total += s.FailedCount
-		total += s.SkippedCount
-		total += s.PassedCount
+		if c.FailOnInconclusive && s.InconclusiveCount > 0 {
+			success = false
+		}
+
+		// A panicked test case has not verified the server.
+		if s.ErroredCount > 0 {
+			success = false
+		}
 	}
 	end := time.Now()
-	d := end.Sub(start)
 
-	if c.DryRun {
-		return true, nil
+	summary := &spec.RunSummary{
+		Groups:      specs,
+		Duration:    end.Sub(start),
+		Interrupted: ctx.Err() != nil,
 	}
-
-	if total == 0 {
-		log.SetIndentLevel(0)
-		log.Println("No matched tests found.")
-		return true, nil
+	if caps != nil {
+		summary.Address = caps.Address
+	}
+	if summary.Interrupted {
+		for _, s := range specs {
+			summary.NotAttempted += s.NotAttemptedCount(c)
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && parent.Err() == nil {
+			summary.AbortReason = abortRunTimeout
+		}
+		spec.LogRunAborted(summary)
 	}
 
-	if !success {
-		log.SetIndentLevel(0)
-		reporter.FailedTests(specs)
+	// The abort of the run is returned even if the reports fail to be
+	// written, so that the caller can tell how the run ended.
+	err := rs.RunFinished(summary)
+
+	if summary.AbortReason == abortRunTimeout {
+		return false, &RunTimeoutError{Tests: summary.Total(), NotAttempted: summary.NotAttempted, ReportError: err}
 	}
 
-	log.SetIndentLevel(0)
-	log.Println(fmt.Sprintf("Finished in %.4f seconds", d.Seconds()))
-	reporter.Summary(specs)
+	if summary.Interrupted {
+		return false, &InterruptedError{Tests: summary.Total(), ReportError: err}
+	}
 
-	if c.JUnitReport != "" {
-		err := reporter.JUnitReport(specs, c.JUnitReport)
-		if err != nil {
-			return false, err
-		}
+	if err != nil {
+		return false, err
 	}
 
 	return success, nil
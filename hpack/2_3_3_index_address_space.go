@@ -41,5 +41,72 @@ func IndexAddressSpace() *spec.TestGroup {
 		},
 	})
 
+	// Indices strictly greater than the sum of the lengths of both
+	// tables MUST be treated as a decoding error.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends an indexed header field representation with index 70 on an empty dynamic table",
+		Requirement: "The endpoint MUST treat this as a decoding error.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// Indexed header field representation with index 70.
+			// The static table has 61 entries and the dynamic table is
+			// empty at the beginning of the first header block.
+			blockFragment := spec.IndexedHeaderField(70)
+
+			headers := spec.CommonHeaders(c)
+			blockFragment = append(blockFragment, conn.EncodeHeaders(headers)...)
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: blockFragment,
+			}
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeCompression)
+		},
+	})
+
+	// Indices strictly greater than the sum of the lengths of both
+	// tables MUST be treated as a decoding error.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends an indexed header field representation with the index next to the dynamic table entry",
+		Requirement: "The endpoint MUST treat this as a decoding error.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// Literal header field with incremental indexing adds an entry
+			// at index 62, then the indexed header field representation
+			// refers to index 63 which is one past the dynamic table.
+			blockFragment := spec.LiteralHeaderFieldWithIndexing("x-test", "ok")
+			blockFragment = append(blockFragment, spec.IndexedHeaderField(63)...)
+
+			headers := spec.CommonHeaders(c)
+			blockFragment = append(blockFragment, conn.EncodeHeaders(headers)...)
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: blockFragment,
+			}
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeCompression)
+		},
+	})
+
 	return tg
 }
@@ -45,5 +45,190 @@ func MaximumTableSize() *spec.TestGroup {
 		},
 	})
 
+	// A change in the maximum size of the dynamic table is signaled
+	// via a dynamic table size update (see Section 6.3). This dynamic
+	// table size update MUST occur at the beginning of the first
+	// header block following the change to the dynamic table size.
+	// In HTTP/2, this follows a settings acknowledgment (see Section
+	// 6.5.3 of [HTTP2]).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends SETTINGS_HEADER_TABLE_SIZE with 0 and requests twice",
+		Requirement: "The endpoint MUST NOT use the dynamic table for the header blocks.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			setting := http2.Setting{
+				ID:  http2.SettingHeaderTableSize,
+				Val: 0,
+			}
+			conn.WriteSettings(setting)
+
+			err = spec.VerifySettingsFrameWithAck(conn)
+			if err != nil {
+				return err
+			}
+
+			conn.SetDecoderMaxDynamicTableSize(0)
+
+			headers := spec.CommonHeaders(c)
+			for _, streamID := range []uint32{1, 3} {
+				hp := http2.HeadersFrameParam{
+					StreamID:      streamID,
+					EndStream:     true,
+					EndHeaders:    true,
+					BlockFragment: conn.EncodeHeaders(headers),
+				}
+				conn.WriteHeaders(hp)
+
+				err = spec.VerifyHeaderBlockDecoding(conn, streamID, streamID == 1)
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	})
+
+	// A change in the maximum size of the dynamic table is signaled
+	// via a dynamic table size update (see Section 6.3). This dynamic
+	// table size update MUST occur at the beginning of the first
+	// header block following the change to the dynamic table size.
+	// In HTTP/2, this follows a settings acknowledgment (see Section
+	// 6.5.3 of [HTTP2]).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a dynamic table size update in the middle of header block",
+		Requirement: "The endpoint MUST treat this as a decoding error.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			maxTableSize, ok := conn.Settings[http2.SettingHeaderTableSize]
+			if !ok {
+				maxTableSize = uint32(4096)
+			}
+			tableSizeUpdate := spec.DynamicTableSizeUpdate(uint64(maxTableSize))
+
+			headers := spec.CommonHeaders(c)
+			// The literal header field is added to the dynamic table
+			// before the dynamic table size update.
+			blockFragment := spec.LiteralHeaderFieldWithIndexing(headers[3].Name, headers[3].Value)
+			blockFragment = append(blockFragment, tableSizeUpdate...)
+			blockFragment = append(blockFragment, conn.EncodeHeaders(headers[:3])...)
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: blockFragment,
+			}
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeCompression)
+		},
+	})
+
+	// A change in the maximum size of the dynamic table is signaled
+	// via a dynamic table size update (see Section 6.3). This dynamic
+	// table size update MUST occur at the beginning of the first
+	// header block following the change to the dynamic table size.
+	// In HTTP/2, this follows a settings acknowledgment (see Section
+	// 6.5.3 of [HTTP2]).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a dynamic table size update at the beginning of header block",
+		Requirement: "The endpoint MUST accept the dynamic table size update.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			maxTableSize, ok := conn.Settings[http2.SettingHeaderTableSize]
+			if !ok {
+				maxTableSize = uint32(4096)
+			}
+			tableSizeUpdate := spec.DynamicTableSizeUpdate(uint64(maxTableSize))
+
+			headers := spec.CommonHeaders(c)
+			blockFragment := tableSizeUpdate
+			blockFragment = append(blockFragment, conn.EncodeHeaders(headers)...)
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: blockFragment,
+			}
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyStreamResponse(conn, streamID)
+		},
+	})
+
+	// RFC7540, 6.5.2:
+	// SETTINGS_MAX_HEADER_LIST_SIZE (0x6): This advisory setting
+	// informs a peer of the maximum size of header list that the
+	// sender is prepared to accept, in octets. The value is based on
+	// the uncompressed size of header fields, including the length of
+	// the name and value in octets plus an overhead of 32 octets for
+	// each header field.
+	//
+	// Note: This test case advertises SETTINGS_MAX_HEADER_LIST_SIZE
+	// together with SETTINGS_HEADER_TABLE_SIZE of 0, and expects each
+	// response header block to decode without the dynamic table and
+	// within the advertised header list size.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends SETTINGS_HEADER_TABLE_SIZE with 0 and SETTINGS_MAX_HEADER_LIST_SIZE in the preface and requests several times",
+		Requirement: "The endpoint MUST encode the header blocks within the advertised limits.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var maxHeaderListSize uint32 = 8192
+
+			settings := []http2.Setting{
+				http2.Setting{
+					ID:  http2.SettingHeaderTableSize,
+					Val: 0,
+				},
+				http2.Setting{
+					ID:  http2.SettingMaxHeaderListSize,
+					Val: maxHeaderListSize,
+				},
+			}
+
+			conn.SetDecoderMaxHeaderListSize(maxHeaderListSize)
+
+			err := conn.HandshakeWithSettings(settings...)
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			for _, streamID := range []uint32{1, 3, 5} {
+				hp := http2.HeadersFrameParam{
+					StreamID:      streamID,
+					EndStream:     true,
+					EndHeaders:    true,
+					BlockFragment: conn.EncodeHeaders(headers),
+				}
+				conn.WriteHeaders(hp)
+
+				err = spec.VerifyHeaderBlockDecoding(conn, streamID, false)
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	})
+
 	return tg
 }
@@ -0,0 +1,52 @@
+package hpack
+
+import (
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+	"golang.org/x/net/http2"
+)
+
+func IntegerRepresentation() *spec.TestGroup {
+	tg := NewTestGroup("5.1", "Integer Representation")
+
+	// If the integer value is small enough, i.e., strictly less than
+	// 2^N-1, it is encoded within the N-bit prefix.
+	//
+	// Otherwise, all the bits of the prefix are set to 1, and the value,
+	// decreased by 2^N-1, is encoded using a list of one or more octets.
+	// The most significant bit of each octet is used as a continuation
+	// flag: its value is set to 1 except for the last octet in the list.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a header block ending with an incomplete integer representation",
+		Requirement: "The endpoint MUST treat this as a decoding error.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// Indexed Header Field Representation
+			// All the bits of the prefix are set to 1, but the
+			// following octets are missing.
+			rep := []byte("\xff")
+
+			headers := spec.CommonHeaders(c)
+			blockFragment := conn.EncodeHeaders(headers)
+			blockFragment = append(blockFragment, rep...)
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: blockFragment,
+			}
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeCompression)
+		},
+	})
+
+	return tg
+}
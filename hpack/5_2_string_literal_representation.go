@@ -141,5 +141,129 @@ func StringLiteralRepresentation() *spec.TestGroup {
 		},
 	})
 
+	// As the Huffman-encoded data doesn't always end at an octet boundary,
+	// some padding is inserted after it, up to the next octet boundary.  To
+	// prevent this padding from being misinterpreted as part of the string
+	// literal, the most significant bits of the code corresponding to the
+	// EOS (end-of-string) symbol are used.
+	//
+	// Upon decoding, an incomplete code at the end of the encoded data is
+	// to be considered as padding and discarded.  A padding strictly longer
+	// than 7 bits MUST be treated as a decoding error.  A padding not
+	// corresponding to the most significant bits of the code for the EOS
+	// symbol MUST be treated as a decoding error.  A Huffman-encoded string
+	// literal containing the EOS symbol MUST be treated as a decoding
+	// error.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a Huffman-encoded string literal representation with the EOS symbol at the end",
+		Requirement: "The endpoint MUST treat this as a decoding error.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// Literal Header Field without Indexing - New Name (x-test: test)
+			// This contains a EOS symbol at the end, after "test "
+			rep := []byte("\x00\x85\xf2\xb2\x4a\x84\xff\x87\x49\x50\x95\x3f\xff\xff\xff")
+
+			headers := spec.CommonHeaders(c)
+			blockFragment := conn.EncodeHeaders(headers)
+			blockFragment = append(blockFragment, rep...)
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: blockFragment,
+			}
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeCompression)
+		},
+	})
+
+	// As the Huffman-encoded data doesn't always end at an octet boundary,
+	// some padding is inserted after it, up to the next octet boundary.  To
+	// prevent this padding from being misinterpreted as part of the string
+	// literal, the most significant bits of the code corresponding to the
+	// EOS (end-of-string) symbol are used.
+	//
+	// Upon decoding, an incomplete code at the end of the encoded data is
+	// to be considered as padding and discarded.  A padding strictly longer
+	// than 7 bits MUST be treated as a decoding error.  A padding not
+	// corresponding to the most significant bits of the code for the EOS
+	// symbol MUST be treated as a decoding error.  A Huffman-encoded string
+	// literal containing the EOS symbol MUST be treated as a decoding
+	// error.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a Huffman-encoded string literal representation padded by bits not matching the EOS prefix",
+		Requirement: "The endpoint MUST treat this as a decoding error.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// Literal Header Field without Indexing - New Name (x-test: test)
+			// This is padded by "1110" instead of "1111"
+			rep := []byte("\x00\x85\xf2\xb2\x4a\x84\xff\x83\x49\x50\x9e")
+
+			headers := spec.CommonHeaders(c)
+			blockFragment := conn.EncodeHeaders(headers)
+			blockFragment = append(blockFragment, rep...)
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: blockFragment,
+			}
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeCompression)
+		},
+	})
+
+	// String Length:  The number of octets used to encode the string
+	// literal, encoded as an integer with a 7-bit prefix (see
+	// Section 5.1).
+	//
+	// String Data:  The encoded data of the string literal.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a string literal representation with the length exceeding the header block",
+		Requirement: "The endpoint MUST treat this as a decoding error.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// Literal Header Field without Indexing - New Name (x-test: test)
+			// The length of value is 10, but only 4 octets are present
+			rep := []byte("\x00\x06x-test\x0atest")
+
+			headers := spec.CommonHeaders(c)
+			blockFragment := conn.EncodeHeaders(headers)
+			blockFragment = append(blockFragment, rep...)
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: blockFragment,
+			}
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeCompression)
+		},
+	})
+
 	return tg
 }
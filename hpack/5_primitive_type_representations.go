@@ -5,6 +5,7 @@ import "github.com/summerwind/h2spec/spec"
 func PrimitiveTypeRepresentations() *spec.TestGroup {
 	tg := NewTestGroup("5", "Primitive Type Representations")
 
+	tg.AddTestGroup(IntegerRepresentation())
 	tg.AddTestGroup(StringLiteralRepresentation())
 
 	return tg
@@ -41,5 +41,36 @@ func IndexedHeaderFieldRepresentation() *spec.TestGroup {
 		},
 	})
 
+	// The index value of 0 is not used.  It MUST be treated as a decoding
+	// error if found in an indexed header field representation.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a indexed header field representation with index 0 at the beginning of header block",
+		Requirement: "The endpoint MUST treat this as a decoding error.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// Indexed Header Field Representation
+			blockFragment := []byte("\x80")
+
+			headers := spec.CommonHeaders(c)
+			blockFragment = append(blockFragment, conn.EncodeHeaders(headers)...)
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: blockFragment,
+			}
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeCompression)
+		},
+	})
+
 	return tg
 }
@@ -31,23 +31,7 @@ func DynamicTableSizeUpdate() *spec.TestGroup {
 				maxTableSize = uint32(4096)
 			}
 
-			tableSize := uint64(maxTableSize + 1)
-			rep := []byte{}
-
-			// Encode to dynamic table size update.
-			// This code is from golang.org/x/net/http2.
-			k := uint64((1 << 5) - 1)
-			if tableSize < k {
-				rep = append(rep, byte(tableSize))
-			} else {
-				rep = append(rep, byte(k))
-				tableSize -= k
-				for ; tableSize >= 128; tableSize >>= 7 {
-					rep = append(rep, byte(0x80|(tableSize&0x7f)))
-				}
-				rep = append(rep, byte(tableSize))
-			}
-			rep[0] |= 0x20
+			rep := spec.DynamicTableSizeUpdate(uint64(maxTableSize + 1))
 
 			headers := spec.CommonHeaders(c)
 			blockFragment := conn.EncodeHeaders(headers)
@@ -65,5 +49,53 @@ func DynamicTableSizeUpdate() *spec.TestGroup {
 		},
 	})
 
+	// The new maximum size MUST be lower than or equal to the limit
+	// determined by the protocol using HPACK.  A value that exceeds this
+	// limit MUST be treated as a decoding error.  In HTTP/2, this limit is
+	// the last value of the SETTINGS_HEADER_TABLE_SIZE parameter (see
+	// Section 6.5.2 of [HTTP2]) received from the decoder and acknowledged
+	// by the encoder (see Section 6.5.3 of [HTTP2]).
+	//
+	// Note: SETTINGS_HEADER_TABLE_SIZE sent by h2spec limits the encoder
+	// of the server, so it does not affect the limit of this update.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends SETTINGS_HEADER_TABLE_SIZE with 256 and a dynamic table size update equal to the value of the server",
+		Requirement: "The endpoint MUST accept the dynamic table size update.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			setting := http2.Setting{
+				ID:  http2.SettingHeaderTableSize,
+				Val: 256,
+			}
+
+			err := conn.HandshakeWithSettings(setting)
+			if err != nil {
+				return err
+			}
+
+			maxTableSize, ok := conn.Settings[http2.SettingHeaderTableSize]
+			if !ok {
+				maxTableSize = uint32(4096)
+			}
+
+			rep := spec.DynamicTableSizeUpdate(uint64(maxTableSize))
+
+			headers := spec.CommonHeaders(c)
+			blockFragment := conn.EncodeHeaders(headers)
+			blockFragment = append(rep, blockFragment...)
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: blockFragment,
+			}
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyStreamResponse(conn, streamID)
+		},
+	})
+
 	return tg
 }
@@ -16,6 +16,7 @@ func Spec() *spec.TestGroup {
 	tg := &spec.TestGroup{
 		Key:  key,
 		Name: "HPACK: Header Compression for HTTP/2",
+		Tags: []string{"hpack"},
 	}
 
 	tg.AddTestGroup(CompressionProcessOverview())
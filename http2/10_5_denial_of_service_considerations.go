@@ -0,0 +1,153 @@
+package http2
+
+import (
+	"fmt"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+)
+
+func DenialOfServiceConsiderations() *spec.TestGroup {
+	tg := NewTestGroup("10.5", "Denial-of-Service Considerations")
+
+	// A server that receives a larger header block than it is willing
+	// to handle can send an HTTP 431 (Request Header Fields Too Large)
+	// status code [RFC6585]. A client can discard responses that it
+	// cannot process. The header block MUST be processed to ensure a
+	// consistent connection state, unless the connection is closed.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a header field exceeding SETTINGS_MAX_HEADER_LIST_SIZE",
+		Requirement: "The endpoint MUST keep processing the connection or close it with a connection error.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// The size of a header field is the sum of its name length,
+			// its value length and 32 octets, so the value itself
+			// exceeds the limit.
+			maxHeaderListSize, ok := conn.Settings[http2.SettingMaxHeaderListSize]
+			if !ok {
+				maxHeaderListSize = uint32(65536)
+			}
+			if maxHeaderListSize > maxOversizedHeaderList {
+				return &spec.TestSkipped{
+					Reason: fmt.Sprintf("SETTINGS_MAX_HEADER_LIST_SIZE of %d octets is too large to exceed", maxHeaderListSize),
+				}
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers = append(headers, spec.HeaderField("x-dummy", spec.DummyString(int(maxHeaderListSize))))
+			conn.WriteHeaderBlock(streamID, true, conn.EncodeHeaders(headers))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID + 2,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(spec.CommonHeaders(c)),
+			}
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyOversizedHeaderList(conn, streamID, streamID+2)
+		},
+	})
+
+	// SETTINGS_MAX_HEADER_LIST_SIZE (0x6): This advisory setting
+	// informs a peer of the maximum size of header list that the
+	// sender is prepared to accept, in octets.
+	//
+	// Note: This test case sends many small header fields whose size
+	// stays just under the advertised limit, or 8KB if it is not
+	// advertised, and expects a response other than 431 to catch a
+	// lower limit hidden by the server. The header list is capped at
+	// 64KB. Since the setting is advisory, a 431 for a header list
+	// larger than 8KB is reported as a warning.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends header fields just under SETTINGS_MAX_HEADER_LIST_SIZE",
+		Requirement: "The endpoint MUST accept the header list within the advertised limit.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			maxHeaderListSize, ok := conn.Settings[http2.SettingMaxHeaderListSize]
+			if !ok {
+				maxHeaderListSize = uint32(8192)
+			}
+
+			limit := int(maxHeaderListSize)
+			if limit > maxHeaderListProbe {
+				limit = maxHeaderListProbe
+			}
+
+			headers, size := headerListUnder(spec.CommonHeaders(c), limit)
+			conn.WriteHeaderBlock(streamID, true, conn.EncodeHeaders(headers))
+
+			res, actual := spec.ReadResponse(conn, streamID)
+			if res.Headers == nil {
+				return &spec.TestError{
+					Expected: []string{fmt.Sprintf("HEADERS Frame (stream_id:%d, :status:xxx)", streamID)},
+					Actual:   actual.String(),
+				}
+			}
+			if res.Status() == "431" && size > minHeaderListSize {
+				return &spec.TestWarning{
+					Reason: fmt.Sprintf("HEADERS Frame (stream_id:%d, :status:431) for %d octets in %d fields (limit:%d)", streamID, size, len(headers), maxHeaderListSize),
+				}
+			}
+			if res.Status() == "431" {
+				return &spec.TestError{
+					Expected: []string{fmt.Sprintf("HEADERS Frame (stream_id:%d) with :status other than 431", streamID)},
+					Actual:   fmt.Sprintf("HEADERS Frame (stream_id:%d, :status:431) for %d octets", streamID, size),
+				}
+			}
+
+			return &spec.TestInfo{
+				Message: fmt.Sprintf("Sent %d octets of header list in %d fields (limit:%d)", size, len(headers), maxHeaderListSize),
+			}
+		},
+	})
+
+	return tg
+}
+
+// maxOversizedHeaderList is the largest SETTINGS_MAX_HEADER_LIST_SIZE
+// that the test case exceeds with a header field. maxHeaderListProbe
+// caps the header list sent under the advertised limit, so that a huge
+// limit such as 2^32-1 does not exhaust the memory, and a 431 for a
+// header list up to minHeaderListSize fails the test case.
+const (
+	maxOversizedHeaderList = 16 << 20
+	maxHeaderListProbe     = 64 << 10
+	minHeaderListSize      = 8 << 10
+)
+
+// headerListUnder appends small header fields to the headers while the
+// size of the header list stays under the limit. It returns the header
+// fields and the size of the header list.
+func headerListUnder(headers []hpack.HeaderField, limit int) ([]hpack.HeaderField, int) {
+	size := 0
+	for _, hf := range headers {
+		size += int(hf.Size())
+	}
+
+	for i := 0; ; i++ {
+		hf := spec.HeaderField(fmt.Sprintf("x-dummy%d", i), spec.DummyString(16))
+		if size+int(hf.Size()) >= limit {
+			break
+		}
+		headers = append(headers, hf)
+		size += int(hf.Size())
+	}
+
+	return headers, size
+}
@@ -0,0 +1,11 @@
+package http2
+
+import "github.com/summerwind/h2spec/spec"
+
+func SecurityConsiderations() *spec.TestGroup {
+	tg := NewTestGroup("10", "Security Considerations")
+
+	tg.AddTestGroup(DenialOfServiceConsiderations())
+
+	return tg
+}
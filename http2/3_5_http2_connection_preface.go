@@ -66,5 +66,54 @@ func HTTP2ConnectionPreface() *spec.TestGroup {
 		},
 	})
 
+	// The client connection preface starts with a sequence of 24
+	// octets [...]. This sequence MUST be followed by a SETTINGS frame
+	// (Section 6.5), which MAY be empty.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends client connection preface with an empty SETTINGS frame",
+		Requirement: "The endpoint MUST accept the empty SETTINGS frame of the client connection preface.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			err := conn.Send([]byte("PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n"))
+			if err != nil {
+				return err
+			}
+
+			err = conn.WriteSettings()
+			if err != nil {
+				return err
+			}
+
+			return spec.ExpectFrame(conn, spec.SettingsAck())
+		},
+	})
+
+	// This sequence MUST be followed by a SETTINGS frame (Section 6.5),
+	// which MAY be empty. [...] Clients and servers MUST treat an
+	// invalid connection preface as a connection error (Section 5.4.1)
+	// of type PROTOCOL_ERROR.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends HEADERS frame before SETTINGS frame of client connection preface",
+		Requirement: "The endpoint MUST treat this as a connection error of type PROTOCOL_ERROR.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Send([]byte("PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n"))
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			return spec.ExpectFrame(conn, spec.ConnectionError(http2.ErrCodeProtocol))
+		},
+	})
+
 	return tg
 }
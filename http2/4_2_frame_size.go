@@ -72,7 +72,7 @@ func FrameSize() *spec.TestGroup {
 			data := spec.DummyString(conn.MaxFrameSize() + 1)
 			conn.WriteData(streamID, true, []byte(data))
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeFrameSize)
+			return spec.VerifyStreamErrorOnStream(conn, streamID, http2.ErrCodeFrameSize)
 		},
 	})
 
@@ -68,5 +68,85 @@ func StreamIdentifiers() *spec.TestGroup {
 		},
 	})
 
+	// Stream identifiers cannot be reused. Long-lived connections can
+	// result in an endpoint exhausting the available range of stream
+	// identifiers.
+	//
+	// Note: The stream identifiers need not be used sequentially, so
+	// the request on the maximum stream identifier is valid.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends HEADERS frame with the maximum stream identifier",
+		Requirement: "The endpoint MUST respond to the request.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = maxStreamID
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.RequestHeaders(c)
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     !spec.HasRequestBody(c),
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
+
+			return spec.VerifyHeadersFrame(conn, streamID)
+		},
+	})
+
+	// An endpoint that receives an unexpected stream identifier
+	// MUST respond with a connection error (Section 5.4.1) of
+	// type PROTOCOL_ERROR.
+	//
+	// Note: No new stream can be opened after the maximum stream
+	// identifier, so any other stream identifier is numerically
+	// smaller than previous.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends HEADERS frame with a lower stream identifier after the maximum stream identifier",
+		Requirement: "The endpoint MUST respond with a connection error of type PROTOCOL_ERROR.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = maxStreamID
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.RequestHeaders(c)
+			hp1 := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     !spec.HasRequestBody(c),
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp1)
+			spec.WriteRequestBody(conn, c, streamID)
+
+			err = spec.VerifyHeadersFrame(conn, streamID)
+			if err != nil {
+				return err
+			}
+
+			hp2 := http2.HeadersFrameParam{
+				StreamID:      1,
+				EndStream:     !spec.HasRequestBody(c),
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp2)
+			spec.WriteRequestBody(conn, c, 1)
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeProtocol)
+		},
+	})
+
 	return tg
 }
+
+// maxStreamID is the maximum stream identifier, 2^31-1.
+const maxStreamID = 1<<31 - 1
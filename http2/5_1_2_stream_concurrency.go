@@ -58,5 +58,41 @@ func StreamConcurrency() *spec.TestGroup {
 		},
 	})
 
+	// A single HTTP/2 connection can contain multiple concurrently open
+	// streams, with either endpoint interleaving frames from multiple
+	// streams.
+	//
+	// Note: This test case opens the streams with HEADERS frames, and
+	// then ends them with DATA frames in reverse order. Each response
+	// has to be complete on its own stream.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends requests on multiple concurrent streams",
+		Requirement: "The endpoint MUST send a complete response on each stream.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			streamIDs := []uint32{1, 3, 5, 7, 9}
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			for i, streamID := range streamIDs {
+				hp := http2.HeadersFrameParam{
+					StreamID:      streamID,
+					EndStream:     false,
+					EndHeaders:    true,
+					BlockFragment: conn.EncodeHeaders(spec.MultiplexHeaders(c, i)),
+				}
+				conn.WriteHeaders(hp)
+			}
+
+			for i := len(streamIDs) - 1; i >= 0; i-- {
+				conn.WriteData(streamIDs[i], true, []byte{})
+			}
+
+			return spec.VerifyMultiplexedResponses(conn, streamIDs...)
+		},
+	})
+
 	return tg
 }
@@ -1,6 +1,8 @@
 package http2
 
 import (
+	"fmt"
+
 	"github.com/summerwind/h2spec/config"
 	"github.com/summerwind/h2spec/spec"
 	"golang.org/x/net/http2"
@@ -9,6 +11,12 @@ import (
 func StreamStates() *spec.TestGroup {
 	tg := NewTestGroup("5.1", "Stream States")
 
+	// Note: A CONTINUATION frame that is not preceded by a HEADERS
+	// frame without END_HEADERS flag is also a connection error of
+	// type PROTOCOL_ERROR (Section 6.10), so the CONTINUATION cases on
+	// the half-closed (remote) and closed streams accept PROTOCOL_ERROR
+	// as well as STREAM_CLOSED.
+
 	// idle:
 	// Receiving any frame other than HEADERS or PRIORITY on a stream
 	// in this state MUST be treated as a connection error
@@ -114,7 +122,7 @@ func StreamStates() *spec.TestGroup {
 			conn.WriteHeaders(hp)
 			conn.WriteData(streamID, true, []byte("test"))
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeStreamClosed)
+			return spec.VerifyStreamErrorOnStream(conn, streamID, http2.ErrCodeStreamClosed)
 		},
 	})
 
@@ -152,7 +160,7 @@ func StreamStates() *spec.TestGroup {
 			}
 			conn.WriteHeaders(hp2)
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeStreamClosed)
+			return spec.VerifyStreamErrorOnStream(conn, streamID, http2.ErrCodeStreamClosed)
 		},
 	})
 
@@ -185,7 +193,7 @@ func StreamStates() *spec.TestGroup {
 			conn.WriteHeaders(hp)
 			conn.WriteContinuation(streamID, true, blockFragment)
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeStreamClosed, http2.ErrCodeProtocol)
+			return spec.VerifyStreamErrorOnStream(conn, streamID, http2.ErrCodeStreamClosed, http2.ErrCodeProtocol)
 		},
 	})
 
@@ -217,7 +225,7 @@ func StreamStates() *spec.TestGroup {
 
 			conn.WriteData(streamID, true, []byte("test"))
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeStreamClosed)
+			return spec.VerifyStreamErrorOnStream(conn, streamID, http2.ErrCodeStreamClosed)
 		},
 	})
 
@@ -247,6 +255,17 @@ func StreamStates() *spec.TestGroup {
 
 			conn.WriteRSTStream(streamID, http2.ErrCodeCancel)
 
+			actual, err := spec.ExpectFrameEvent(conn, spec.RSTStream(streamID, http2.ErrCodeProtocol))
+			if err != nil {
+				switch actual.(type) {
+				case spec.GoAwayFrameEvent, spec.ConnectionClosedEvent:
+					return err
+				}
+				return &spec.TestSkipped{
+					Reason: fmt.Sprintf("RST_STREAM frame is not observed on stream %d (actual:%s)", streamID, actual),
+				}
+			}
+
 			hp2 := http2.HeadersFrameParam{
 				StreamID:      streamID,
 				EndStream:     true,
@@ -255,7 +274,7 @@ func StreamStates() *spec.TestGroup {
 			}
 			conn.WriteHeaders(hp2)
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeStreamClosed)
+			return spec.VerifyStreamErrorOnStream(conn, streamID, http2.ErrCodeStreamClosed)
 		},
 	})
 
@@ -292,7 +311,7 @@ func StreamStates() *spec.TestGroup {
 				http2.ErrCodeStreamClosed,
 				http2.ErrCodeProtocol,
 			}
-			return spec.VerifyStreamError(conn, codes...)
+			return spec.VerifyStreamErrorOnStream(conn, streamID, codes...)
 		},
 	})
 
@@ -327,7 +346,7 @@ func StreamStates() *spec.TestGroup {
 
 			conn.WriteData(streamID, true, []byte("test"))
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeStreamClosed)
+			return spec.VerifyStreamErrorOnStream(conn, streamID, http2.ErrCodeStreamClosed)
 		},
 	})
 
@@ -406,6 +425,72 @@ func StreamStates() *spec.TestGroup {
 		},
 	})
 
+	// closed:
+	// If this state is reached as a result of sending a RST_STREAM
+	// frame, the peer that receives the RST_STREAM might have already
+	// sent -- or enqueued for sending -- frames on the stream that
+	// cannot be withdrawn. An endpoint MUST ignore frames that it
+	// receives on closed streams after it has sent a RST_STREAM frame.
+	//
+	// Note: This test case sends a request body exceeding its
+	// content-length to make the endpoint reset the stream, and sends
+	// WINDOW_UPDATE, PRIORITY and RST_STREAM frames on the stream
+	// before reading the RST_STREAM frame. The endpoint must not treat
+	// them as a connection error, which is confirmed by a response to
+	// the next request on the same connection. This test case is
+	// skipped if the endpoint does not reset the stream, since the
+	// frames do not race with RST_STREAM frame then.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "closed: Sends frames on the stream racing with RST_STREAM frame of the endpoint",
+		Requirement: "The endpoint MUST ignore frames received after it has sent a RST_STREAM frame.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// The DATA frame exceeding the content-length makes the
+			// request malformed after the stream is opened.
+			headers := spec.CommonHeaders(c)
+			headers[0].Value = "POST"
+			headers = append(headers, spec.HeaderField("content-length", "1"))
+			hp1 := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp1)
+			conn.WriteData(streamID, true, []byte("test"))
+
+			conn.WriteWindowUpdate(streamID, 1)
+			pp := http2.PriorityParam{
+				StreamDep: 0,
+				Exclusive: false,
+				Weight:    255,
+			}
+			conn.WritePriority(streamID, pp)
+			conn.WriteRSTStream(streamID, http2.ErrCodeCancel)
+
+			actual, err := spec.ExpectFrameEvent(conn, spec.RSTStream(streamID, http2.ErrCodeProtocol))
+			if err != nil {
+				switch actual.(type) {
+				case spec.GoAwayFrameEvent, spec.ConnectionClosedEvent:
+					return err
+				}
+				return &spec.TestSkipped{
+					Reason: fmt.Sprintf("RST_STREAM frame is not observed on stream %d (actual:%s)", streamID, actual),
+				}
+			}
+
+			spec.WriteRequest(conn, c, streamID+2)
+
+			return spec.VerifyStreamResponse(conn, streamID+2)
+		},
+	})
+
 	tg.AddTestGroup(StreamIdentifiers())
 	tg.AddTestGroup(StreamConcurrency())
 
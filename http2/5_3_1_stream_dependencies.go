@@ -11,9 +11,14 @@ func StreamDependencies() *spec.TestGroup {
 
 	// A stream cannot depend on itself. An endpoint MUST treat this
 	// as a stream error (Section 5.4.2) of type PROTOCOL_ERROR.
+	//
+	// Note: RFC 9113 deprecates the priority signaling of RFC 7540 and
+	// drops this requirement, so that the endpoint may also ignore the
+	// stream dependency in the profile of RFC 9113.
 	tg.AddTestCase(&spec.TestCase{
-		Desc:        "Sends HEADERS frame that depend on itself",
-		Requirement: "The endpoint MUST treat this as a stream error of type PROTOCOL_ERROR.",
+		Desc:             "Sends HEADERS frame that depend on itself",
+		Requirement:      "The endpoint MUST treat this as a stream error of type PROTOCOL_ERROR.",
+		ProfileDependent: true,
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 
@@ -36,15 +41,25 @@ func StreamDependencies() *spec.TestGroup {
 			}
 			conn.WriteHeaders(hp)
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeProtocol)
+			if spec.ActiveProfile(c) == spec.ProfileRFC9113 {
+				data := [8]byte{'h', '2', 's', 'p', 'e', 'c'}
+				conn.WritePing(false, data)
+
+				return spec.VerifyStreamErrorOrPingFrame(conn, data, http2.ErrCodeProtocol)
+			}
+
+			return spec.VerifyStreamErrorOnStream(conn, streamID, http2.ErrCodeProtocol)
 		},
 	})
 
 	// A stream cannot depend on itself. An endpoint MUST treat this
 	// as a stream error (Section 5.4.2) of type PROTOCOL_ERROR.
+	//
+	// Note: This requirement is dropped in RFC 9113 as well.
 	tg.AddTestCase(&spec.TestCase{
-		Desc:        "Sends PRIORITY frame that depend on itself",
-		Requirement: "The endpoint MUST treat this as a stream error of type PROTOCOL_ERROR.",
+		Desc:             "Sends PRIORITY frame that depend on itself",
+		Requirement:      "The endpoint MUST treat this as a stream error of type PROTOCOL_ERROR.",
+		ProfileDependent: true,
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 
@@ -60,7 +75,14 @@ func StreamDependencies() *spec.TestGroup {
 			}
 			conn.WritePriority(streamID, priorityParam)
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeProtocol)
+			if spec.ActiveProfile(c) == spec.ProfileRFC9113 {
+				data := [8]byte{'h', '2', 's', 'p', 'e', 'c'}
+				conn.WritePing(false, data)
+
+				return spec.VerifyStreamErrorOrPingFrame(conn, data, http2.ErrCodeProtocol)
+			}
+
+			return spec.VerifyStreamErrorOnStream(conn, streamID, http2.ErrCodeProtocol)
 		},
 	})
 
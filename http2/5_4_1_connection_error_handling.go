@@ -62,5 +62,77 @@ func ConnectionErrorHandling() *spec.TestGroup {
 		},
 	})
 
+	// An endpoint that encounters a connection error SHOULD first send
+	// a GOAWAY frame (Section 6.8) with the stream identifier of the last
+	// stream that it successfully received from its peer. The GOAWAY
+	// frame includes an error code that indicates why the connection is
+	// terminating.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends an invalid SETTINGS frame after processing streams to receive GOAWAY frame with the last stream ID",
+		Requirement: "The GOAWAY frame SHOULD contain the identifier of the last processed stream and the error code.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			for _, streamID := range []uint32{1, 3} {
+				hp := http2.HeadersFrameParam{
+					StreamID:      streamID,
+					EndStream:     true,
+					EndHeaders:    true,
+					BlockFragment: conn.EncodeHeaders(headers),
+				}
+				conn.WriteHeaders(hp)
+
+				err = spec.VerifyStreamClose(conn)
+				if err != nil {
+					return err
+				}
+			}
+
+			// SETTINGS frame:
+			// length: 3, flags: 0x0, stream_id: 0x0
+			conn.Send([]byte("\x00\x00\x03\x04\x00\x00\x00\x00\x00"))
+			conn.Send([]byte("\x00\x03\x00"))
+
+			return spec.VerifyGoAwayFrame(conn, 3, http2.ErrCodeFrameSize)
+		},
+	})
+
+	// After sending the GOAWAY frame for an error condition,
+	// the endpoint MUST close the TCP connection.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends frames after an invalid PING frame",
+		Requirement: "The endpoint MUST NOT process the frames sent after the connection error.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// PING frame with invalid stream ID
+			conn.Send([]byte("\x00\x00\x08\x06\x00\x00\x00\x00\x03"))
+			conn.Send([]byte("\x00\x00\x00\x00\x00\x00\x00\x00"))
+
+			data := [8]byte{'h', '2', 's', 'p', 'e', 'c'}
+			conn.WritePing(false, data)
+
+			headers := spec.CommonHeaders(c)
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyConnectionErrorWithoutResponse(conn, streamID, http2.ErrCodeProtocol)
+		},
+	})
+
 	return tg
 }
@@ -63,6 +63,9 @@ func ExtendingHTTP2() *spec.TestGroup {
 			conn.Send([]byte("\x00\x00\x08\x16\x00\x00\x00\x00\x00"))
 			conn.Send([]byte("\x00\x00\x00\x00\x00\x00\x00\x00"))
 
+			dummyHeaders := spec.DummyHeaders(c, 1)
+			conn.WriteContinuation(streamID, true, conn.EncodeHeaders(dummyHeaders))
+
 			return spec.VerifyConnectionError(conn, http2.ErrCodeProtocol)
 		},
 	})
@@ -26,10 +26,10 @@ func Continuation() *spec.TestGroup {
 				return err
 			}
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     !spec.HasRequestBody(c),
 				EndHeaders:    false,
 				BlockFragment: conn.EncodeHeaders(headers),
 			}
@@ -38,6 +38,7 @@ func Continuation() *spec.TestGroup {
 			dummyHeaders := spec.DummyHeaders(c, 1)
 			conn.WriteContinuation(streamID, false, conn.EncodeHeaders(dummyHeaders))
 			conn.WriteContinuation(streamID, true, conn.EncodeHeaders(dummyHeaders))
+			spec.WriteRequestBody(conn, c, streamID)
 
 			return spec.VerifyHeadersFrame(conn, streamID)
 		},
@@ -207,5 +208,186 @@ func Continuation() *spec.TestGroup {
 		},
 	})
 
+	// A CONTINUATION frame MUST be preceded by a HEADERS, PUSH_PROMISE
+	// or CONTINUATION frame without the END_HEADERS flag set.
+	// A recipient that observes violation of this rule MUST respond
+	// with a connection error (Section 5.4.1) of type PROTOCOL_ERROR.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a CONTINUATION frame without a preceding HEADERS frame",
+		Requirement: "The endpoint MUST respond with a connection error of type PROTOCOL_ERROR.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			blockFragment := conn.EncodeHeaders(headers)
+			conn.WriteRawFrame(http2.FrameContinuation, http2.FlagContinuationEndHeaders, streamID, blockFragment)
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeProtocol)
+		},
+	})
+
+	// CONTINUATION frames MUST be associated with a stream. If a
+	// CONTINUATION frame is received whose stream identifier field is
+	// 0x0, the recipient MUST respond with a connection error
+	// (Section 5.4.1) of type PROTOCOL_ERROR.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a CONTINUATION frame with 0x0 stream identifier without a preceding HEADERS frame",
+		Requirement: "The endpoint MUST respond with a connection error of type PROTOCOL_ERROR.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			blockFragment := conn.EncodeHeaders(headers)
+			conn.WriteRawFrame(http2.FrameContinuation, http2.FlagContinuationEndHeaders, 0, blockFragment)
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeProtocol)
+		},
+	})
+
+	// A CONTINUATION frame MUST be preceded by a HEADERS, PUSH_PROMISE
+	// or CONTINUATION frame without the END_HEADERS flag set.
+	// A recipient that observes violation of this rule MUST respond
+	// with a connection error (Section 5.4.1) of type PROTOCOL_ERROR.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a CONTINUATION frame preceded by a HEADERS frame with END_HEADERS flag on an open stream",
+		Requirement: "The endpoint MUST respond with a connection error of type PROTOCOL_ERROR.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers[0].Value = "POST"
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			dummyHeaders := spec.DummyHeaders(c, 1)
+			conn.WriteContinuation(streamID, true, conn.EncodeHeaders(dummyHeaders))
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeProtocol)
+		},
+	})
+
+	// A CONTINUATION frame MUST be preceded by a HEADERS, PUSH_PROMISE
+	// or CONTINUATION frame without the END_HEADERS flag set.
+	// A recipient that observes violation of this rule MUST respond
+	// with a connection error (Section 5.4.1) of type PROTOCOL_ERROR.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a CONTINUATION frame preceded by a DATA frame after a HEADERS frame with END_HEADERS flag",
+		Requirement: "The endpoint MUST respond with a connection error of type PROTOCOL_ERROR.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers[0].Value = "POST"
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+			conn.WriteData(streamID, false, []byte("test"))
+
+			dummyHeaders := spec.DummyHeaders(c, 1)
+			conn.WriteContinuation(streamID, true, conn.EncodeHeaders(dummyHeaders))
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeProtocol)
+		},
+	})
+
+	// END_HEADERS (0x4):
+	// If the END_HEADERS bit is not set, this frame MUST be followed
+	// by another CONTINUATION frame. A receiver MUST treat the receipt
+	// of any other type of frame or a frame on a different stream as
+	// a connection error (Section 5.4.1) of type PROTOCOL_ERROR.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a PING frame between a HEADERS frame and a CONTINUATION frame",
+		Requirement: "The endpoint MUST treat this as a connection error of type PROTOCOL_ERROR.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    false,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			data := [8]byte{'h', '2', 's', 'p', 'e', 'c'}
+			conn.WritePing(false, data)
+
+			dummyHeaders := spec.DummyHeaders(c, 1)
+			conn.WriteContinuation(streamID, true, conn.EncodeHeaders(dummyHeaders))
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeProtocol)
+		},
+	})
+
+	// END_HEADERS (0x4):
+	// If the END_HEADERS bit is not set, this frame MUST be followed
+	// by another CONTINUATION frame. A receiver MUST treat the receipt
+	// of any other type of frame or a frame on a different stream as
+	// a connection error (Section 5.4.1) of type PROTOCOL_ERROR.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a DATA frame between a HEADERS frame and a CONTINUATION frame",
+		Requirement: "The endpoint MUST treat this as a connection error of type PROTOCOL_ERROR.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers[0].Value = "POST"
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    false,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+			conn.WriteData(streamID, false, []byte("test"))
+
+			dummyHeaders := spec.DummyHeaders(c, 1)
+			conn.WriteContinuation(streamID, true, conn.EncodeHeaders(dummyHeaders))
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeProtocol)
+		},
+	})
+
 	return tg
 }
@@ -1,6 +1,8 @@
 package http2
 
 import (
+	"fmt"
+
 	"golang.org/x/net/http2"
 
 	"github.com/summerwind/h2spec/config"
@@ -58,7 +60,7 @@ func Data() *spec.TestGroup {
 			conn.WriteHeaders(hp)
 			conn.WriteData(streamID, true, []byte("test"))
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeStreamClosed)
+			return spec.VerifyStreamErrorOnStream(conn, streamID, http2.ErrCodeStreamClosed)
 		},
 	})
 
@@ -98,5 +100,280 @@ func Data() *spec.TestGroup {
 		},
 	})
 
+	// DATA frames MUST be associated with a stream. If a DATA frame is
+	// received whose stream identifier field is 0x0, the recipient
+	// MUST respond with a connection error (Section 5.4.1) of type
+	// PROTOCOL_ERROR.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a DATA frame with 0x0 stream identifier and no payload",
+		Requirement: "The endpoint MUST respond with a connection error of type PROTOCOL_ERROR.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// DATA frame:
+			// length: 0, flags: 0x1, stream_id: 0
+			conn.Send([]byte("\x00\x00\x00\x00\x01\x00\x00\x00\x00"))
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeProtocol)
+		},
+	})
+
+	// If the length of the padding is the length of the frame payload
+	// or greater, the recipient MUST treat this as a connection error
+	// (Section 5.4.1) of type PROTOCOL_ERROR.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a DATA frame with pad length equal to the frame payload length",
+		Requirement: "The endpoint MUST treat this as a connection error of type PROTOCOL_ERROR.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers[0].Value = "POST"
+			headers = append(headers, spec.HeaderField("content-length", "4"))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+
+			// DATA frame:
+			// frame length: 5, pad length: 5
+			conn.Send([]byte("\x00\x00\x05\x00\x09\x00\x00\x00\x01"))
+			conn.Send([]byte("\x05\x54\x65\x73\x74"))
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeProtocol)
+		},
+	})
+
+	// DATA frames MAY also contain padding. The Pad Length field
+	// gives the length of the frame padding in units of octets.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a DATA frame with maximum pad length",
+		Requirement: "The endpoint MUST accept DATA frame with padding.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers[0].Value = "POST"
+			headers = append(headers, spec.HeaderField("content-length", "4"))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+			conn.WriteDataPadded(streamID, true, []byte("test"), make([]byte, 255))
+
+			return spec.VerifyHeadersFrame(conn, streamID)
+		},
+	})
+
+	// The entire DATA frame payload is included in flow control,
+	// including the Pad Length and Padding fields if present.
+	//
+	// Note: This test case sends DATA frames with maximum padding on
+	// the requests until most of the connection window is consumed,
+	// and expects WINDOW_UPDATE frames on the connection crediting
+	// the full frame payloads. Then the request that exceeds the rest
+	// of the window must be accepted. This test case is skipped when
+	// the stream window or SETTINGS_MAX_CONCURRENT_STREAMS does not
+	// allow the requests.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends DATA frames with maximum pad length consuming most of the connection window",
+		Requirement: "The endpoint MUST send WINDOW_UPDATE frames including the padding in flow control.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			_, window := conn.SendWindow(0)
+			if window > maxConsumedWindow {
+				return &spec.TestSkipped{
+					Reason: fmt.Sprintf("connection window of %d octets is too large to consume", window),
+				}
+			}
+
+			// Each request must carry at least the payload of the last
+			// request, which bounds the number of streams opened.
+			streamWindow, _ := conn.SendWindow(streamID)
+			if streamWindow < paddedDataSize {
+				return &spec.TestSkipped{
+					Reason: fmt.Sprintf("stream window of %d octets is too small to send DATA frames", streamWindow),
+				}
+			}
+
+			// Most of the connection window is consumed, leaving less
+			// than the payload of the last request.
+			consumed := window - paddedDataSize/2
+			streams := (consumed+streamWindow-1)/streamWindow + 1
+			maxStreams, ok := conn.Settings[http2.SettingMaxConcurrentStreams]
+			if ok && uint32(streams) > maxStreams {
+				return &spec.TestSkipped{
+					Reason: fmt.Sprintf("%d concurrent streams are required but SETTINGS_MAX_CONCURRENT_STREAMS is %d", streams, maxStreams),
+				}
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers[0].Value = "POST"
+
+			sent := 0
+			for ; sent < consumed; streamID += 2 {
+				hp := http2.HeadersFrameParam{
+					StreamID:      streamID,
+					EndStream:     false,
+					EndHeaders:    true,
+					BlockFragment: conn.EncodeHeaders(headers),
+				}
+				conn.WriteHeaders(hp)
+
+				size := streamWindow
+				if rest := consumed - sent; size > rest {
+					size = rest
+				}
+				sent += writePaddedData(conn, streamID, size)
+			}
+
+			err = spec.ExpectFrame(conn, spec.WindowUpdate(0, sent))
+			if err != nil {
+				return err
+			}
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+			writePaddedData(conn, streamID, paddedDataSize)
+
+			return spec.ExpectFrame(conn, spec.Headers(streamID))
+		},
+	})
+
+	// END_STREAM (0x1): When set, bit 0 indicates that this frame is
+	// the last that the endpoint will send for the identified stream.
+	//
+	// Note: A DATA frame with no payload is used only to carry the
+	// END_STREAM flag after the request body.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a DATA frame with no payload and END_STREAM flag after a DATA frame",
+		Requirement: "The endpoint MUST accept DATA frame with no payload ending the stream.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers[0].Value = "POST"
+			headers = append(headers, spec.HeaderField("content-length", "4"))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+			conn.WriteData(streamID, false, []byte("test"))
+			conn.WriteData(streamID, true, []byte{})
+
+			return spec.VerifyHeadersFrame(conn, streamID)
+		},
+	})
+
+	// END_STREAM (0x1): When set, bit 0 indicates that this frame is
+	// the last that the endpoint will send for the identified stream.
+	//
+	// Note: The request has no body, but the stream is ended by a DATA
+	// frame with no payload instead of the HEADERS frame.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a DATA frame with no payload and END_STREAM flag right after HEADERS frame with content-length of 0",
+		Requirement: "The endpoint MUST accept DATA frame with no payload ending the stream.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers[0].Value = "POST"
+			headers = append(headers, spec.HeaderField("content-length", "0"))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+			conn.WriteData(streamID, true, []byte{})
+
+			return spec.VerifyHeadersFrame(conn, streamID)
+		},
+	})
+
 	return tg
 }
+
+// maxConsumedWindow is the maximum size of the connection window that
+// the test case consumes, and paddedDataSize is the payload size of
+// the DATA frames with maximum padding it sends.
+const (
+	maxConsumedWindow = 16 << 20
+	paddedDataSize    = spec.DefaultFrameSize
+)
+
+// writePaddedData sends DATA frames with maximum padding whose payloads
+// sum up to the specified size on the stream, ending the stream with
+// the last one. It returns the sum of the payload sizes.
+func writePaddedData(conn *spec.Conn, streamID uint32, size int) int {
+	sent := 0
+	for sent < size {
+		n := size - sent
+		if n > paddedDataSize {
+			n = paddedDataSize
+		}
+
+		padLen := 255
+		if n <= padLen {
+			padLen = n - 1
+		}
+
+		data := []byte(spec.DummyString(n - 1 - padLen))
+		conn.WriteDataPadded(streamID, sent+n == size, data, make([]byte, padLen))
+		sent += n
+	}
+
+	return sent
+}
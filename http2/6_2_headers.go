@@ -110,15 +110,12 @@ func Headers() *spec.TestGroup {
 			}
 
 			headers := spec.CommonHeaders(c)
+			blockFragment := conn.EncodeHeaders(headers)
 
-			hp := http2.HeadersFrameParam{
-				StreamID:      0,
-				EndStream:     true,
-				EndHeaders:    true,
-				BlockFragment: conn.EncodeHeaders(headers),
-			}
-
-			conn.WriteHeaders(hp)
+			// HEADERS frame:
+			// flags: END_STREAM | END_HEADERS, stream_id: 0
+			flags := http2.FlagHeadersEndStream | http2.FlagHeadersEndHeaders
+			conn.WriteRawFrame(http2.FrameHeaders, flags, 0, blockFragment)
 
 			return spec.VerifyConnectionError(conn, http2.ErrCodeProtocol)
 		},
@@ -149,7 +146,116 @@ func Headers() *spec.TestGroup {
 			conn.Send([]byte{byte(len(blockFragment) + 2)})
 			conn.Send(blockFragment)
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeProtocol)
+			return spec.VerifyStreamErrorOnStream(conn, 1, http2.ErrCodeProtocol)
+		},
+	})
+
+	// The HEADERS frame can include padding. Padding fields and flags
+	// are identical to those defined for DATA frames (Section 6.1).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame with padding",
+		Requirement: "The endpoint MUST accept HEADERS frame with padding.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.RequestHeaders(c)
+			blockFragment := conn.EncodeHeaders(headers)
+
+			// HEADERS frame payload:
+			// pad length: 8, header block fragment, padding: 8 octets
+			padLen := 8
+			payload := []byte{byte(padLen)}
+			payload = append(payload, blockFragment...)
+			payload = append(payload, make([]byte, padLen)...)
+
+			flags := http2.FlagHeadersEndHeaders | http2.FlagHeadersPadded
+			if !spec.HasRequestBody(c) {
+				flags |= http2.FlagHeadersEndStream
+			}
+			conn.WriteRawFrame(http2.FrameHeaders, flags, streamID, payload)
+			spec.WriteRequestBody(conn, c, streamID)
+
+			return spec.VerifyHeadersFrame(conn, streamID)
+		},
+	})
+
+	// The HEADERS frame payload has the following fields:
+	// Pad Length, E, Stream Dependency, Weight, Header Block Fragment
+	// and Padding. The Pad Length field is present only if the PADDED
+	// flag is set, and the E, Stream Dependency and Weight fields are
+	// present only if the PRIORITY flag is set.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame with both PADDED and PRIORITY flags",
+		Requirement: "The endpoint MUST accept HEADERS frame with padding and priority.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.RequestHeaders(c)
+			blockFragment := conn.EncodeHeaders(headers)
+
+			// HEADERS frame payload:
+			// pad length: 8, exclusive: 1, stream dependency: 0,
+			// weight: 255, header block fragment, padding: 8 octets
+			padLen := 8
+			payload := []byte{byte(padLen)}
+			payload = append(payload, 0x80, 0x00, 0x00, 0x00, 0xff)
+			payload = append(payload, blockFragment...)
+			payload = append(payload, make([]byte, padLen)...)
+
+			flags := http2.FlagHeadersEndHeaders | http2.FlagHeadersPadded | http2.FlagHeadersPriority
+			if !spec.HasRequestBody(c) {
+				flags |= http2.FlagHeadersEndStream
+			}
+			conn.WriteRawFrame(http2.FrameHeaders, flags, streamID, payload)
+			spec.WriteRequestBody(conn, c, streamID)
+
+			return spec.VerifyHeadersFrame(conn, streamID)
+		},
+	})
+
+	// Pad Length: An 8-bit field containing the length of the frame
+	// padding in units of octets. This field is only present if the
+	// PADDED flag is set.
+	//
+	// Note: The pad length of 0 is valid, which makes the frame have
+	// the Pad Length field without padding.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame with the PADDED flag and a pad length of 0",
+		Requirement: "The endpoint MUST accept HEADERS frame with a pad length of 0.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.RequestHeaders(c)
+			blockFragment := conn.EncodeHeaders(headers)
+
+			// HEADERS frame payload:
+			// pad length: 0, header block fragment
+			payload := []byte{0x00}
+			payload = append(payload, blockFragment...)
+
+			flags := http2.FlagHeadersEndHeaders | http2.FlagHeadersPadded
+			if !spec.HasRequestBody(c) {
+				flags |= http2.FlagHeadersEndStream
+			}
+			conn.WriteRawFrame(http2.FrameHeaders, flags, streamID, payload)
+			spec.WriteRequestBody(conn, c, streamID)
+
+			return spec.VerifyHeadersFrame(conn, streamID)
 		},
 	})
 
@@ -62,7 +62,42 @@ func Priority() *spec.TestGroup {
 			conn.Send([]byte("\x00\x00\x04\x02\x00\x00\x00\x00\x01"))
 			conn.Send([]byte("\x80\x00\x00\x01"))
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeFrameSize)
+			return spec.VerifyStreamErrorOrConnectionError(conn, http2.ErrCodeFrameSize)
+		},
+	})
+
+	// The PRIORITY frame specifies the sender-advised priority of
+	// a stream (Section 5.3). It can be sent in any stream state,
+	// including idle or closed streams.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a PRIORITY frame with a stream identifier other than 0x0",
+		Requirement: "The endpoint MUST accept PRIORITY frame.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			pp := http2.PriorityParam{
+				StreamDep: 0,
+				Exclusive: false,
+				Weight:    255,
+			}
+			conn.WritePriority(streamID, pp)
+
+			headers := spec.RequestHeaders(c)
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     !spec.HasRequestBody(c),
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
+
+			return spec.VerifyHeadersFrame(conn, streamID)
 		},
 	})
 
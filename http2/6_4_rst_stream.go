@@ -23,9 +23,17 @@ func RSTStream() *spec.TestGroup {
 				return err
 			}
 
-			conn.WriteRSTStream(0, http2.ErrCodeCancel)
+			// RST_STREAM frame:
+			// length: 4, flags: 0x0, stream_id: 0x0, error_code: CANCEL
+			conn.Send([]byte("\x00\x00\x04\x03\x00\x00\x00\x00\x00"))
+			conn.Send([]byte("\x00\x00\x00\x08"))
 
-			return spec.VerifyConnectionError(conn, http2.ErrCodeProtocol)
+			// PING frame as a probe to detect that the RST_STREAM frame
+			// has been ignored.
+			data := [8]byte{'h', '2', 's', 'p', 'e', 'c'}
+			conn.WritePing(false, data)
+
+			return spec.VerifyConnectionErrorBeforePing(conn, http2.ErrCodeProtocol)
 		},
 	})
 
@@ -63,9 +71,11 @@ func RSTStream() *spec.TestGroup {
 			}
 
 			headers := spec.CommonHeaders(c)
+			headers[0].Value = "POST"
+
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     true,
+				EndStream:     false,
 				EndHeaders:    true,
 				BlockFragment: conn.EncodeHeaders(headers),
 			}
@@ -76,7 +86,26 @@ func RSTStream() *spec.TestGroup {
 			conn.Send([]byte("\x00\x00\x03\x03\x00\x00\x00\x00\x01"))
 			conn.Send([]byte("\x00\x00\x00"))
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeFrameSize)
+			return spec.VerifyConnectionError(conn, http2.ErrCodeFrameSize)
+		},
+	})
+
+	// RST_STREAM frames MUST NOT be sent for a stream in the "idle"
+	// state. If a RST_STREAM frame identifying an idle stream is
+	// received, the recipient MUST treat this as a connection error
+	// (Section 5.4.1) of type PROTOCOL_ERROR.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a RST_STREAM frame on a idle stream with a large stream identifier",
+		Requirement: "The endpoint MUST respond with a connection error of type PROTOCOL_ERROR.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			conn.WriteRSTStream(101, http2.ErrCodeCancel)
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeProtocol)
 		},
 	})
 
@@ -29,7 +29,7 @@ func DefinedSETTINGSParameters() *spec.TestGroup {
 			}
 			conn.WriteSettings(setting)
 
-			return spec.VerifyConnectionError(conn, http2.ErrCodeProtocol)
+			return spec.ExpectFrame(conn, spec.ConnectionError(http2.ErrCodeProtocol))
 		},
 	})
 
@@ -52,7 +52,7 @@ func DefinedSETTINGSParameters() *spec.TestGroup {
 			}
 			conn.WriteSettings(setting)
 
-			return spec.VerifyConnectionError(conn, http2.ErrCodeFlowControl)
+			return spec.ExpectFrame(conn, spec.ConnectionError(http2.ErrCodeFlowControl))
 		},
 	})
 
@@ -77,7 +77,7 @@ func DefinedSETTINGSParameters() *spec.TestGroup {
 			}
 			conn.WriteSettings(setting)
 
-			return spec.VerifyConnectionError(conn, http2.ErrCodeProtocol)
+			return spec.ExpectFrame(conn, spec.ConnectionError(http2.ErrCodeProtocol))
 		},
 	})
 
@@ -102,7 +102,7 @@ func DefinedSETTINGSParameters() *spec.TestGroup {
 			}
 			conn.WriteSettings(setting)
 
-			return spec.VerifyConnectionError(conn, http2.ErrCodeProtocol)
+			return spec.ExpectFrame(conn, spec.ConnectionError(http2.ErrCodeProtocol))
 		},
 	})
 
@@ -126,7 +126,43 @@ func DefinedSETTINGSParameters() *spec.TestGroup {
 			data := [8]byte{}
 			conn.WritePing(false, data)
 
-			return spec.VerifyPingFrameWithAck(conn, data)
+			return spec.ExpectFrame(conn, spec.PingAck(data))
+		},
+	})
+
+	// An endpoint that receives a SETTINGS frame with any unknown
+	// or unsupported identifier MUST ignore that setting.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a SETTINGS frame with unknown identifier followed by a known setting",
+		Requirement: "The endpoint MUST ignore that setting and continue processing.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			settings := []http2.Setting{
+				http2.Setting{
+					ID:  0xFA,
+					Val: 0xDEADBEEF,
+				},
+				http2.Setting{
+					ID:  http2.SettingMaxConcurrentStreams,
+					Val: 100,
+				},
+			}
+			conn.WriteSettings(settings...)
+
+			err = spec.ExpectFrame(conn, spec.SettingsAck())
+			if err != nil {
+				return err
+			}
+
+			spec.WriteRequest(conn, c, streamID)
+
+			return spec.ExpectFrame(conn, spec.Headers(streamID))
 		},
 	})
 
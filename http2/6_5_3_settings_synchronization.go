@@ -2,6 +2,7 @@ package http2
 
 import (
 	"fmt"
+	"time"
 
 	"golang.org/x/net/http2"
 
@@ -46,7 +47,7 @@ func SettingsSynchronization() *spec.TestGroup {
 			}
 			conn.WriteSettings(settings...)
 
-			err = spec.VerifySettingsFrameWithAck(conn)
+			err = spec.ExpectFrame(conn, spec.SettingsAck())
 			if err != nil {
 				return err
 			}
@@ -100,7 +101,161 @@ func SettingsSynchronization() *spec.TestGroup {
 			}
 			conn.WriteSettings(setting)
 
-			return spec.VerifySettingsFrameWithAck(conn)
+			return spec.ExpectFrame(conn, spec.SettingsAck())
+		},
+	})
+
+	// The values in the SETTINGS frame MUST be processed in the order
+	// they appear, with no other frame processing between values.
+	// When a parameter appears more than once, the last value wins.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a SETTINGS frame with duplicate SETTINGS_INITIAL_WINDOW_SIZE",
+		Requirement: "The endpoint MUST apply the last value of the duplicate parameters.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+			var windowSize uint32 = 100
+
+			// Skip this test case when the length of data is 0.
+			dataLen, err := spec.ServerDataLength(c)
+			if err != nil {
+				return err
+			}
+			if dataLen < 1 {
+				return spec.ErrSkipped
+			}
+
+			err = conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// Disable the automatic WINDOW_UPDATE to observe the
+			// window size applied by the server.
+			conn.WindowUpdate = false
+
+			settings := []http2.Setting{
+				http2.Setting{
+					ID:  http2.SettingInitialWindowSize,
+					Val: 1,
+				},
+				http2.Setting{
+					ID:  http2.SettingInitialWindowSize,
+					Val: windowSize,
+				},
+			}
+			conn.WriteSettings(settings...)
+
+			err = spec.ExpectFrame(conn, spec.SettingsAck())
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			expectedLen := dataLen
+			if expectedLen > int(windowSize) {
+				expectedLen = int(windowSize)
+			}
+
+			counter := spec.NewDataCounter()
+			actual := counter.ReadData(conn, conn.Timeout, streamID)
+
+			switch actual.(type) {
+			case spec.RSTStreamFrameEvent, spec.GoAwayFrameEvent, spec.ConnectionClosedEvent, spec.ErrorEvent:
+				return &spec.TestError{
+					Expected: []string{
+						fmt.Sprintf("DATA Frame (total length:%d, stream_id:%d)", expectedLen, streamID),
+					},
+					Actual: actual.String(),
+				}
+			}
+
+			if counter.Streams[streamID] != expectedLen {
+				return &spec.TestError{
+					Expected: []string{
+						fmt.Sprintf("DATA Frame (total length:%d, stream_id:%d)", expectedLen, streamID),
+					},
+					Actual: fmt.Sprintf("DATA Frame (total length:%d, stream_id:%d)", counter.Streams[streamID], streamID),
+				}
+			}
+
+			return nil
+		},
+	})
+
+	// Once all values have been processed, the recipient MUST
+	// immediately emit a SETTINGS frame with the ACK flag set.
+	//
+	// Note: The frames are read until the timeout, so that an ACK for
+	// a SETTINGS frame that has not been sent fails the test case.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends multiple SETTINGS frames without ACK flag",
+		Requirement: "The endpoint MUST emit a SETTINGS frame with the ACK flag set for each SETTINGS frame.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			conn.WriteSettings(http2.Setting{
+				ID:  http2.SettingMaxConcurrentStreams,
+				Val: 100,
+			})
+			conn.WriteSettings(http2.Setting{
+				ID:  http2.SettingMaxConcurrentStreams,
+				Val: 200,
+			})
+
+			return spec.VerifySettingsFrameWithAcks(conn, 2)
+		},
+	})
+
+	// If the sender of a SETTINGS frame does not receive an
+	// acknowledgement within a reasonable amount of time, it MAY issue
+	// a connection error (Section 5.4.1) of type SETTINGS_TIMEOUT.
+	//
+	// Note: The SETTINGS frame of the client connection preface has to
+	// be acknowledged within the delay of --settings-ack-delay over the
+	// median round-trip time measured by the capability detection. The
+	// delay is 1 second if the configuration leaves it zero.
+	tg.AddTestCase(&spec.TestCase{
+		Strict:      true,
+		Desc:        "Sends a SETTINGS frame in the connection preface and measures the time until ACK",
+		Requirement: "The endpoint SHOULD acknowledge SETTINGS frame within a reasonable amount of time.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			limit := c.SettingsAckDelay
+			if limit == 0 {
+				limit = time.Second
+			}
+			if caps := spec.DetectedCapabilities(c); caps != nil {
+				limit += caps.RTT.Median
+			}
+
+			latency := conn.SettingsAckLatency.Round(time.Microsecond)
+			if conn.SettingsAckLatency > limit {
+				return &spec.TestError{
+					Expected: []string{
+						fmt.Sprintf("SETTINGS Frame (flags:0x01) within %s", limit.Round(time.Microsecond)),
+					},
+					Actual: fmt.Sprintf("SETTINGS Frame (flags:0x01) after %s", latency),
+				}
+			}
+
+			return &spec.TestInfo{
+				Message: fmt.Sprintf("SETTINGS ACK received after %s", latency),
+			}
 		},
 	})
 
@@ -31,7 +31,7 @@ func Settings() *spec.TestGroup {
 			conn.Send([]byte("\x00\x00\x01\x04\x01\x00\x00\x00\x00"))
 			conn.Send([]byte("\x00"))
 
-			return spec.VerifyConnectionError(conn, http2.ErrCodeFrameSize)
+			return spec.ExpectFrame(conn, spec.ConnectionError(http2.ErrCodeFrameSize))
 		},
 	})
 
@@ -55,7 +55,7 @@ func Settings() *spec.TestGroup {
 			conn.Send([]byte("\x00\x00\x06\x04\x00\x00\x00\x00\x01"))
 			conn.Send([]byte("\x00\x03\x00\x00\x00\x64"))
 
-			return spec.VerifyConnectionError(conn, http2.ErrCodeProtocol)
+			return spec.ExpectFrame(conn, spec.ConnectionError(http2.ErrCodeProtocol))
 		},
 	})
 
@@ -80,11 +80,7 @@ func Settings() *spec.TestGroup {
 			conn.Send([]byte("\x00\x00\x03\x04\x00\x00\x00\x00\x00"))
 			conn.Send([]byte("\x00\x03\x00"))
 
-			codes := []http2.ErrCode{
-				http2.ErrCodeProtocol,
-				http2.ErrCodeFrameSize,
-			}
-			return spec.VerifyStreamError(conn, codes...)
+			return spec.ExpectFrame(conn, spec.ConnectionError(http2.ErrCodeFrameSize))
 		},
 	})
 
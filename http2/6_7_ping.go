@@ -22,7 +22,9 @@ func Ping() *spec.TestGroup {
 				return err
 			}
 
-			data := [8]byte{'h', '2', 's', 'p', 'e', 'c'}
+			// Use all 8 octets of the payload to detect a response
+			// that does not echo the opaque data.
+			data := [8]byte{'h', '2', 's', 'p', 'e', 'c', '0', '1'}
 			conn.WritePing(false, data)
 
 			return spec.VerifyPingFrameWithAck(conn, data)
@@ -46,6 +48,14 @@ func Ping() *spec.TestGroup {
 			unexpectedData := [8]byte{'i', 'n', 'v', 'a', 'l', 'i', 'd'}
 			expectedData := [8]byte{'h', '2', 's', 'p', 'e', 'c'}
 			conn.WritePing(true, unexpectedData)
+
+			// Verify that no PING frame is sent as a response of the
+			// PING frame with ACK.
+			err = spec.VerifyNoEventWithin(conn, spec.EventPingFrame, conn.Timeout)
+			if err != nil {
+				return err
+			}
+
 			conn.WritePing(false, expectedData)
 
 			return spec.VerifyPingFrameWithAck(conn, expectedData)
@@ -65,11 +75,16 @@ func Ping() *spec.TestGroup {
 			}
 
 			// PING frame:
-			// length: 8, flags: 0x0, stream_id: 1
-			conn.Send([]byte("\x00\x00\x08\x06\x00\x00\x00\x00\x01"))
+			// length: 8, flags: 0x0, stream_id: 3
+			conn.Send([]byte("\x00\x00\x08\x06\x00\x00\x00\x00\x03"))
 			conn.Send([]byte("\x00\x00\x00\x00\x00\x00\x00\x00"))
 
-			return spec.VerifyConnectionError(conn, http2.ErrCodeProtocol)
+			// PING frame on stream 0x0 as a probe. It must not be
+			// acknowledged because the connection has already failed.
+			data := [8]byte{'h', '2', 's', 'p', 'e', 'c'}
+			conn.WritePing(false, data)
+
+			return spec.VerifyConnectionErrorBeforePing(conn, http2.ErrCodeProtocol)
 		},
 	})
 
@@ -86,13 +101,34 @@ func Ping() *spec.TestGroup {
 			}
 
 			// PING frame:
-			// length: 8, flags: 0x0, stream_id: 1
-			conn.Send([]byte("\x00\x00\x06\x06\x00\x00\x00\x00\x01"))
+			// length: 6, flags: 0x0, stream_id: 0
+			conn.Send([]byte("\x00\x00\x06\x06\x00\x00\x00\x00\x00"))
 			conn.Send([]byte("\x00\x00\x00\x00\x00\x00"))
 
 			return spec.VerifyConnectionError(conn, http2.ErrCodeFrameSize)
 		},
 	})
 
+	// Receipt of a PING frame with a length field value other than 8
+	// MUST be treated as a connection error (Section 5.4.1) of type
+	// FRAME_SIZE_ERROR.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a PING frame with a length field value greater than 8",
+		Requirement: "The endpoint MUST treat this as a connection error of type FRAME_SIZE_ERROR.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// PING frame:
+			// length: 9, flags: 0x0, stream_id: 0
+			conn.Send([]byte("\x00\x00\x09\x06\x00\x00\x00\x00\x00"))
+			conn.Send([]byte("\x00\x00\x00\x00\x00\x00\x00\x00\x00"))
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeFrameSize)
+		},
+	})
+
 	return tg
 }
@@ -31,5 +31,105 @@ func GoAway() *spec.TestGroup {
 		},
 	})
 
+	// Once sent, the sender will ignore frames sent on streams initiated
+	// by the receiver if the stream has an identifier higher than the
+	// included last stream identifier. Receivers of a GOAWAY frame MUST
+	// NOT open additional streams on the connection, although a new
+	// connection can be established for new streams.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a GOAWAY frame with NO_ERROR during the response",
+		Requirement: "The endpoint MUST complete the response and close the connection without error.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.RequestHeaders(c)
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     !spec.HasRequestBody(c),
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+			spec.WriteRequestBody(conn, c, streamID)
+			conn.WriteGoAway(0, http2.ErrCodeNo, []byte{})
+
+			return spec.VerifyResponseAfterGoAway(conn, streamID)
+		},
+	})
+
+	// Activity on streams numbered lower or equal to the last stream
+	// identifier might still complete successfully.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a GOAWAY frame with NO_ERROR before the request body",
+		Requirement: "The endpoint MUST complete the response for the stream covered by the last stream identifier.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers[0].Value = "POST"
+			headers = append(headers, spec.HeaderField("content-length", "4"))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+			conn.WriteGoAway(streamID, http2.ErrCodeNo, []byte{})
+			conn.WriteData(streamID, true, []byte("test"))
+
+			return spec.VerifyResponseAfterGoAway(conn, streamID)
+		},
+	})
+
+	// Activity on streams numbered lower or equal to the last stream
+	// identifier might still complete successfully.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame to open a new stream after a GOAWAY frame",
+		Requirement: "The endpoint MUST complete the response for the stream opened before the GOAWAY frame.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.RequestHeaders(c)
+			hp1 := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     !spec.HasRequestBody(c),
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp1)
+			spec.WriteRequestBody(conn, c, streamID)
+			conn.WriteGoAway(0, http2.ErrCodeNo, []byte{})
+
+			// The new stream may be refused by the endpoint.
+			hp2 := http2.HeadersFrameParam{
+				StreamID:      streamID + 2,
+				EndStream:     !spec.HasRequestBody(c),
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp2)
+			spec.WriteRequestBody(conn, c, streamID+2)
+
+			return spec.VerifyResponseAfterGoAway(conn, streamID)
+		},
+	})
+
 	return tg
 }
@@ -2,6 +2,8 @@ package http2
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"golang.org/x/net/http2"
 
@@ -18,12 +20,13 @@ func TheFlowControlWindow() *spec.TestGroup {
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends SETTINGS frame to set the initial window size to 1 and sends HEADERS frame",
 		Requirement: "The endpoint MUST NOT send a flow-controlled frame with a length that exceeds the space available.",
+		Severity:    spec.SeverityHigh,
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 			var actual spec.Event
 
 			// Skip this test case when the length of data is 0.
-			dataLen, err := spec.ServerDataLength(c)
+			dataLen, err := spec.RequestDataLength(c)
 			if err != nil {
 				return err
 			}
@@ -49,14 +52,7 @@ func TheFlowControlWindow() *spec.TestGroup {
 				return err
 			}
 
-			headers := spec.CommonHeaders(c)
-			hp := http2.HeadersFrameParam{
-				StreamID:      streamID,
-				EndStream:     true,
-				EndHeaders:    true,
-				BlockFragment: conn.EncodeHeaders(headers),
-			}
-			conn.WriteHeaders(hp)
+			spec.WriteRequest(conn, c, streamID)
 
 			actual, passed := conn.WaitEventByType(spec.EventDataFrame)
 			switch event := actual.(type) {
@@ -91,6 +87,7 @@ func TheFlowControlWindow() *spec.TestGroup {
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends multiple WINDOW_UPDATE frames increasing the flow control window to above 2^31-1",
 		Requirement: "The endpoint MUST sends a GOAWAY frame with a FLOW_CONTROL_ERROR code.",
+		Severity:    spec.SeverityHigh,
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var actual spec.Event
 
@@ -135,6 +132,7 @@ func TheFlowControlWindow() *spec.TestGroup {
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends multiple WINDOW_UPDATE frames increasing the flow control window to above 2^31-1 on a stream",
 		Requirement: "The endpoint MUST sends a RST_STREAM frame with a FLOW_CONTROL_ERROR code.",
+		Severity:    spec.SeverityHigh,
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 			var actual spec.Event
@@ -144,7 +142,7 @@ func TheFlowControlWindow() *spec.TestGroup {
 				return err
 			}
 
-			headers := spec.CommonHeaders(c)
+			headers := spec.RequestHeaders(c)
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
 				EndStream:     false,
@@ -181,5 +179,302 @@ func TheFlowControlWindow() *spec.TestGroup {
 		},
 	})
 
+	// A sender MUST NOT allow a flow-control window to exceed 2^31-1
+	// octets. If a sender receives a WINDOW_UPDATE that causes a
+	// flow-control window to exceed this maximum, it MUST terminate
+	// either the stream or the connection, as appropriate.
+	// For streams, the sender sends a RST_STREAM with an error code
+	// of FLOW_CONTROL_ERROR; for the connection, a GOAWAY frame with
+	// an error code of FLOW_CONTROL_ERROR is sent.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends WINDOW_UPDATE frames increasing the flow control window to 2^31",
+		Requirement: "The endpoint MUST treat this as a connection error of type FLOW_CONTROL_ERROR.",
+		Severity:    spec.SeverityHigh,
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// The first WINDOW_UPDATE frame increases the connection
+			// window from the initial 65,535 octets to exactly 2^31-1,
+			// and the second one exceeds the maximum by 1 octet.
+			conn.WriteWindowUpdate(0, 2147483647-spec.DefaultWindowSize)
+			conn.WriteWindowUpdate(0, 1)
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeFlowControl)
+		},
+	})
+
+	// A sender MUST NOT allow a flow-control window to exceed 2^31-1
+	// octets. If a sender receives a WINDOW_UPDATE that causes a
+	// flow-control window to exceed this maximum, it MUST terminate
+	// either the stream or the connection, as appropriate.
+	// For streams, the sender sends a RST_STREAM with an error code
+	// of FLOW_CONTROL_ERROR; for the connection, a GOAWAY frame with
+	// an error code of FLOW_CONTROL_ERROR is sent.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends WINDOW_UPDATE frames increasing the flow control window to above 2^31-1 by the initial window size on a stream",
+		Requirement: "The endpoint MUST treat this as a stream error of type FLOW_CONTROL_ERROR.",
+		Severity:    spec.SeverityHigh,
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.RequestHeaders(c)
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			// The first WINDOW_UPDATE frame increases the stream window
+			// from the initial 65,535 octets to at most 2^31-1. The
+			// second one exceeds the maximum even if the endpoint has
+			// already sent DATA frames up to the initial window size.
+			conn.WriteWindowUpdate(streamID, 2147483647-spec.DefaultWindowSize)
+			conn.WriteWindowUpdate(streamID, spec.DefaultWindowSize+1)
+
+			return spec.VerifyStreamErrorOnStream(conn, streamID, http2.ErrCodeFlowControl)
+		},
+	})
+
+	// The sender MUST NOT send a flow-controlled frame with a length
+	// that exceeds the space available in either of the flow-control
+	// windows advertised by the receiver.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sets the initial window size to 64 in the connection preface and requests a large resource",
+		Requirement: "The endpoint MUST NOT send DATA frames exceeding the stream flow-control window.",
+		Severity:    spec.SeverityHigh,
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+			var windowSize uint32 = 64
+
+			// Skip this test case when the resource fits in the
+			// window size.
+			dataLen, err := spec.ResourceDataLength(c)
+			if err != nil {
+				return err
+			}
+			if dataLen <= int(windowSize) {
+				return spec.ErrSkipped
+			}
+
+			setting := http2.Setting{
+				ID:  http2.SettingInitialWindowSize,
+				Val: windowSize,
+			}
+			err = conn.HandshakeWithSettings(setting)
+			if err != nil {
+				return err
+			}
+
+			// Disable the automatic WINDOW_UPDATE to keep the stream
+			// window small.
+			conn.WindowUpdate = false
+
+			headers := spec.ResourceHeaders(c)
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			counter := spec.NewDataCounter()
+			counter.ReadData(conn, conn.Timeout, streamID)
+
+			err = counter.VerifyWindow(streamID, int(windowSize))
+			if err != nil {
+				return err
+			}
+
+			// Send WINDOW_UPDATE to verify that the endpoint resumes
+			// sending DATA frames within the new window, which adds to
+			// the space left unconsumed by the first DATA frames.
+			conn.WriteWindowUpdate(streamID, windowSize)
+
+			leftover := int(windowSize) - counter.Streams[streamID]
+
+			resumed := spec.NewDataCounter()
+			actual := resumed.ReadData(conn, conn.Timeout, streamID)
+
+			err = resumed.VerifyWindow(streamID, leftover+int(windowSize))
+			if err != nil {
+				return err
+			}
+
+			if resumed.Streams[streamID] == 0 {
+				return &spec.TestError{
+					Expected: []string{
+						fmt.Sprintf("DATA Frame (stream_id:%d)", streamID),
+					},
+					Actual: actual.String(),
+				}
+			}
+
+			return nil
+		},
+	})
+
+	// The sender MUST NOT send a flow-controlled frame with a length
+	// that exceeds the space available in either of the flow-control
+	// windows advertised by the receiver.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Requests large resources on multiple streams without updating the connection window",
+		Requirement: "The endpoint MUST NOT send DATA frames exceeding the connection flow-control window.",
+		Severity:    spec.SeverityHigh,
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var windowSize uint32 = 2147483647
+			streamIDs := []uint32{1, 3}
+
+			// Skip this test case when the resources fit in the
+			// connection window size.
+			dataLen, err := spec.ResourceDataLength(c)
+			if err != nil {
+				return err
+			}
+			if dataLen*len(streamIDs) <= spec.DefaultWindowSize {
+				return spec.ErrSkipped
+			}
+
+			// Set the initial window size to the maximum to make the
+			// connection window the only limit.
+			setting := http2.Setting{
+				ID:  http2.SettingInitialWindowSize,
+				Val: windowSize,
+			}
+			err = conn.HandshakeWithSettings(setting)
+			if err != nil {
+				return err
+			}
+
+			// Disable the automatic WINDOW_UPDATE to keep the
+			// connection window.
+			conn.WindowUpdate = false
+
+			headers := spec.ResourceHeaders(c)
+			for _, streamID := range streamIDs {
+				hp := http2.HeadersFrameParam{
+					StreamID:      streamID,
+					EndStream:     true,
+					EndHeaders:    true,
+					BlockFragment: conn.EncodeHeaders(headers),
+				}
+				conn.WriteHeaders(hp)
+			}
+
+			counter := spec.NewDataCounter()
+			counter.ReadData(conn, conn.Timeout, streamIDs...)
+
+			return counter.VerifyWindow(0, spec.DefaultWindowSize)
+		},
+	})
+
+	// A receiver that wishes to use a higher flow-control window can
+	// send a WINDOW_UPDATE frame to update it.
+	//
+	// Note: A server that reads the request body has to replenish the
+	// flow-control windows, otherwise the upload of a body larger than
+	// the windows is stalled. The body is sent strictly within the
+	// windows advertised by the server, and the whole upload must
+	// finish within uploadTimeout. A final response other than 2xx
+	// before the whole body is sent means that the path does not
+	// accept the body, and this test case is skipped then.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Uploads a request body larger than the initial flow-control windows",
+		Requirement: "The endpoint MUST send WINDOW_UPDATE frames to allow the request body to be received.",
+		Severity:    spec.SeverityMedium,
+		Tags:        []string{"needs-upload"},
+		Requires:    []spec.Capability{spec.CapabilityUploadEndpoint},
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+			size := c.UploadSize
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// Skip this test case when the body fits in the windows
+			// of the endpoint, since no WINDOW_UPDATE is required.
+			stream, connection := conn.SendWindow(streamID)
+			if size <= stream && size <= connection {
+				return spec.ErrSkipped
+			}
+
+			headers := spec.UploadHeaders(c, size)
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			timeout := c.TestTimeout(uploadTimeout)
+			deadline := time.Now().Add(timeout)
+
+			res := spec.NewResponse(streamID)
+			sent, stop := res.WriteBody(conn, size, deadline)
+
+			if _, ok := stop.(spec.TimeoutEvent); ok {
+				stream, connection := conn.SendWindow(streamID)
+				if !time.Now().Before(deadline) {
+					return &spec.TestError{
+						Expected: []string{"WINDOW_UPDATE Frame"},
+						Actual: fmt.Sprintf(
+							"Upload not finished within %s after %d of %d octets (stream window:%d, connection window:%d)",
+							timeout, sent, size, stream, connection,
+						),
+					}
+				}
+				return &spec.TestError{
+					Expected: []string{"WINDOW_UPDATE Frame"},
+					Actual: fmt.Sprintf(
+						"No WINDOW_UPDATE Frame for %s after %d of %d octets (stream window:%d, connection window:%d)",
+						conn.Timeout, sent, size, stream, connection,
+					),
+				}
+			}
+
+			// The endpoint may respond before the whole body is
+			// received, and the stream may be closed then.
+			actual := stop
+			if actual == nil && res.Headers == nil {
+				actual = res.ReadHeaders(conn, conn.Timeout)
+			}
+			if res.Headers == nil {
+				return &spec.TestError{
+					Expected: []string{
+						fmt.Sprintf("HEADERS Frame (stream_id:%d)", streamID),
+					},
+					Actual: actual.String(),
+				}
+			}
+
+			if sent < size && !strings.HasPrefix(res.Status(), "2") {
+				return &spec.TestSkipped{
+					Reason: fmt.Sprintf("upload path responded with :status %s after %d of %d octets", res.Status(), sent, size),
+				}
+			}
+
+			return &spec.TestInfo{
+				Message: fmt.Sprintf("Uploaded %d of %d octets (:status:%s)", sent, size, res.Status()),
+			}
+		},
+	})
+
 	return tg
 }
+
+// uploadTimeout is the time within which the whole request body of the
+// upload test case must be sent, before the timeout scale is applied.
+const uploadTimeout = 60 * time.Second
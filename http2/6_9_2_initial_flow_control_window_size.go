@@ -19,6 +19,7 @@ func InitialFlowControlWindowSize() *spec.TestGroup {
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Changes SETTINGS_INITIAL_WINDOW_SIZE after sending HEADERS frame",
 		Requirement: "The endpoint MUST adjust the size of all stream flow-control windows.",
+		Severity:    spec.SeverityHigh,
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 			var actual spec.Event
@@ -108,6 +109,7 @@ func InitialFlowControlWindowSize() *spec.TestGroup {
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends a SETTINGS frame for window size to be negative",
 		Requirement: "The endpoint MUST track the negative flow-control window.",
+		Severity:    spec.SeverityHigh,
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 			var actual spec.Event
@@ -205,6 +207,7 @@ func InitialFlowControlWindowSize() *spec.TestGroup {
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends a SETTINGS_INITIAL_WINDOW_SIZE settings with an exceeded maximum window size value",
 		Requirement: "The endpoint MUST treat this as a connection error of type FLOW_CONTROL_ERROR.",
+		Severity:    spec.SeverityHigh,
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			err := conn.Handshake()
 			if err != nil {
@@ -220,5 +223,335 @@ func InitialFlowControlWindowSize() *spec.TestGroup {
 		},
 	})
 
+	// A change to SETTINGS_INITIAL_WINDOW_SIZE can cause the available
+	// space in a flow-control window to become negative. A sender MUST
+	// track the negative flow-control window and MUST NOT send new
+	// flow-controlled frames until it receives WINDOW_UPDATE frames
+	// that cause the flow-control window to become positive.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Reduces SETTINGS_INITIAL_WINDOW_SIZE during the response to make the window negative",
+		Requirement: "The endpoint MUST NOT send new flow-controlled frames until the window becomes positive.",
+		Severity:    spec.SeverityHigh,
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+			var windowSize uint32 = 1
+			var resumeSize uint32 = 100
+
+			// Skip this test case when the resource fits in the
+			// default window size.
+			dataLen, err := spec.ResourceDataLength(c)
+			if err != nil {
+				return err
+			}
+			if dataLen <= spec.DefaultWindowSize {
+				return spec.ErrSkipped
+			}
+
+			err = conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// Disable the automatic WINDOW_UPDATE and open the
+			// connection window to limit the server by the stream
+			// window only.
+			conn.WindowUpdate = false
+			conn.WriteWindowUpdate(0, 2147483647-spec.DefaultWindowSize)
+
+			headers := spec.ResourceHeaders(c)
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			// Wait for the first DATA frame of the response.
+			sent := spec.NewDataCounter()
+			err = waitDataFrame(conn, sent, streamID)
+			if err != nil {
+				return err
+			}
+
+			setting := http2.Setting{
+				ID:  http2.SettingInitialWindowSize,
+				Val: windowSize,
+			}
+			conn.WriteSettings(setting)
+
+			// DATA frames received before the SETTINGS frame with ACK
+			// flag were sent with the previous window size.
+			err = waitSettingsAck(conn, sent)
+			if err != nil {
+				return err
+			}
+
+			// The window is negative, the server must stop sending.
+			paused := spec.NewDataCounter()
+			actual := paused.ReadData(conn, conn.Timeout, streamID)
+
+			err = paused.VerifyWindow(streamID, 0)
+			if err != nil {
+				return err
+			}
+
+			if actual.Type() != spec.EventTimeout {
+				return &spec.TestError{
+					Expected: []string{
+						fmt.Sprintf("No DATA Frame (stream_id:%d) until WINDOW_UPDATE", streamID),
+					},
+					Actual: actual.String(),
+				}
+			}
+
+			// Restore the window size to a positive value.
+			incr := uint32(sent.Streams[streamID]) - windowSize + resumeSize
+			conn.WriteWindowUpdate(streamID, incr)
+
+			resumed := spec.NewDataCounter()
+			actual = resumed.ReadData(conn, conn.Timeout, streamID)
+
+			err = resumed.VerifyWindow(streamID, int(resumeSize))
+			if err != nil {
+				return err
+			}
+
+			if resumed.Streams[streamID] == 0 {
+				return &spec.TestError{
+					Expected: []string{
+						fmt.Sprintf("DATA Frame (stream_id:%d)", streamID),
+					},
+					Actual: actual.String(),
+				}
+			}
+
+			return nil
+		},
+	})
+
+	// When the value of SETTINGS_INITIAL_WINDOW_SIZE changes,
+	// a receiver MUST adjust the size of all stream flow-control
+	// windows that it maintains by the difference between the new
+	// value and the old value.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Changes SETTINGS_INITIAL_WINDOW_SIZE to 0 and back to 65535 during the response",
+		Requirement: "The endpoint MUST NOT send DATA frames while the window is not positive.",
+		Severity:    spec.SeverityHigh,
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+			var resumeSize int = 100
+
+			// Skip this test case when the resource fits in the
+			// default window size.
+			dataLen, err := spec.ResourceDataLength(c)
+			if err != nil {
+				return err
+			}
+			if dataLen <= spec.DefaultWindowSize {
+				return spec.ErrSkipped
+			}
+
+			err = conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// Disable the automatic WINDOW_UPDATE and open the
+			// connection window to limit the server by the stream
+			// window only.
+			conn.WindowUpdate = false
+			conn.WriteWindowUpdate(0, 2147483647-spec.DefaultWindowSize)
+
+			headers := spec.ResourceHeaders(c)
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			sent := spec.NewDataCounter()
+			err = waitDataFrame(conn, sent, streamID)
+			if err != nil {
+				return err
+			}
+
+			conn.WriteSettings(http2.Setting{
+				ID:  http2.SettingInitialWindowSize,
+				Val: 0,
+			})
+
+			err = waitSettingsAck(conn, sent)
+			if err != nil {
+				return err
+			}
+
+			// The window is not positive, the server must pause.
+			paused := spec.NewDataCounter()
+			actual := paused.ReadData(conn, conn.Timeout, streamID)
+
+			err = paused.VerifyWindow(streamID, 0)
+			if err != nil {
+				return err
+			}
+
+			if actual.Type() != spec.EventTimeout {
+				return &spec.TestError{
+					Expected: []string{
+						fmt.Sprintf("No DATA Frame (stream_id:%d) until SETTINGS_INITIAL_WINDOW_SIZE changes", streamID),
+					},
+					Actual: actual.String(),
+				}
+			}
+
+			conn.WriteSettings(http2.Setting{
+				ID:  http2.SettingInitialWindowSize,
+				Val: spec.DefaultWindowSize,
+			})
+
+			resumed := spec.NewDataCounter()
+			err = waitSettingsAck(conn, resumed)
+			if err != nil {
+				return err
+			}
+
+			// The window is restored to the initial window size minus
+			// the length of DATA frames already sent. If it is still
+			// not positive, restore it with WINDOW_UPDATE frame.
+			window := spec.DefaultWindowSize - sent.Streams[streamID]
+			if window <= 0 {
+				resumed.ReadData(conn, conn.Timeout, streamID)
+
+				err = resumed.VerifyWindow(streamID, 0)
+				if err != nil {
+					return err
+				}
+
+				conn.WriteWindowUpdate(streamID, uint32(resumeSize-window))
+				window = resumeSize
+			}
+
+			actual = resumed.ReadData(conn, conn.Timeout, streamID)
+
+			err = resumed.VerifyWindow(streamID, window)
+			if err != nil {
+				return err
+			}
+
+			if resumed.Streams[streamID] == 0 {
+				return &spec.TestError{
+					Expected: []string{
+						fmt.Sprintf("DATA Frame (stream_id:%d)", streamID),
+					},
+					Actual: actual.String(),
+				}
+			}
+
+			return nil
+		},
+	})
+
+	// An endpoint MUST treat a change to SETTINGS_INITIAL_WINDOW_SIZE
+	// that causes any flow-control window to exceed the maximum size
+	// as a connection error (Section 5.4.1) of type
+	// FLOW_CONTROL_ERROR.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a SETTINGS_INITIAL_WINDOW_SIZE settings with an exceeded maximum window size value during the response",
+		Requirement: "The endpoint MUST treat this as a connection error of type FLOW_CONTROL_ERROR.",
+		Severity:    spec.SeverityHigh,
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			// Skip this test case when the resource fits in the
+			// default window size.
+			dataLen, err := spec.ResourceDataLength(c)
+			if err != nil {
+				return err
+			}
+			if dataLen <= spec.DefaultWindowSize {
+				return spec.ErrSkipped
+			}
+
+			err = conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			conn.WindowUpdate = false
+
+			headers := spec.ResourceHeaders(c)
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			err = waitDataFrame(conn, spec.NewDataCounter(), streamID)
+			if err != nil {
+				return err
+			}
+
+			conn.WriteSettings(http2.Setting{
+				ID:  http2.SettingInitialWindowSize,
+				Val: 2147483648,
+			})
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeFlowControl)
+		},
+	})
+
 	return tg
 }
+
+// waitDataFrame waits for a DATA frame on the specified stream and
+// accounts its length with the counter.
+func waitDataFrame(conn *spec.Conn, counter *spec.DataCounter, streamID uint32) error {
+	var actual spec.Event
+
+	for !conn.Closed {
+		actual = conn.WaitEvent()
+
+		event, ok := actual.(spec.DataFrameEvent)
+		if ok && event.Header().StreamID == streamID {
+			counter.Add(event)
+			return nil
+		}
+	}
+
+	return &spec.TestError{
+		Expected: []string{
+			fmt.Sprintf("DATA Frame (stream_id:%d)", streamID),
+		},
+		Actual: actual.String(),
+	}
+}
+
+// waitSettingsAck waits for a SETTINGS frame with ACK flag while
+// accounting the length of DATA frames with the counter.
+func waitSettingsAck(conn *spec.Conn, counter *spec.DataCounter) error {
+	var actual spec.Event
+
+	for !conn.Closed {
+		actual = conn.WaitEvent()
+
+		switch event := actual.(type) {
+		case spec.DataFrameEvent:
+			counter.Add(event)
+		case spec.SettingsFrameEvent:
+			if event.IsAck() {
+				return nil
+			}
+		}
+	}
+
+	return &spec.TestError{
+		Expected: []string{
+			"SETTINGS Frame (length:0, flags:0x01, stream_id:0)",
+		},
+		Actual: actual.String(),
+	}
+}
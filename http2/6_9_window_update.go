@@ -18,15 +18,24 @@ func WindowUpdate() *spec.TestGroup {
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends a WINDOW_UPDATE frame with a flow control window increment of 0",
 		Requirement: "The endpoint MUST treat this as a connection error of type PROTOCOL_ERROR.",
+		Severity:    spec.SeverityHigh,
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			err := conn.Handshake()
 			if err != nil {
 				return err
 			}
 
-			conn.WriteWindowUpdate(0, 0)
+			// WINDOW_UPDATE frame:
+			// length: 4, flags: 0x0, stream_id: 0, increment: 0
+			conn.Send([]byte("\x00\x00\x04\x08\x00\x00\x00\x00\x00"))
+			conn.Send([]byte("\x00\x00\x00\x00"))
+
+			// PING frame as a probe to detect that the WINDOW_UPDATE
+			// frame has been ignored.
+			data := [8]byte{'h', '2', 's', 'p', 'e', 'c'}
+			conn.WritePing(false, data)
 
-			return spec.VerifyConnectionError(conn, http2.ErrCodeProtocol)
+			return spec.VerifyConnectionErrorBeforePing(conn, http2.ErrCodeProtocol)
 		},
 	})
 
@@ -37,7 +46,8 @@ func WindowUpdate() *spec.TestGroup {
 	// (Section 5.4.1).
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends a WINDOW_UPDATE frame with a flow control window increment of 0 on a stream",
-		Requirement: "The endpoint MUST treat this as a connection error of type PROTOCOL_ERROR.",
+		Requirement: "The endpoint MUST treat this as a stream error of type PROTOCOL_ERROR.",
+		Severity:    spec.SeverityHigh,
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 
@@ -57,7 +67,7 @@ func WindowUpdate() *spec.TestGroup {
 
 			conn.WriteWindowUpdate(streamID, 0)
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeProtocol)
+			return spec.VerifyStreamErrorOrConnectionError(conn, http2.ErrCodeProtocol)
 		},
 	})
 
@@ -67,6 +77,7 @@ func WindowUpdate() *spec.TestGroup {
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends a WINDOW_UPDATE frame with a length other than 4 octets",
 		Requirement: "The endpoint MUST treat this as a connection error of type FRAME_SIZE_ERROR.",
+		Severity:    spec.SeverityHigh,
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			err := conn.Handshake()
 			if err != nil {
@@ -82,6 +93,28 @@ func WindowUpdate() *spec.TestGroup {
 		},
 	})
 
+	// A WINDOW_UPDATE frame with a length other than 4 octets MUST
+	// be treated as a connection error (Section 5.4.1) of type
+	// FRAME_SIZE_ERROR.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a WINDOW_UPDATE frame with a length greater than 4 octets",
+		Requirement: "The endpoint MUST treat this as a connection error of type FRAME_SIZE_ERROR.",
+		Severity:    spec.SeverityHigh,
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// WINDOW_UPDATE frame:
+			// length: 5, flags: 0x0, stream_id: 0
+			conn.Send([]byte("\x00\x00\x05\x08\x00\x00\x00\x00\x00"))
+			conn.Send([]byte("\x00\x00\x00\x01\x00"))
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeFrameSize)
+		},
+	})
+
 	tg.AddTestGroup(TheFlowControlWindow())
 	tg.AddTestGroup(InitialFlowControlWindowSize())
 
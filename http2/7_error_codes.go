@@ -65,5 +65,57 @@ func ErrorCodes() *spec.TestGroup {
 		},
 	})
 
+	// Unknown or unsupported error codes MUST NOT trigger any special
+	// behavior. These MAY be treated by an implementation as being
+	// equivalent to INTERNAL_ERROR.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a RST_STREAM frame with unknown error code followed by a new request",
+		Requirement: "The endpoint MUST respond to the new request.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.RequestHeadersWithMethod(c, "POST")
+
+			hp1 := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp1)
+			conn.WriteRSTStream(streamID, 0xff)
+
+			spec.WriteRequest(conn, c, streamID+2)
+
+			return spec.VerifyStreamResponse(conn, streamID+2)
+		},
+	})
+
+	// Unknown or unsupported error codes MUST NOT trigger any special
+	// behavior. These MAY be treated by an implementation as being
+	// equivalent to INTERNAL_ERROR.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a GOAWAY frame with unknown error code during the response",
+		Requirement: "The endpoint MUST complete the response for the stream.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			spec.WriteRequest(conn, c, streamID)
+			conn.WriteGoAway(0, 0xff, []byte{})
+
+			return spec.VerifyStreamResponse(conn, streamID)
+		},
+	})
+
 	return tg
 }
@@ -19,7 +19,7 @@ func PseudoHeaderFields() *spec.TestGroup {
 	// pseudo-header fields as malformed (Section 8.1.2.6).
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends a HEADERS frame that contains a unknown pseudo-header field",
-		Requirement: "The endpoint MUST respond with a stream error of type PROTOCOL_ERROR.",
+		Requirement: "The endpoint MUST treat the request as malformed.",
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 
@@ -40,7 +40,7 @@ func PseudoHeaderFields() *spec.TestGroup {
 
 			conn.WriteHeaders(hp)
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeProtocol)
+			return spec.VerifyMalformedRequest(conn, streamID)
 		},
 	})
 
@@ -53,7 +53,7 @@ func PseudoHeaderFields() *spec.TestGroup {
 	// pseudo-header fields as malformed (Section 8.1.2.6).
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends a HEADERS frame that contains the pseudo-header field defined for response",
-		Requirement: "The endpoint MUST respond with a stream error of type PROTOCOL_ERROR.",
+		Requirement: "The endpoint MUST treat the request as malformed.",
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 
@@ -74,7 +74,7 @@ func PseudoHeaderFields() *spec.TestGroup {
 
 			conn.WriteHeaders(hp)
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeProtocol)
+			return spec.VerifyMalformedRequest(conn, streamID)
 		},
 	})
 
@@ -87,7 +87,7 @@ func PseudoHeaderFields() *spec.TestGroup {
 	// pseudo-header fields as malformed (Section 8.1.2.6).
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends a HEADERS frame that contains a pseudo-header field as trailers",
-		Requirement: "The endpoint MUST respond with a stream error of type PROTOCOL_ERROR.",
+		Requirement: "The endpoint MUST treat the request as malformed.",
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 
@@ -110,19 +110,20 @@ func PseudoHeaderFields() *spec.TestGroup {
 			conn.WriteData(streamID, false, []byte("test"))
 
 			trailers := []hpack.HeaderField{
-				spec.HeaderField(":method", "POST"),
+				spec.HeaderField(":method", "GET"),
+				spec.HeaderField("x-test", "ok"),
 			}
 
 			hp2 := http2.HeadersFrameParam{
 				StreamID:      streamID,
-				EndStream:     false,
+				EndStream:     true,
 				EndHeaders:    true,
 				BlockFragment: conn.EncodeHeaders(trailers),
 			}
 
 			conn.WriteHeaders(hp2)
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeProtocol)
+			return spec.VerifyMalformedRequest(conn, streamID)
 		},
 	})
 
@@ -156,7 +157,74 @@ func PseudoHeaderFields() *spec.TestGroup {
 
 			conn.WriteHeaders(hp)
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeProtocol)
+			return spec.VerifyStreamErrorOnStream(conn, streamID, http2.ErrCodeProtocol)
+		},
+	})
+
+	// All pseudo-header fields MUST appear in the header block before
+	// regular header fields. Any request or response that contains
+	// a pseudo-header field that appears in a header block after
+	// a regular header field MUST be treated as malformed
+	// (Section 8.1.2.6).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame that contains the \":authority\" pseudo-header field after a regular header field",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			common := spec.CommonHeaders(c)
+			headers := []hpack.HeaderField{
+				common[0],
+				common[1],
+				common[2],
+				spec.HeaderField("x-foo", "bar"),
+				common[3],
+			}
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// All pseudo-header fields MUST appear in the header block before
+	// regular header fields.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame that contains all pseudo-header fields before a regular header field",
+		Requirement: "The endpoint MUST accept the request.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers = append(headers, spec.HeaderField("x-foo", "bar"))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyStreamResponse(conn, streamID)
 		},
 	})
 
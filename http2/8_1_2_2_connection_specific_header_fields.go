@@ -15,7 +15,7 @@ func ConnectionSpecificHeaderFields() *spec.TestGroup {
 	// malformed (Section 8.1.2.6).
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends a HEADERS frame that contains the connection-specific header field",
-		Requirement: "The endpoint MUST respond with a stream error of type PROTOCOL_ERROR.",
+		Requirement: "The endpoint MUST treat the request as malformed.",
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 
@@ -36,7 +36,7 @@ func ConnectionSpecificHeaderFields() *spec.TestGroup {
 
 			conn.WriteHeaders(hp)
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeProtocol)
+			return spec.VerifyMalformedRequest(conn, streamID)
 		},
 	})
 
@@ -45,7 +45,7 @@ func ConnectionSpecificHeaderFields() *spec.TestGroup {
 	// any value other than "trailers".
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends a HEADERS frame that contains the TE header field with any value other than \"trailers\"",
-		Requirement: "The endpoint MUST respond with a stream error of type PROTOCOL_ERROR.",
+		Requirement: "The endpoint MUST treat the request as malformed.",
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 
@@ -67,7 +67,259 @@ func ConnectionSpecificHeaderFields() *spec.TestGroup {
 
 			conn.WriteHeaders(hp)
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeProtocol)
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// An endpoint MUST NOT generate an HTTP/2 message containing
+	// connection-specific header fields; any message containing
+	// connection-specific header fields MUST be treated as
+	// malformed (Section 8.1.2.6).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame that contains the \"keep-alive\" header field",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers = append(headers, spec.HeaderField("keep-alive", "timeout=5"))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// An endpoint MUST NOT generate an HTTP/2 message containing
+	// connection-specific header fields; any message containing
+	// connection-specific header fields MUST be treated as
+	// malformed (Section 8.1.2.6).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame that contains the \"proxy-connection\" header field",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers = append(headers, spec.HeaderField("proxy-connection", "keep-alive"))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// An endpoint MUST NOT generate an HTTP/2 message containing
+	// connection-specific header fields; any message containing
+	// connection-specific header fields MUST be treated as
+	// malformed (Section 8.1.2.6).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame that contains the \"transfer-encoding\" header field",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers = append(headers, spec.HeaderField("transfer-encoding", "chunked"))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// An endpoint MUST NOT generate an HTTP/2 message containing
+	// connection-specific header fields; any message containing
+	// connection-specific header fields MUST be treated as
+	// malformed (Section 8.1.2.6).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame that contains the \"upgrade\" header field",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers = append(headers, spec.HeaderField("upgrade", "h2c"))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// The only exception to this is the TE header field, which MAY be
+	// present in an HTTP/2 request; when it is, it MUST NOT contain
+	// any value other than "trailers".
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame that contains the TE header field with \"gzip\"",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers = append(headers, spec.HeaderField("te", "gzip"))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// The only exception to this is the TE header field, which MAY be
+	// present in an HTTP/2 request; when it is, it MUST NOT contain
+	// any value other than "trailers".
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame that contains the TE header field with \"trailers\"",
+		Requirement: "The endpoint MUST accept the request.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers = append(headers, spec.HeaderField("te", "trailers"))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyStreamResponse(conn, streamID)
+		},
+	})
+
+	// An endpoint MUST NOT generate an HTTP/2 message containing
+	// connection-specific header fields; any message containing
+	// connection-specific header fields MUST be treated as
+	// malformed (Section 8.1.2.6).
+	//
+	// Note: HTTP/2 removes support for the 101 (Switching Protocols)
+	// informational status code (see Section 8.1.1), so the response
+	// must not contain it either.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a request and receives the response",
+		Requirement: "The endpoint MUST NOT send connection-specific header fields or 101 status code.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(spec.CommonHeaders(c)),
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyNoConnectionSpecificResponse(conn, streamID)
+		},
+	})
+
+	// An endpoint MUST NOT generate an HTTP/2 message containing
+	// connection-specific header fields; any message containing
+	// connection-specific header fields MUST be treated as
+	// malformed (Section 8.1.2.6).
+	//
+	// Note: This test case sends the request to upgrade to WebSocket
+	// as in HTTP/1.1, and expects the endpoint to reject it without
+	// sending a response with 101 status code.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame that contains the \"upgrade: websocket\" and \"connection: upgrade\" header fields",
+		Requirement: "The endpoint MUST treat the request as malformed without switching protocols.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers = append(headers, spec.HeaderField("upgrade", "websocket"))
+			headers = append(headers, spec.HeaderField("connection", "upgrade"))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyMalformedRequest(conn, streamID)
 		},
 	})
 
@@ -21,7 +21,7 @@ func RequestPseudoHeaderFields() *spec.TestGroup {
 	// component MUST include a value of '/'.
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends a HEADERS frame with empty \":path\" pseudo-header field",
-		Requirement: "The endpoint MUST respond with a stream error of type PROTOCOL_ERROR.",
+		Requirement: "The endpoint MUST treat the request as malformed.",
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 
@@ -42,7 +42,7 @@ func RequestPseudoHeaderFields() *spec.TestGroup {
 
 			conn.WriteHeaders(hp)
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeProtocol)
+			return spec.VerifyMalformedRequest(conn, streamID)
 		},
 	})
 
@@ -51,7 +51,7 @@ func RequestPseudoHeaderFields() *spec.TestGroup {
 	// unless it is a CONNECT request (Section 8.3).
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends a HEADERS frame that omits \":method\" pseudo-header field",
-		Requirement: "The endpoint MUST respond with a stream error of type PROTOCOL_ERROR.",
+		Requirement: "The endpoint MUST treat the request as malformed.",
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 
@@ -71,12 +71,12 @@ func RequestPseudoHeaderFields() *spec.TestGroup {
 				StreamID:      streamID,
 				EndStream:     true,
 				EndHeaders:    true,
-				BlockFragment: conn.EncodeHeaders(headers[1:]),
+				BlockFragment: conn.EncodeHeaders(headers),
 			}
 
 			conn.WriteHeaders(hp)
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeProtocol)
+			return spec.VerifyMalformedRequest(conn, streamID)
 		},
 	})
 
@@ -85,7 +85,7 @@ func RequestPseudoHeaderFields() *spec.TestGroup {
 	// unless it is a CONNECT request (Section 8.3).
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends a HEADERS frame that omits \":scheme\" pseudo-header field",
-		Requirement: "The endpoint MUST respond with a stream error of type PROTOCOL_ERROR.",
+		Requirement: "The endpoint MUST treat the request as malformed.",
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 
@@ -110,7 +110,7 @@ func RequestPseudoHeaderFields() *spec.TestGroup {
 
 			conn.WriteHeaders(hp)
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeProtocol)
+			return spec.VerifyMalformedRequest(conn, streamID)
 		},
 	})
 
@@ -119,7 +119,7 @@ func RequestPseudoHeaderFields() *spec.TestGroup {
 	// unless it is a CONNECT request (Section 8.3).
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends a HEADERS frame that omits \":path\" pseudo-header field",
-		Requirement: "The endpoint MUST respond with a stream error of type PROTOCOL_ERROR.",
+		Requirement: "The endpoint MUST treat the request as malformed.",
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 
@@ -144,7 +144,7 @@ func RequestPseudoHeaderFields() *spec.TestGroup {
 
 			conn.WriteHeaders(hp)
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeProtocol)
+			return spec.VerifyMalformedRequest(conn, streamID)
 		},
 	})
 
@@ -153,7 +153,7 @@ func RequestPseudoHeaderFields() *spec.TestGroup {
 	// unless it is a CONNECT request (Section 8.3).
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends a HEADERS frame with duplicated \":method\" pseudo-header field",
-		Requirement: "The endpoint MUST respond with a stream error of type PROTOCOL_ERROR.",
+		Requirement: "The endpoint MUST treat the request as malformed.",
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 
@@ -163,7 +163,7 @@ func RequestPseudoHeaderFields() *spec.TestGroup {
 			}
 
 			headers := spec.CommonHeaders(c)
-			headers = append(headers, spec.HeaderField(":method", headers[0].Value))
+			headers = append(headers, spec.HeaderField(":method", "POST"))
 
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
@@ -174,7 +174,7 @@ func RequestPseudoHeaderFields() *spec.TestGroup {
 
 			conn.WriteHeaders(hp)
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeProtocol)
+			return spec.VerifyMalformedRequest(conn, streamID)
 		},
 	})
 
@@ -183,7 +183,7 @@ func RequestPseudoHeaderFields() *spec.TestGroup {
 	// unless it is a CONNECT request (Section 8.3).
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends a HEADERS frame with duplicated \":scheme\" pseudo-header field",
-		Requirement: "The endpoint MUST respond with a stream error of type PROTOCOL_ERROR.",
+		Requirement: "The endpoint MUST treat the request as malformed.",
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 
@@ -204,7 +204,7 @@ func RequestPseudoHeaderFields() *spec.TestGroup {
 
 			conn.WriteHeaders(hp)
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeProtocol)
+			return spec.VerifyMalformedRequest(conn, streamID)
 		},
 	})
 
@@ -212,8 +212,67 @@ func RequestPseudoHeaderFields() *spec.TestGroup {
 	// the ":method", ":scheme", and ":path" pseudo-header fields,
 	// unless it is a CONNECT request (Section 8.3).
 	tg.AddTestCase(&spec.TestCase{
-		Desc:        "Sends a HEADERS frame with duplicated \":method\" pseudo-header field",
-		Requirement: "The endpoint MUST respond with a stream error of type PROTOCOL_ERROR.",
+		Desc:        "Sends a HEADERS frame with duplicated \":path\" pseudo-header field",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers = append(headers, spec.HeaderField(":path", headers[2].Value))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// All HTTP/2 requests MUST include exactly one valid value for
+	// the ":method", ":scheme", and ":path" pseudo-header fields,
+	// unless it is a CONNECT request (Section 8.3).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame with empty \":scheme\" pseudo-header field",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers[1].Value = ""
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// A request in asterisk form includes the value '*' for the
+	// ":path" pseudo-header field.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends an OPTIONS request with \"*\" as \":path\" pseudo-header field",
+		Requirement: "The endpoint MUST accept the request in asterisk form.",
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 
@@ -223,7 +282,8 @@ func RequestPseudoHeaderFields() *spec.TestGroup {
 			}
 
 			headers := spec.CommonHeaders(c)
-			headers = append(headers, spec.HeaderField(":method", headers[2].Value))
+			headers[0].Value = "OPTIONS"
+			headers[2].Value = "*"
 
 			hp := http2.HeadersFrameParam{
 				StreamID:      streamID,
@@ -234,7 +294,7 @@ func RequestPseudoHeaderFields() *spec.TestGroup {
 
 			conn.WriteHeaders(hp)
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeProtocol)
+			return spec.VerifyStreamResponse(conn, streamID)
 		},
 	})
 
@@ -0,0 +1,55 @@
+package http2
+
+import (
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+	"golang.org/x/net/http2"
+)
+
+func CompressingTheCookieHeaderField() *spec.TestGroup {
+	tg := NewTestGroup("8.1.2.5", "Compressing the Cookie Header Field")
+
+	// The Cookie header field [COOKIE] uses a semi-colon (";") to
+	// delimit cookie-pairs (or "crumbs"). This header field doesn't
+	// follow the list construction rules in HTTP (see [RFC7230],
+	// Section 3.2.2), which prevents cookie-pairs from being
+	// separated into different name-value pairs.
+	//
+	// If there are multiple Cookie header fields after decompression,
+	// these MUST be concatenated into a single octet string using the
+	// two-octet delimiter of 0x3B, 0x20 (the ASCII string "; ") before
+	// being passed into a non-HTTP/2 context, such as an HTTP/1.1
+	// connection, or a generic HTTP server application.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame with the cookie header field split into multiple crumbs",
+		Requirement: "The endpoint MUST concatenate the cookie header fields using \"; \".",
+		Tags:        []string{"needs-echo"},
+		Requires:    []spec.Capability{spec.CapabilityEchoEndpoint},
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.EchoHeaders(c)
+			headers = append(headers, spec.HeaderField("cookie", "a=b"))
+			headers = append(headers, spec.HeaderField("cookie", "c=d"))
+			headers = append(headers, spec.HeaderField("cookie", "e=f"))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyEchoedHeaderField(conn, streamID, "cookie", "a=b; c=d; e=f")
+		},
+	})
+
+	return tg
+}
@@ -25,7 +25,7 @@ func MalformedRequestsAndResponses() *spec.TestGroup {
 	// (Section 5.4.2) of type PROTOCOL_ERROR.
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends a HEADERS frame with the \"content-length\" header field which does not equal the DATA frame payload length",
-		Requirement: "The endpoint MUST treat this as a stream error of type PROTOCOL_ERROR.",
+		Requirement: "The endpoint MUST treat the request as malformed.",
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 
@@ -48,7 +48,7 @@ func MalformedRequestsAndResponses() *spec.TestGroup {
 			conn.WriteHeaders(hp)
 			conn.WriteData(streamID, true, []byte("test"))
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeProtocol)
+			return spec.VerifyMalformedRequest(conn, streamID)
 		},
 	})
 
@@ -68,7 +68,7 @@ func MalformedRequestsAndResponses() *spec.TestGroup {
 	// (Section 5.4.2) of type PROTOCOL_ERROR.
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends a HEADERS frame with the \"content-length\" header field which does not equal the sum of the multiple DATA frames payload length",
-		Requirement: "The endpoint MUST treat this as a stream error of type PROTOCOL_ERROR.",
+		Requirement: "The endpoint MUST treat the request as malformed.",
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 
@@ -92,7 +92,248 @@ func MalformedRequestsAndResponses() *spec.TestGroup {
 			conn.WriteData(streamID, false, []byte("test"))
 			conn.WriteData(streamID, true, []byte("test"))
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeProtocol)
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// A request or response that includes a payload body can include
+	// a content-length header field. A request or response is also
+	// malformed if the value of a content-length header field does
+	// not equal the sum of the DATA frame payload lengths that form
+	// the body. A response that is defined to have no payload, as
+	// described in [RFC7230], Section 3.3.2, can have a non-zero
+	// content-length header field, even though no content is included
+	// in DATA frames.
+	//
+	// Intermediaries that process HTTP requests or responses (i.e.,
+	// any intermediary not acting as a tunnel) MUST NOT forward a
+	// malformed request or response. Malformed requests or responses
+	// that are detected MUST be treated as a stream error
+	// (Section 5.4.2) of type PROTOCOL_ERROR.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame with the \"content-length\" header field which is greater than the DATA frame payload length",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers[0].Value = "POST"
+			headers = append(headers, spec.HeaderField("content-length", "10"))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+			conn.WriteData(streamID, true, []byte("test!"))
+
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// A request or response that includes a payload body can include
+	// a content-length header field. A request or response is also
+	// malformed if the value of a content-length header field does
+	// not equal the sum of the DATA frame payload lengths that form
+	// the body. A response that is defined to have no payload, as
+	// described in [RFC7230], Section 3.3.2, can have a non-zero
+	// content-length header field, even though no content is included
+	// in DATA frames.
+	//
+	// Intermediaries that process HTTP requests or responses (i.e.,
+	// any intermediary not acting as a tunnel) MUST NOT forward a
+	// malformed request or response. Malformed requests or responses
+	// that are detected MUST be treated as a stream error
+	// (Section 5.4.2) of type PROTOCOL_ERROR.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame with the \"content-length\" header field which is less than the sum of the multiple DATA frames payload length",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers[0].Value = "POST"
+			headers = append(headers, spec.HeaderField("content-length", "12"))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+			conn.WriteData(streamID, false, []byte("test"))
+			conn.WriteData(streamID, false, []byte("test"))
+			conn.WriteData(streamID, true, []byte("test!"))
+
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// A request or response that includes a payload body can include
+	// a content-length header field. A request or response is also
+	// malformed if the value of a content-length header field does
+	// not equal the sum of the DATA frame payload lengths that form
+	// the body. A response that is defined to have no payload, as
+	// described in [RFC7230], Section 3.3.2, can have a non-zero
+	// content-length header field, even though no content is included
+	// in DATA frames.
+	//
+	// Intermediaries that process HTTP requests or responses (i.e.,
+	// any intermediary not acting as a tunnel) MUST NOT forward a
+	// malformed request or response. Malformed requests or responses
+	// that are detected MUST be treated as a stream error
+	// (Section 5.4.2) of type PROTOCOL_ERROR.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame with the \"content-length\" header field which is greater than the sum of the multiple DATA frames payload length",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers[0].Value = "POST"
+			headers = append(headers, spec.HeaderField("content-length", "12"))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+			conn.WriteData(streamID, false, []byte("test"))
+			conn.WriteData(streamID, true, []byte("test"))
+
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// A request or response that includes a payload body can include
+	// a content-length header field. A request or response is also
+	// malformed if the value of a content-length header field does
+	// not equal the sum of the DATA frame payload lengths that form
+	// the body. A response that is defined to have no payload, as
+	// described in [RFC7230], Section 3.3.2, can have a non-zero
+	// content-length header field, even though no content is included
+	// in DATA frames.
+	//
+	// Intermediaries that process HTTP requests or responses (i.e.,
+	// any intermediary not acting as a tunnel) MUST NOT forward a
+	// malformed request or response. Malformed requests or responses
+	// that are detected MUST be treated as a stream error
+	// (Section 5.4.2) of type PROTOCOL_ERROR.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame with the \"content-length\" header field which equals the sum of the padded DATA frames payload length",
+		Requirement: "The endpoint MUST NOT count the padding toward the content-length.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers[0].Value = "POST"
+			headers = append(headers, spec.HeaderField("content-length", "12"))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+			conn.WriteDataPadded(streamID, false, []byte("test"), make([]byte, 8))
+			conn.WriteDataPadded(streamID, false, []byte("test"), make([]byte, 8))
+			conn.WriteDataPadded(streamID, true, []byte("test"), make([]byte, 8))
+
+			return spec.VerifyStreamResponse(conn, streamID)
+		},
+	})
+
+	// A response that is defined to have no payload, as described in
+	// [RFC7230], Section 3.3.2, can have a non-zero content-length
+	// header field, even though no content is included in DATA frames.
+	//
+	// Note: Responses to the HEAD request method never include a
+	// message body (see [RFC7230], Section 3.3), so the response must
+	// end with the HEADERS frame or an empty DATA frame even if it
+	// includes the content-length of the body for GET.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEAD request",
+		Requirement: "The endpoint MUST NOT send the response payload in DATA frames.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers[0].Value = "HEAD"
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyHeadResponse(conn, streamID)
+		},
+	})
+
+	// A request or response is also malformed if the value of a
+	// content-length header field does not equal the sum of the DATA
+	// frame payload lengths that form the body.
+	//
+	// Note: The padding is not a part of the payload, so it is not
+	// counted toward the content-length. This test case is skipped if
+	// the response of the target path has no content-length.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a GET request and receives the response with the \"content-length\" header field",
+		Requirement: "The endpoint MUST send the DATA frames whose payload length equals the content-length.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(spec.CommonHeaders(c)),
+			}
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyResponseContentLength(conn, streamID)
 		},
 	})
 
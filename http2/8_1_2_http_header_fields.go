@@ -4,6 +4,7 @@ import (
 	"github.com/summerwind/h2spec/config"
 	"github.com/summerwind/h2spec/spec"
 	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
 )
 
 func HTTPHeaderFields() *spec.TestGroup {
@@ -17,7 +18,8 @@ func HTTPHeaderFields() *spec.TestGroup {
 	// malformed (Section 8.1.2.6).
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends a HEADERS frame that contains the header field name in uppercase letters",
-		Requirement: "The endpoint MUST respond with a stream error of type PROTOCOL_ERROR.",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Severity:    spec.SeverityLow,
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 
@@ -38,13 +40,62 @@ func HTTPHeaderFields() *spec.TestGroup {
 
 			conn.WriteHeaders(hp)
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeProtocol)
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// Just as in HTTP/1.x, header field names are strings of ASCII
+	// characters that are compared in a case-insensitive fashion.
+	// However, header field names MUST be converted to lowercase
+	// prior to their encoding in HTTP/2. A request or response
+	// containing uppercase header field names MUST be treated as
+	// malformed (Section 8.1.2.6).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame that contains the header field name in uppercase letters as trailers",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Severity:    spec.SeverityLow,
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers[0].Value = "POST"
+
+			hp1 := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp1)
+			conn.WriteData(streamID, false, []byte("test"))
+
+			trailers := []hpack.HeaderField{
+				spec.HeaderField("X-Test", "ok"),
+			}
+
+			hp2 := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(trailers),
+			}
+
+			conn.WriteHeaders(hp2)
+
+			return spec.VerifyMalformedRequest(conn, streamID)
 		},
 	})
 
 	tg.AddTestGroup(PseudoHeaderFields())
 	tg.AddTestGroup(ConnectionSpecificHeaderFields())
 	tg.AddTestGroup(RequestPseudoHeaderFields())
+	tg.AddTestGroup(CompressingTheCookieHeaderField())
 	tg.AddTestGroup(MalformedRequestsAndResponses())
 
 	return tg
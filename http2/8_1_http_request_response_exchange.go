@@ -16,7 +16,7 @@ func HTTPRequestResponseExchange() *spec.TestGroup {
 	// as malformed (Section 8.1.2.6).
 	tg.AddTestCase(&spec.TestCase{
 		Desc:        "Sends a second HEADERS frame without the END_STREAM flag",
-		Requirement: "The endpoint MUST respond with a stream error of type PROTOCOL_ERROR.",
+		Requirement: "The endpoint MUST treat the request as malformed.",
 		Run: func(c *config.Config, conn *spec.Conn) error {
 			var streamID uint32 = 1
 
@@ -51,7 +51,146 @@ func HTTPRequestResponseExchange() *spec.TestGroup {
 
 			conn.WriteHeaders(hp2)
 
-			return spec.VerifyStreamError(conn, http2.ErrCodeProtocol)
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// An HTTP request/response exchange fully consumes a single stream.
+	// A request starts with the HEADERS frame that puts the stream into
+	// an "open" state. [...]
+	//
+	// An HTTP response consists of:
+	//   1. one HEADERS frame (followed by zero or more CONTINUATION
+	//      frames) containing the message headers (see [RFC7230],
+	//      Section 3.2),
+	//   [...]
+	//
+	// A server can send a complete response prior to the client sending
+	// an entire request if the response does not depend on any portion
+	// of the request that has not been sent and received.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a POST request with \"expect: 100-continue\" header field",
+		Requirement: "The endpoint MUST send a final response after the interim response.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers[0].Value = "POST"
+			headers = append(headers, spec.HeaderField("expect", "100-continue"))
+			headers = append(headers, spec.HeaderField("content-length", "4"))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+
+			res := spec.NewResponse(streamID)
+			res.ReadHeaders(conn, c.Timeout)
+
+			conn.WriteData(streamID, true, []byte("test"))
+
+			return spec.VerifyInterimResponse(conn, res)
+		},
+	})
+
+	// A server can send a complete response prior to the client sending
+	// an entire request if the response does not depend on any portion
+	// of the request that has not been sent and received. When this is
+	// true, a server MAY request that the client abort transmission of
+	// a request without error by sending a RST_STREAM with an error
+	// code of NO_ERROR after sending a complete response (i.e., a frame
+	// with the END_STREAM flag).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a POST request with \"expect: 100-continue\" header field and withholds the body",
+		Requirement: "The endpoint MUST respond to the request without waiting for the body indefinitely.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers[0].Value = "POST"
+			headers = append(headers, spec.HeaderField("expect", "100-continue"))
+			headers = append(headers, spec.HeaderField("content-length", "4"))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+
+			res := spec.NewResponse(streamID)
+			ev := res.ReadHeaders(conn, c.Timeout)
+
+			conn.WriteData(streamID, true, []byte("test"))
+
+			result := spec.VerifyExpectContinue(conn, res, ev)
+			if _, ok := result.(*spec.TestInfo); !ok {
+				return result
+			}
+
+			// The connection must remain usable after the body arrives.
+			data := [8]byte{'h', '2', 's', 'p', 'e', 'c'}
+			conn.WritePing(false, data)
+
+			err = spec.VerifyPingFrameWithAck(conn, data)
+			if err != nil {
+				return err
+			}
+
+			return result
+		},
+	})
+
+	// An HTTP response consists of:
+	//   [...]
+	//   3. optionally, one HEADERS frame, followed by zero or more
+	//      CONTINUATION frames containing the trailer-part, if present
+	//      (see [RFC7230], Section 4.1.2).
+	//
+	// The last frame in the sequence bears an END_STREAM flag, noting
+	// that a HEADERS frame bearing the END_STREAM flag can be followed
+	// by CONTINUATION frames that carry any remaining portions of the
+	// header block.
+	//
+	// Pseudo-header fields MUST NOT appear in trailers.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a request to the endpoint that sends trailers",
+		Requirement: "The endpoint MUST send the trailers with END_STREAM flag and without pseudo-header fields.",
+		Requires:    []spec.Capability{spec.CapabilityTrailersEndpoint},
+		Tags:        []string{"needs-trailers"},
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(spec.TrailersHeaders(c)),
+			}
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyTrailers(conn, streamID)
 		},
 	})
 
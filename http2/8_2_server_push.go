@@ -41,5 +41,81 @@ func ServerPush() *spec.TestGroup {
 		},
 	})
 
+	// PUSH_PROMISE MUST NOT be sent if the SETTINGS_ENABLE_PUSH setting
+	// of the peer endpoint is set to 0. An endpoint that has both set
+	// this parameter to 0 and had it acknowledged MUST treat the receipt
+	// of a PUSH_PROMISE frame as a connection error (Section 5.4.1) of
+	// type PROTOCOL_ERROR.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends SETTINGS_ENABLE_PUSH with 0 in the connection preface",
+		Requirement: "The endpoint MUST NOT send a PUSH_PROMISE frame.",
+		Requires:    []spec.Capability{spec.CapabilityPush},
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			setting := http2.Setting{
+				ID:  http2.SettingEnablePush,
+				Val: 0,
+			}
+			err := conn.HandshakeWithSettings(setting)
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			err = spec.VerifyNoPushPromise(conn, streamID)
+			if err != nil {
+				return err
+			}
+
+			return &spec.TestInfo{Message: "Push correctly suppressed"}
+		},
+	})
+
+	// A client cannot push. Thus, servers MUST treat the receipt of
+	// a PUSH_PROMISE frame as a connection error (Section 5.4.1) of
+	// type PROTOCOL_ERROR.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a PUSH_PROMISE frame on an open stream",
+		Requirement: "The endpoint MUST treat this as a connection error of type PROTOCOL_ERROR.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers[0].Value = "POST"
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+
+			// PUSH_PROMISE frame:
+			// promised_stream_id: 2
+			payload := []byte("\x00\x00\x00\x02")
+			payload = append(payload, conn.EncodeHeaders(spec.CommonHeaders(c))...)
+
+			conn.WriteRawFrame(http2.FramePushPromise, http2.FlagPushPromiseEndHeaders, streamID, payload)
+
+			return spec.VerifyConnectionError(conn, http2.ErrCodeProtocol)
+		},
+	})
+
 	return tg
 }
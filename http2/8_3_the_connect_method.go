@@ -0,0 +1,111 @@
+package http2
+
+import (
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+func TheCONNECTMethod() *spec.TestGroup {
+	tg := NewTestGroup("8.3", "The CONNECT Method")
+
+	// A CONNECT request MUST be constructed as follows:
+	//   - The ":method" pseudo-header field is set to "CONNECT".
+	//   - The ":scheme" and ":path" pseudo-header fields MUST be omitted.
+	//   - The ":authority" pseudo-header field contains the host and port
+	//     to connect to (equivalent to the authority-form of the
+	//     request-target of CONNECT requests (see [RFC7230],
+	//     Section 5.3)).
+	//
+	// A proxy that supports CONNECT establishes a TCP connection
+	// [TCP] to the server identified in the ":authority" pseudo-header
+	// field. Once this connection is successfully established, the
+	// proxy sends a HEADERS frame containing a 2xx series status code
+	// to the client, as defined in [RFC7231], Section 4.3.6.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a CONNECT request",
+		Requirement: "The endpoint MUST NOT treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers = []hpack.HeaderField{
+				spec.HeaderField(":method", "CONNECT"),
+				headers[3], // :authority
+			}
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+
+			res := spec.NewResponse(streamID)
+			ev := res.ReadHeaders(conn, c.Timeout)
+
+			result := spec.VerifyConnectRequest(conn, res, ev)
+			if _, ok := result.(*spec.TestInfo); !ok {
+				return result
+			}
+
+			// Only DATA frames can be sent on the established tunnel.
+			if res.Headers != nil && !res.Ended {
+				conn.WriteData(streamID, false, []byte("test"))
+			}
+
+			data := [8]byte{'h', '2', 's', 'p', 'e', 'c'}
+			conn.WritePing(false, data)
+
+			err = spec.VerifyNoErrorBeforePing(conn, streamID, data)
+			if err != nil {
+				return err
+			}
+
+			return result
+		},
+	})
+
+	// A CONNECT request MUST be constructed as follows:
+	//   - The ":method" pseudo-header field is set to "CONNECT".
+	//   - The ":scheme" and ":path" pseudo-header fields MUST be omitted.
+	//
+	// A CONNECT request that does not conform to these restrictions is
+	// malformed (Section 8.1.2.6).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a CONNECT request with \":scheme\" and \":path\" pseudo-header fields",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			headers[0].Value = "CONNECT"
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	return tg
+}
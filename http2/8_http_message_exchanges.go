@@ -7,6 +7,7 @@ func HTTPMessageExchanges() *spec.TestGroup {
 
 	tg.AddTestGroup(HTTPRequestResponseExchange())
 	tg.AddTestGroup(ServerPush())
+	tg.AddTestGroup(TheCONNECTMethod())
 
 	return tg
 }
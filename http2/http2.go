@@ -24,6 +24,7 @@ func Spec() *spec.TestGroup {
 	tg.AddTestGroup(FrameDefinitions())
 	tg.AddTestGroup(ErrorCodes())
 	tg.AddTestGroup(HTTPMessageExchanges())
+	tg.AddTestGroup(SecurityConsiderations())
 
 	return tg
 }
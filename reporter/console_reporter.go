@@ -0,0 +1,208 @@
+package reporter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/log"
+	"github.com/summerwind/h2spec/spec"
+)
+
+var (
+	gray   = color.New(color.FgHiBlack).SprintFunc()
+	yellow = color.New(color.FgYellow).SprintFunc()
+)
+
+// ConsoleReporter prints the progress of a run and the summary of the
+// results to the console.
+type ConsoleReporter struct {
+	config *config.Config
+
+	// tested is true if a test result has been printed after the
+	// title of the last group.
+	tested bool
+}
+
+// NewConsoleReporter returns a ConsoleReporter.
+func NewConsoleReporter(c *config.Config) *ConsoleReporter {
+	return &ConsoleReporter{config: c}
+}
+
+// slowRTTRatio is the ratio of the median round-trip time to the
+// timeout above which a larger timeout is suggested.
+const slowRTTRatio = 0.25
+
+// RunStarted prints the active profile if it is specified, and what
+// the capability detection learned about the server including the
+// round-trip time.
+func (r *ConsoleReporter) RunStarted(groups []*spec.TestGroup) {
+	printed := false
+	log.SetIndentLevel(0)
+
+	if p := spec.ActiveProfile(r.config); p != 0 {
+		log.Println(fmt.Sprintf("Spec profile: RFC %s", p))
+		printed = true
+	}
+
+	if caps := spec.DetectedCapabilities(r.config); caps != nil && !r.config.DryRun {
+		printServer(caps)
+		printed = true
+	}
+
+	if caps := spec.DetectedCapabilities(r.config); caps != nil && caps.RTT.Samples > 0 && !r.config.DryRun {
+		rtt := caps.RTT
+		min := rtt.Min.Round(time.Microsecond)
+		median := rtt.Median.Round(time.Microsecond)
+		log.Println(fmt.Sprintf("Round-trip time: min %s, median %s (%d PINGs)", min, median, rtt.Samples))
+		if d := suggestedTimeout(rtt, r.config.Timeout); d > 0 {
+			log.Println(yellow(fmt.Sprintf("Warning: the median round-trip time is close to the timeout of %s, consider --timeout %d or larger", r.config.Timeout, int(d.Seconds()))))
+		}
+		printed = true
+	}
+
+	if printed {
+		log.PrintBlankLine()
+	}
+}
+
+// printServer prints the address, the "server" header field, the TLS
+// parameters and the settings of the server. The settings of the
+// initial value are marked as default.
+func printServer(caps *spec.Capabilities) {
+	server := caps.Address
+	if caps.Server != "" {
+		server = fmt.Sprintf("%s (server: %s)", server, caps.Server)
+	}
+	log.Println(fmt.Sprintf("Server: %s", server))
+
+	if caps.TLS != nil {
+		log.Println(fmt.Sprintf("TLS: %s, %s, ALPN %s", caps.TLS.Version, caps.TLS.CipherSuite, caps.TLS.ALPN))
+	}
+
+	settings := []string{}
+	for _, s := range caps.AdvertisedSettings() {
+		setting := fmt.Sprintf("%s=%d", s.Name, s.Value)
+		if s.Default {
+			setting += " (default)"
+		}
+		settings = append(settings, setting)
+	}
+	if len(settings) == 0 {
+		settings = append(settings, "(none)")
+	}
+	log.Println(fmt.Sprintf("Settings: %s", strings.Join(settings, ", ")))
+}
+
+// GroupStarted prints the title of the group.
+func (r *ConsoleReporter) GroupStarted(tg *spec.TestGroup) {
+	if r.tested {
+		log.PrintBlankLine()
+		r.tested = false
+	}
+
+	level := tg.Level()
+	log.SetIndentLevel(level)
+	log.Println(tg.Title())
+	log.SetIndentLevel(level + 1)
+}
+
+// TestStarted prints the description of the running test case, which
+// is replaced with the result when the test case is finished.
+func (r *ConsoleReporter) TestStarted(tc *spec.TestCase, seq int) {
+	if r.config.DryRun || r.config.Verbose {
+		return
+	}
+
+	log.Print(gray(fmt.Sprintf("  %d: %s", seq, tc.Desc)))
+}
+
+// TestFinished prints the result of the test case.
+func (r *ConsoleReporter) TestFinished(tr *spec.TestResult) {
+	r.tested = true
+
+	if r.config.DryRun {
+		msg := fmt.Sprintf("%d: %s", tr.Sequence, tr.TestCase.Desc)
+		if a := tr.TestCase.Applicability(); a != "" {
+			msg = fmt.Sprintf("%s (%s)", msg, a)
+		}
+		if tags := tr.TestCase.AllTags(); len(tags) > 0 {
+			msg = fmt.Sprintf("%s (tags:%s)", msg, strings.Join(tags, ","))
+		}
+		log.Println(msg)
+		return
+	}
+
+	log.ResetLine()
+	tr.Print()
+}
+
+// RunFinished prints the failed test cases, the flaky test cases, the
+// summary of each section and the summary of the run.
+func (r *ConsoleReporter) RunFinished(summary *spec.RunSummary) error {
+	// The description of the interrupted test case is overwritten.
+	if summary.Interrupted {
+		log.ResetLine()
+	}
+
+	if r.tested {
+		log.PrintBlankLine()
+		r.tested = false
+	}
+
+	if r.config.DryRun {
+		return nil
+	}
+
+	groups := summary.Groups
+
+	if summary.Total() == 0 && !summary.Interrupted {
+		log.SetIndentLevel(0)
+		log.Println("No matched tests found.")
+		return nil
+	}
+
+	if failed(groups) {
+		log.SetIndentLevel(0)
+		FailedTests(groups)
+	}
+
+	log.SetIndentLevel(0)
+	FlakyTests(groups)
+	SectionSummary(groups)
+
+	log.Println(fmt.Sprintf("Finished in %.4f seconds", summary.Duration.Seconds()))
+	Summary(groups)
+	SettingsAckLatency(groups)
+	Traffic(groups)
+
+	if summary.Interrupted {
+		reason := summary.AbortReason
+		if reason == "" {
+			reason = "interrupted"
+		}
+		log.Println(yellow(fmt.Sprintf("Aborted: %s, %d tests not attempted", reason, summary.NotAttempted)))
+	}
+
+	// The address is shown only if it was chosen from the addresses
+	// of the host.
+	if summary.Address != "" && summary.Address != r.config.Addr() {
+		log.Println(fmt.Sprintf("Connected to %s", summary.Address))
+	}
+
+	return nil
+}
+
+// failed returns true if any test case of the groups has failed or
+// errored.
+func failed(groups []*spec.TestGroup) bool {
+	for _, tg := range groups {
+		if tg.FailedCount > 0 || tg.ErroredCount > 0 {
+			return true
+		}
+	}
+
+	return false
+}
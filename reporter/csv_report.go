@@ -0,0 +1,133 @@
+package reporter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+)
+
+// requirementLevels is the list of the requirement levels of RFC 2119
+// in the order of matching.
+var requirementLevels = []string{"MUST NOT", "MUST", "SHOULD NOT", "SHOULD", "MAY"}
+
+// CSVReporter writes the CSV report to the file when the run is
+// finished.
+type CSVReporter struct {
+	spec.NopReporter
+	config *config.Config
+	path   string
+}
+
+// NewCSVReporter returns a CSVReporter that writes the report to the
+// specified path.
+func NewCSVReporter(c *config.Config, filePath string) *CSVReporter {
+	return &CSVReporter{config: c, path: filePath}
+}
+
+// RunFinished writes the CSV report unless no test case has run.
+func (r *CSVReporter) RunFinished(summary *spec.RunSummary) error {
+	if summary.Total() == 0 && !summary.Interrupted {
+		return nil
+	}
+
+	return writeCSVReport(NewReport(r.config, summary), r.path)
+}
+
+// CSVReport writes a file which contains the test result of h2spec in
+// CSV format, with a header row and a row for each test case. Each row
+// contains the metadata of the run as well as the target.
+func CSVReport(groups []*spec.TestGroup, c *config.Config, filePath string) error {
+	r := &Report{
+		Target:   c.Addr(),
+		Metadata: NewReportMetadata(c),
+		Results:  convertReportResults(groups),
+	}
+	return writeCSVReport(r, filePath)
+}
+
+// writeCSVReport writes the CSV report of the report as CSVReport does.
+func writeCSVReport(r *Report, filePath string) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"id", "section", "description", "requirement", "severity", "verdict", "actual", "duration_ms", "timeout_ms", "attempts", "target", "connections", "frames_sent", "frames_received", "bytes_sent", "bytes_received", "h2spec_version", "h2spec_revision", "go_version", "os", "hostname", "target_ip", "labels"})
+
+	m := r.Metadata
+	metadata := []string{m.Version, m.Revision, m.GoVersion, m.OS, m.Hostname, m.TargetIP, m.labelList()}
+	for _, record := range convertCSVRecords(r.Results, r.Target) {
+		w.Write(append(record, metadata...))
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+func convertCSVRecords(results []ReportResult, target string) [][]string {
+	records := [][]string{}
+
+	for _, res := range results {
+		timeout := ""
+		if res.TimeoutMs != 0 {
+			timeout = fmt.Sprintf("%d", res.TimeoutMs)
+		}
+
+		records = append(records, []string{
+			res.ID,
+			res.Section,
+			res.Description,
+			res.Level,
+			res.Severity,
+			res.Verdict,
+			res.Actual,
+			fmt.Sprintf("%d", res.DurationMs),
+			timeout,
+			fmt.Sprintf("%d", res.Attempts),
+			target,
+			fmt.Sprintf("%d", res.Traffic.Connections),
+			fmt.Sprintf("%d", res.Traffic.FramesSent),
+			fmt.Sprintf("%d", res.Traffic.FramesReceived),
+			fmt.Sprintf("%d", res.Traffic.BytesSent),
+			fmt.Sprintf("%d", res.Traffic.BytesReceived),
+		})
+	}
+
+	return records
+}
+
+// requirementLevel returns the requirement level of RFC 2119 in the
+// requirement of the test case.
+func requirementLevel(tc *spec.TestCase) string {
+	for _, level := range requirementLevels {
+		if strings.Contains(tc.Requirement, level) {
+			return level
+		}
+	}
+
+	return ""
+}
+
+// actual returns the summary of the actual result of the test result.
+func actual(tr *spec.TestResult) string {
+	switch err := tr.Error.(type) {
+	case nil:
+		return ""
+	case *spec.TestError:
+		return err.Actual
+	case *spec.TestWarning:
+		return err.Reason
+	case *spec.TestInfo:
+		return err.Message
+	case *spec.TestSkipped:
+		return err.Reason
+	default:
+		return err.Error()
+	}
+}
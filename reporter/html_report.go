@@ -0,0 +1,107 @@
+package reporter
+
+import (
+	"html/template"
+	"os"
+
+	"github.com/summerwind/h2spec/spec"
+)
+
+// htmlReportTemplate is the template of the HTML report.
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>h2spec report for {{.Target}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+.passed, .warning { color: #2a2; }
+.failed, .errored { color: #c22; }
+.inconclusive { color: #b80; }
+.skipped { color: #29a; }
+.severity-high { background: #fcc; font-weight: bold; }
+.severity-medium { background: #fec; }
+.severity-low { background: #eef; }
+</style>
+</head>
+<body>
+<h1>h2spec report for {{.Target}}</h1>
+<p>{{len .Results}} tests in {{printf "%.4f" .Duration}} seconds</p>
+{{if .Interrupted}}<p class="failed">Aborted: {{if .AbortReason}}{{.AbortReason}}{{else}}interrupted{{end}}, {{.NotAttempted}} tests not attempted</p>
+{{end}}{{with .Metadata}}<h2>Metadata</h2>
+<table>
+{{range .Fields}}<tr><th>{{.Name}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>
+{{end}}{{with .Server}}<h2>Server</h2>
+<table>
+<tr><th>Address</th><td>{{.Address}}</td></tr>
+{{if .Header}}<tr><th>Server header</th><td>{{.Header}}</td></tr>
+{{end}}{{with .TLS}}<tr><th>TLS</th><td>{{.Version}}, {{.CipherSuite}}, ALPN {{.ALPN}}</td></tr>
+{{end}}<tr><th>Settings</th><td>{{range .Settings}}{{.Name}}: {{.Value}}{{if .Default}} (default){{end}}<br>{{else}}(none){{end}}</td></tr>
+</table>
+{{end}}{{with .RTT}}<h2>Round-trip time</h2>
+<table>
+<tr><th>Min</th><td>{{printf "%.3f" .MinMs}} ms</td></tr>
+<tr><th>Median</th><td>{{printf "%.3f" .MedianMs}} ms ({{.Samples}} PINGs)</td></tr>
+<tr><th>Timeout</th><td>{{.TimeoutMs}} ms{{if .SuggestedTimeoutMs}} <span class="inconclusive">(suggested: {{.SuggestedTimeoutMs}} ms)</span>{{end}}</td></tr>
+</table>
+{{end}}{{with .FailedBySeverity}}<h2>Failures by severity</h2>
+{{range .}}<h3 class="severity-{{.Severity}}">{{.Severity}} ({{len .Results}})</h3>
+<table>
+<tr><th>ID</th><th>Description</th><th>Expected</th><th>Actual</th></tr>
+{{range .Results}}<tr class="severity-{{.Severity}}"><td>{{.ID}}</td><td>{{.Description}}</td><td>{{range .Expected}}{{.}}<br>{{end}}</td><td>{{.Actual}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}<h2>Results</h2>
+<table>
+<tr><th>ID</th><th>Description</th><th>Severity</th><th>Verdict</th><th>Expected</th><th>Actual</th></tr>
+{{range .Results}}<tr><td>{{.ID}}</td><td>{{.Description}}</td><td class="severity-{{.Severity}}">{{.Severity}}</td><td class="{{.Verdict}}">{{.Verdict}}</td><td>{{range .Expected}}{{.}}<br>{{end}}</td><td>{{.Actual}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// SeverityResults represents the failed test cases of a severity.
+type SeverityResults struct {
+	Severity string
+	Results  []ReportResult
+}
+
+// FailedBySeverity returns the failed test cases grouped by severity
+// from the highest one, so that the operators can triage the failures.
+// The severities without failed test cases are omitted.
+func (r *Report) FailedBySeverity() []SeverityResults {
+	groups := []SeverityResults{}
+
+	for _, severity := range []spec.Severity{spec.SeverityHigh, spec.SeverityMedium, spec.SeverityLow} {
+		sr := SeverityResults{Severity: severity.String()}
+		for _, res := range r.Results {
+			if res.Verdict == "failed" && res.Severity == sr.Severity {
+				sr.Results = append(sr.Results, res)
+			}
+		}
+
+		if len(sr.Results) > 0 {
+			groups = append(groups, sr)
+		}
+	}
+
+	return groups
+}
+
+// HTMLReport writes a file which contains the report as a HTML page.
+func HTMLReport(r *Report, filePath string) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+
+	err = htmlReportTemplate.Execute(f, r)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
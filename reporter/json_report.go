@@ -0,0 +1,24 @@
+package reporter
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// JSONReport writes a file which contains the report in JSON format.
+func JSONReport(r *Report, filePath string) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	err = enc.Encode(r)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
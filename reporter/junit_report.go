@@ -1,43 +1,62 @@
 package reporter
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"strings"
 
+	"github.com/summerwind/h2spec/config"
 	"github.com/summerwind/h2spec/spec"
 )
 
 // JUnitReport represents the JUnit XML format.
 type JUnitTestReport struct {
 	XMLName    xml.Name          `xml:"testsuites"`
+	Aborted    string            `xml:"aborted,attr,omitempty"`
 	TestSuites []*JUnitTestSuite `xml:"testsuite"`
 }
 
 // JUnitTestSuite represents the testsuite element of JUnit XML format.
 type JUnitTestSuite struct {
-	XMLName   xml.Name         `xml:"testsuite"`
-	Name      string           `xml:"name,attr"`
-	Package   string           `xml:"package,attr"`
-	ID        string           `xml:"id,attr"`
-	Tests     int              `xml:"tests,attr"`
-	Skipped   int              `xml:"skipped,attr"`
-	Failures  int              `xml:"failures,attr"`
-	Errors    int              `xml:"errors,attr"`
-	TestCases []*JUnitTestCase `xml:"testcase"`
+	XMLName    xml.Name         `xml:"testsuite"`
+	Name       string           `xml:"name,attr"`
+	Package    string           `xml:"package,attr"`
+	ID         string           `xml:"id,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Skipped    int              `xml:"skipped,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Errors     int              `xml:"errors,attr"`
+	Properties *JUnitProperties `xml:"properties,omitempty"`
+	TestCases  []*JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitProperties represents the properties element of JUnit XML
+// format, which contains the metadata of the run.
+type JUnitProperties struct {
+	Properties []JUnitProperty `xml:"property"`
+}
+
+// JUnitProperty represents the property element of JUnit XML format.
+type JUnitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
 }
 
 // JUnitTestCase represents the testcase element of JUnit XML format.
+// Properties is the numbers of the iterations of the test case repeated
+// by --repeat.
 type JUnitTestCase struct {
-	XMLName   xml.Name      `xml:"testcase"`
-	Package   string        `xml:"package,attr"`
-	ClassName string        `xml:"classname,attr"`
-	Time      string        `xml:"time,attr"`
-	Failure   *JUnitFailure `xml:"failure"`
-	Skipped   *JUnitSkipped `xml:"skipped"`
-	Error     *JUnitError   `xml:"error"`
+	XMLName    xml.Name         `xml:"testcase"`
+	Package    string           `xml:"package,attr"`
+	ClassName  string           `xml:"classname,attr"`
+	Time       string           `xml:"time,attr"`
+	Properties *JUnitProperties `xml:"properties,omitempty"`
+	Failure    *JUnitFailure    `xml:"failure"`
+	Skipped    *JUnitSkipped    `xml:"skipped"`
+	Error      *JUnitError      `xml:"error"`
 }
 
 // JUnitFailure represents the failure element of JUnit XML format.
@@ -61,8 +80,28 @@ type JUnitError struct {
 // JUnitReport writes a file which contains the JUnit report generated
 // by test result of h2spec.
 func JUnitReport(groups []*spec.TestGroup, filePath string) error {
+	return writeJUnitReport(&Report{Results: convertReportResults(groups)}, filePath)
+}
+
+// writeJUnitReport writes the JUnit report of the report. The report is
+// marked with the reason if the run has been aborted, and each
+// testsuite has the properties of the metadata unless it is nil.
+func writeJUnitReport(r *Report, filePath string) error {
 	report := JUnitTestReport{
-		TestSuites: convertJUnitReport(groups),
+		TestSuites: convertJUnitReport(r.Results),
+	}
+	if r.Interrupted {
+		report.Aborted = abortReason(r)
+	}
+
+	if r.Metadata != nil {
+		props := &JUnitProperties{}
+		for _, field := range r.Metadata.Fields() {
+			props.Properties = append(props.Properties, JUnitProperty{Name: field.Name, Value: field.Value})
+		}
+		for _, ts := range report.TestSuites {
+			ts.Properties = props
+		}
 	}
 
 	buf, err := xml.MarshalIndent(report, "", "  ")
@@ -74,70 +113,106 @@ func JUnitReport(groups []*spec.TestGroup, filePath string) error {
 	return ioutil.WriteFile(filePath, []byte(body), os.ModePerm)
 }
 
-func convertJUnitReport(groups []*spec.TestGroup) []*JUnitTestSuite {
-	ts := make([]*JUnitTestSuite, 20)
+// JUnitReporter writes the JUnit report to the file when the run is
+// finished.
+type JUnitReporter struct {
+	spec.NopReporter
+	config *config.Config
+	path   string
+}
 
-	for _, tg := range groups {
-		tests := append(tg.Tests, tg.StrictTests...)
-		if len(tests) == 0 {
-			ts = append(ts, convertJUnitReport(tg.Groups)...)
-			continue
-		}
+// NewJUnitReporter returns a JUnitReporter that writes the report to
+// the specified path.
+func NewJUnitReporter(c *config.Config, filePath string) *JUnitReporter {
+	return &JUnitReporter{config: c, path: filePath}
+}
 
-		jts := &JUnitTestSuite{
-			Package:   tg.ID(),
-			Name:      fmt.Sprintf("%s. %s", tg.Section, tg.Name),
-			ID:        tg.Section,
-			Tests:     0,
-			Skipped:   0,
-			Failures:  0,
-			Errors:    0,
-			TestCases: make([]*JUnitTestCase, 20),
-		}
+// RunFinished writes the JUnit report unless no test case has run.
+func (r *JUnitReporter) RunFinished(summary *spec.RunSummary) error {
+	if summary.Total() == 0 && !summary.Interrupted {
+		return nil
+	}
 
-		for _, tc := range tests {
-			if tc.Result == nil {
-				continue
-			}
+	return writeJUnitReport(NewReport(r.config, summary), r.path)
+}
 
-			jtc := &JUnitTestCase{
-				Package:   tg.ID(),
-				ClassName: tc.Desc,
-				Time:      fmt.Sprintf("%.04f", tc.Result.Duration.Seconds()),
+// convertJUnitReport returns a testsuite for each group of the results,
+// in the order of the results.
+func convertJUnitReport(results []ReportResult) []*JUnitTestSuite {
+	ts := []*JUnitTestSuite{}
+
+	var jts *JUnitTestSuite
+	for _, res := range results {
+		if jts == nil || jts.Package != res.Group {
+			jts = &JUnitTestSuite{
+				Package: res.Group,
+				Name:    res.Section,
+				ID:      groupSection(res.Group),
 			}
+			ts = append(ts, jts)
+		}
 
-			jts.Tests += 1
-			if tc.Result.Skipped {
-				jts.Skipped += 1
-				jtc.Skipped = &JUnitSkipped{}
-			} else if tc.Result.Failed {
-				switch tc.Result.Error.(type) {
-				case spec.TestError:
-					jts.Failures += 1
-
-					err := tc.Result.Error.(*spec.TestError)
-					expected := strings.Join(err.Expected, "\n")
-					actual := err.Actual
-
-					jtc.Failure = &JUnitFailure{
-						Content: fmt.Sprintf("Expect:\n%s\nActual:\n%s", expected, actual),
-					}
-				default:
-					jts.Errors += 1
-
-					err := tc.Result.Error
-					jtc.Error = &JUnitError{
-						Content: err.Error(),
-					}
-				}
+		jtc := &JUnitTestCase{
+			Package:   res.Group,
+			ClassName: res.Description,
+			Time:      fmt.Sprintf("%.03f", float64(res.DurationMs)/1000),
+		}
+		if res.Iterations > 0 {
+			jtc.Properties = &JUnitProperties{
+				Properties: []JUnitProperty{
+					{Name: "iterations", Value: fmt.Sprintf("%d", res.Iterations)},
+					{Name: "passed_iterations", Value: fmt.Sprintf("%d", res.PassedIterations)},
+					{Name: "failed_iterations", Value: fmt.Sprintf("%d", res.FailedIterations)},
+					{Name: "flaky", Value: fmt.Sprintf("%v", res.Flaky)},
+				},
 			}
+		}
 
-			jts.TestCases = append(jts.TestCases, jtc)
+		jts.Tests += 1
+		switch {
+		case res.Verdict == "errored":
+			jts.Errors += 1
+
+			// The stack trace may contain "<autogenerated>", so the
+			// content is escaped.
+			content := res.Error
+			if res.Stack != "" {
+				content = fmt.Sprintf("%s\n%s", content, res.Stack)
+			}
+			var buf bytes.Buffer
+			xml.EscapeText(&buf, []byte(content))
+			jtc.Error = &JUnitError{Content: buf.String()}
+		case res.Verdict == "skipped":
+			jts.Skipped += 1
+			jtc.Skipped = &JUnitSkipped{Content: res.Actual}
+		case res.Verdict == "inconclusive":
+			jts.Skipped += 1
+			jtc.Skipped = &JUnitSkipped{
+				Content: "Inconclusive: no frame received within the timeout",
+			}
+		case res.Verdict == "failed" && res.Error != "":
+			jts.Errors += 1
+			jtc.Error = &JUnitError{Content: res.Error}
+		case res.Verdict == "failed":
+			jts.Failures += 1
+
+			expected := strings.Join(res.Expected, "\n")
+			jtc.Failure = &JUnitFailure{
+				Content: fmt.Sprintf("Severity: %s\nExpect:\n%s\nActual:\n%s", res.Severity, expected, res.Actual),
+			}
 		}
 
-		ts = append(ts, jts)
-		ts = append(ts, convertJUnitReport(tg.Groups)...)
+		jts.TestCases = append(jts.TestCases, jtc)
 	}
 
 	return ts
 }
+
+// groupSection returns the section of the ID of the group, such as
+// "6.5.2" of "http2/6.5.2".
+func groupSection(id string) string {
+	if i := strings.Index(id, "/"); i >= 0 {
+		return id[i+1:]
+	}
+	return id
+}
@@ -0,0 +1,52 @@
+package reporter
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// MarkdownReport writes a file which contains the report in Markdown
+// format, with the summary and the metadata of the run followed by a
+// table of the test cases.
+func MarkdownReport(r *Report, filePath string) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	fmt.Fprintf(w, "# h2spec report for %s\n\n", r.Target)
+	fmt.Fprintf(w, "%d tests, %d passed, %d skipped, %d failed in %.4f seconds\n\n",
+		len(r.Results), r.Count("passed")+r.Count("warning"), r.Count("skipped"), r.Count("failed"), r.Duration)
+	if r.Interrupted {
+		fmt.Fprintf(w, "Aborted: %s, %d tests not attempted\n\n", abortReason(r), r.NotAttempted)
+	}
+
+	for _, field := range r.Metadata.Fields() {
+		fmt.Fprintf(w, "- %s: %s\n", field.Name, markdownCell(field.Value))
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "| ID | Description | Severity | Verdict | Actual |")
+	fmt.Fprintln(w, "|----|-------------|----------|---------|--------|")
+	for _, res := range r.Results {
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %s |\n",
+			res.ID, markdownCell(res.Description), res.Severity, res.Verdict, markdownCell(res.Actual))
+	}
+
+	err = w.Flush()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// markdownCell returns the text escaped for a cell of Markdown table.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
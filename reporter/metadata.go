@@ -0,0 +1,143 @@
+package reporter
+
+import (
+	"net"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+)
+
+// MetadataSchemaVersion is the version of the schema of the metadata
+// in the JSON report, which is incremented when a field is changed or
+// removed.
+const MetadataSchemaVersion = 1
+
+// unknownCommit is the commit of the build without the commit given by
+// the linker flags.
+const unknownCommit = "(Unknown)"
+
+// ReportMetadata represents the provenance of the run, so that an
+// archived report tells what was tested by which build of h2spec.
+type ReportMetadata struct {
+	SchemaVersion int               `json:"schema_version"`
+	Version       string            `json:"h2spec_version"`
+	Revision      string            `json:"h2spec_revision,omitempty"`
+	GoVersion     string            `json:"go_version"`
+	OS            string            `json:"os"`
+	Hostname      string            `json:"hostname,omitempty"`
+	TargetIP      string            `json:"target_ip,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+}
+
+// MetadataField represents a field of the metadata in the reports
+// other than JSON.
+type MetadataField struct {
+	Name  string
+	Value string
+}
+
+// NewReportMetadata returns the metadata of the run of the
+// configuration. The revision is taken from the build information if
+// the commit is not given, and the target IP is the address of the
+// connection of the capability detection.
+func NewReportMetadata(c *config.Config) *ReportMetadata {
+	revision := c.Commit
+	if revision == "" || revision == unknownCommit {
+		revision = buildRevision()
+	}
+
+	hostname, _ := os.Hostname()
+
+	m := &ReportMetadata{
+		SchemaVersion: MetadataSchemaVersion,
+		Version:       c.Version,
+		Revision:      revision,
+		GoVersion:     runtime.Version(),
+		OS:            runtime.GOOS + "/" + runtime.GOARCH,
+		Hostname:      hostname,
+		Labels:        c.Labels,
+	}
+
+	if caps := spec.DetectedCapabilities(c); caps != nil {
+		host, _, err := net.SplitHostPort(caps.Address)
+		if err == nil {
+			m.TargetIP = host
+		}
+	}
+
+	return m
+}
+
+// buildRevision returns the revision of the version control recorded
+// in the build information, or empty if it is not recorded.
+func buildRevision() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+
+	revision, modified := "", false
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			modified = s.Value == "true"
+		}
+	}
+
+	if revision != "" && modified {
+		revision += "-dirty"
+	}
+
+	return revision
+}
+
+// Fields returns the fields of the metadata that are known, followed by
+// a field of each label sorted by the key.
+func (m *ReportMetadata) Fields() []MetadataField {
+	fields := []MetadataField{}
+	for _, field := range []MetadataField{
+		{"h2spec_version", m.Version},
+		{"h2spec_revision", m.Revision},
+		{"go_version", m.GoVersion},
+		{"os", m.OS},
+		{"hostname", m.Hostname},
+		{"target_ip", m.TargetIP},
+	} {
+		if field.Value != "" {
+			fields = append(fields, field)
+		}
+	}
+
+	for _, key := range m.labelKeys() {
+		fields = append(fields, MetadataField{"label." + key, m.Labels[key]})
+	}
+
+	return fields
+}
+
+// labelList returns the labels as "key=value" separated by ";", which
+// is written in a column of the CSV report.
+func (m *ReportMetadata) labelList() string {
+	list := []string{}
+	for _, key := range m.labelKeys() {
+		list = append(list, key+"="+m.Labels[key])
+	}
+	return strings.Join(list, ";")
+}
+
+// labelKeys returns the keys of the labels in sorted order.
+func (m *ReportMetadata) labelKeys() []string {
+	keys := []string{}
+	for key := range m.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,279 @@
+package reporter
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+)
+
+// Report represents the results of a run shared by the writers of the
+// output files, so that every format is written from the same results.
+type Report struct {
+	Target       string          `json:"target"`
+	Metadata     *ReportMetadata `json:"metadata"`
+	Duration     float64         `json:"duration_seconds"`
+	Interrupted  bool            `json:"interrupted,omitempty"`
+	AbortReason  string          `json:"abort_reason,omitempty"`
+	NotAttempted int             `json:"not_attempted,omitempty"`
+	Server       *ReportServer   `json:"server,omitempty"`
+	RTT          *ReportRTT      `json:"rtt,omitempty"`
+	Results      []ReportResult  `json:"results"`
+}
+
+// ReportServer represents what the capability detection learned about
+// the server, so that the results can be correlated with the build of
+// the server under test.
+type ReportServer struct {
+	Address  string                   `json:"address"`
+	Header   string                   `json:"server_header,omitempty"`
+	TLS      *spec.TLSParameters      `json:"tls,omitempty"`
+	Settings []spec.AdvertisedSetting `json:"settings"`
+}
+
+// newReportServer returns the ReportServer of the detected
+// capabilities, or nil if the detection failed.
+func newReportServer(caps *spec.Capabilities) *ReportServer {
+	if caps == nil {
+		return nil
+	}
+
+	return &ReportServer{
+		Address:  caps.Address,
+		Header:   caps.Server,
+		TLS:      caps.TLS,
+		Settings: caps.AdvertisedSettings(),
+	}
+}
+
+// ReportRTT represents the round-trip time measured by the capability
+// detection, so that the timeout-based verdicts can be put in
+// context. SuggestedTimeoutMs is the timeout suggested from the median
+// round-trip time if the timeout of the run is too small for it.
+type ReportRTT struct {
+	MinMs              float64 `json:"min_ms"`
+	MedianMs           float64 `json:"median_ms"`
+	Samples            int     `json:"samples"`
+	TimeoutMs          int64   `json:"timeout_ms"`
+	SuggestedTimeoutMs int64   `json:"suggested_timeout_ms,omitempty"`
+}
+
+// newReportRTT returns the ReportRTT of the detected capabilities, or
+// nil if the round-trip time was not measured.
+func newReportRTT(c *config.Config, caps *spec.Capabilities) *ReportRTT {
+	if caps == nil || caps.RTT.Samples == 0 {
+		return nil
+	}
+
+	return &ReportRTT{
+		MinMs:              float64(caps.RTT.Min) / float64(time.Millisecond),
+		MedianMs:           float64(caps.RTT.Median) / float64(time.Millisecond),
+		Samples:            caps.RTT.Samples,
+		TimeoutMs:          c.Timeout.Milliseconds(),
+		SuggestedTimeoutMs: suggestedTimeout(caps.RTT, c.Timeout).Milliseconds(),
+	}
+}
+
+// suggestedTimeout returns the timeout in whole seconds of which the
+// median round-trip time is at most slowRTTRatio, or zero if the
+// specified timeout is already large enough.
+func suggestedTimeout(rtt spec.RoundTripTime, timeout time.Duration) time.Duration {
+	if float64(rtt.Median) <= float64(timeout)*slowRTTRatio {
+		return 0
+	}
+
+	d := time.Duration(float64(rtt.Median) / slowRTTRatio)
+	return (d + time.Second - 1).Truncate(time.Second)
+}
+
+// ReportResult represents the result of a test case in the report.
+type ReportResult struct {
+	ID          string   `json:"id"`
+	Group       string   `json:"group"`
+	Section     string   `json:"section"`
+	Description string   `json:"description"`
+	Requirement string   `json:"requirement"`
+	Level       string   `json:"level,omitempty"`
+	Severity    string   `json:"severity"`
+	Verdict     string   `json:"verdict"`
+	Expected    []string `json:"expected,omitempty"`
+	Actual      string   `json:"actual,omitempty"`
+	DurationMs  int64    `json:"duration_ms"`
+	TimeoutMs   int64    `json:"timeout_ms,omitempty"`
+	Attempts    int      `json:"attempts,omitempty"`
+
+	// Iterations is the number of times the test case was repeated by
+	// --repeat, with the numbers of the iterations that passed and
+	// failed. Flaky is true if the iterations had mixed verdicts.
+	Iterations       int  `json:"iterations,omitempty"`
+	PassedIterations int  `json:"passed_iterations,omitempty"`
+	FailedIterations int  `json:"failed_iterations,omitempty"`
+	Flaky            bool `json:"flaky,omitempty"`
+
+	// Error is the error that stopped the test case before the server
+	// was verified, such as a failure to connect or a panic.
+	Error string `json:"error,omitempty"`
+
+	// Stack is the stack trace of the panic of the errored test case.
+	Stack string `json:"stack,omitempty"`
+
+	Traffic spec.Traffic `json:"traffic"`
+
+	ExpectedAttrs []spec.FrameAttributes `json:"expected_attributes,omitempty"`
+	ActualAttrs   *spec.FrameAttributes  `json:"actual_attributes,omitempty"`
+}
+
+// NewReport returns the report of the run of the summary.
+func NewReport(c *config.Config, summary *spec.RunSummary) *Report {
+	caps := spec.DetectedCapabilities(c)
+
+	return &Report{
+		Target:       c.Addr(),
+		Metadata:     NewReportMetadata(c),
+		Duration:     summary.Duration.Seconds(),
+		Interrupted:  summary.Interrupted,
+		AbortReason:  summary.AbortReason,
+		NotAttempted: summary.NotAttempted,
+		Server:       newReportServer(caps),
+		RTT:          newReportRTT(c, caps),
+		Results:      convertReportResults(summary.Groups),
+	}
+}
+
+func convertReportResults(groups []*spec.TestGroup) []ReportResult {
+	results := []ReportResult{}
+
+	for _, tg := range groups {
+		tests := append(tg.Tests, tg.StrictTests...)
+
+		for _, tc := range tests {
+			tr := tc.Result
+			if tr == nil {
+				continue
+			}
+
+			res := ReportResult{
+				ID:          fmt.Sprintf("%s/%d", tg.ID(), tr.Sequence),
+				Group:       tg.ID(),
+				Section:     fmt.Sprintf("%s. %s", tg.Section, tg.Name),
+				Description: tc.Desc,
+				Requirement: tc.Requirement,
+				Level:       requirementLevel(tc),
+				Severity:    tc.Severity.String(),
+				Verdict:     tr.Verdict(),
+				Actual:      actual(tr),
+				DurationMs:  tr.Duration.Milliseconds(),
+				TimeoutMs:   tr.Timeout.Milliseconds(),
+				Attempts:    tr.Attempts,
+				Traffic:     tr.Traffic,
+
+				Iterations:       tr.Iterations,
+				PassedIterations: tr.PassedIterations,
+				FailedIterations: tr.FailedIterations,
+				Flaky:            tr.Flaky,
+			}
+
+			switch err := tr.Error.(type) {
+			case *spec.TestError:
+				res.Expected = err.Expected
+				res.ExpectedAttrs = err.ExpectedAttrs
+				res.ActualAttrs = err.ActualAttrs
+			case *spec.TestPanic:
+				res.Error = err.Error()
+				res.Stack = string(err.Stack)
+			default:
+				if tr.Failed && err != nil {
+					res.Error = err.Error()
+				}
+			}
+
+			results = append(results, res)
+		}
+
+		results = append(results, convertReportResults(tg.Groups)...)
+	}
+
+	return results
+}
+
+// Count returns the number of the results of the specified verdict.
+func (r *Report) Count(verdict string) int {
+	count := 0
+	for _, res := range r.Results {
+		if res.Verdict == verdict {
+			count++
+		}
+	}
+	return count
+}
+
+// reportWriter writes the report to the file of the path.
+type reportWriter func(r *Report, path string) error
+
+// reportWriters is the writer of each extension of the output files.
+var reportWriters = map[string]reportWriter{
+	".json": JSONReport,
+	".xml":  writeJUnitReport,
+	".html": HTMLReport,
+	".htm":  HTMLReport,
+	".tap":  TAPReport,
+	".csv":  writeCSVReport,
+	".md":   MarkdownReport,
+}
+
+// OutputReporter writes the report to the output files when the run is
+// finished. The format of each file is inferred from its extension.
+type OutputReporter struct {
+	spec.NopReporter
+	config  *config.Config
+	paths   []string
+	writers []reportWriter
+}
+
+// NewOutputReporter returns an OutputReporter that writes the report to
+// the specified paths. Error is returned if the format of any path is
+// unknown.
+func NewOutputReporter(c *config.Config, paths []string) (*OutputReporter, error) {
+	r := &OutputReporter{config: c}
+
+	for _, path := range paths {
+		ext := strings.ToLower(filepath.Ext(path))
+		w, ok := reportWriters[ext]
+		if !ok {
+			return nil, fmt.Errorf("unknown format of output file: %s (json, xml, html, tap, csv or md)", path)
+		}
+
+		r.paths = append(r.paths, path)
+		r.writers = append(r.writers, w)
+	}
+
+	return r, nil
+}
+
+// RunFinished writes the report to each output file unless no test
+// case has run. All the files are written even if some of them fail,
+// and the failures are returned as an error.
+func (r *OutputReporter) RunFinished(summary *spec.RunSummary) error {
+	if summary.Total() == 0 && !summary.Interrupted {
+		return nil
+	}
+
+	report := NewReport(r.config, summary)
+
+	failures := []string{}
+	for i, path := range r.paths {
+		err := r.writers[i](report, path)
+		if err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to write output files: %s", strings.Join(failures, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package reporter
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+)
+
+// outputSummary returns the summary of a run with a passed and a failed
+// test case.
+func outputSummary() *spec.RunSummary {
+	groups := testGroups(
+		&spec.TestResult{},
+		&spec.TestResult{Failed: true, Error: &spec.TestError{Actual: "Connection closed"}},
+	)
+	groups[0].PassedCount = 1
+	groups[0].FailedCount = 1
+
+	return &spec.RunSummary{Groups: groups}
+}
+
+func TestNewOutputReporter(t *testing.T) {
+	c := &config.Config{Host: "127.0.0.1", Port: 443}
+
+	tests := []struct {
+		path string
+		ok   bool
+	}{
+		{path: "report.json", ok: true},
+		{path: "report.XML", ok: true},
+		{path: "dir.v1/report.htm", ok: true},
+		{path: "report.txt", ok: false},
+		{path: "report", ok: false},
+	}
+
+	for _, tt := range tests {
+		_, err := NewOutputReporter(c, []string{"report.md", tt.path})
+		if (err == nil) != tt.ok {
+			t.Errorf("%s - expected ok:%v, actual:%v", tt.path, tt.ok, err)
+		}
+	}
+}
+
+func TestOutputReporter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "h2spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Each writer is expected to write its own format with both of the
+	// test cases.
+	expected := map[string][]string{
+		"report.json": {`"results"`, `"id": "test/1/2"`, `"verdict": "failed"`},
+		"report.xml":  {"<testsuites", "<failure"},
+		"report.html": {"<html>", "Sends a frame", `<h3 class="severity-medium">medium (1)</h3>`},
+		"report.tap":  {"TAP version 13", "ok 1 - test/1/1", "not ok 2 - test/1/2"},
+		"report.csv":  {"id,", "test/1/1,", "test/1/2,"},
+		"report.md":   {"| ID |", "| test/1/2 |", "2 tests, 1 passed, 0 skipped, 1 failed"},
+	}
+
+	// The writer of the path in the missing directory fails, and the
+	// rest of the files are still written.
+	missing := filepath.Join(dir, "missing", "report.json")
+	paths := []string{missing}
+	for name := range expected {
+		paths = append(paths, filepath.Join(dir, name))
+	}
+
+	c := &config.Config{Host: "127.0.0.1", Port: 443}
+	r, err := NewOutputReporter(c, paths)
+	if err != nil {
+		t.Fatalf("NewOutputReporter() error: %v", err)
+	}
+
+	err = r.RunFinished(outputSummary())
+	if err == nil || !strings.Contains(err.Error(), missing) {
+		t.Errorf("RunFinished() - expected error of %s, actual:%v", missing, err)
+	}
+
+	for name, contents := range expected {
+		out, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Errorf("%s - not written: %v", name, err)
+			continue
+		}
+
+		for _, content := range contents {
+			if !strings.Contains(string(out), content) {
+				t.Errorf("%s - expected to contain %q:\n%s", name, content, out)
+			}
+		}
+	}
+}
@@ -2,6 +2,8 @@ package reporter
 
 import (
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/summerwind/h2spec/log"
 	"github.com/summerwind/h2spec/spec"
@@ -10,17 +12,137 @@ import (
 // Summary outputs the summary of test result that includes
 // the number of passsed, skipped and failed.
 func Summary(groups []*spec.TestGroup) {
-	var passed, failed, skipped, total int
+	var passed, failed, skipped, warned, inconclusive, goAwayOmitted, flaky, errored, total int
 
 	for _, tg := range groups {
 		passed += tg.PassedCount
 		failed += tg.FailedCount
 		skipped += tg.SkippedCount
+		warned += tg.WarnedCount
+		inconclusive += tg.InconclusiveCount
+		goAwayOmitted += tg.GoAwayOmittedCount
+		flaky += tg.FlakyCount
+		errored += tg.ErroredCount
 	}
 
-	total = passed + failed + skipped
+	total = passed + failed + skipped + inconclusive + errored
 	tmp := "%d tests, %d passed, %d skipped, %d failed"
-	log.Println(fmt.Sprintf(tmp, total, passed, skipped, failed))
+	summary := fmt.Sprintf(tmp, total, passed, skipped, failed)
+	if inconclusive > 0 {
+		summary = fmt.Sprintf("%s, %d inconclusive", summary, inconclusive)
+	}
+	if errored > 0 {
+		summary = fmt.Sprintf("%s, %d errored", summary, errored)
+	}
+	if flaky > 0 {
+		summary = fmt.Sprintf("%s, %d flaky", summary, flaky)
+	}
+	if warned > 0 && goAwayOmitted > 0 {
+		summary = fmt.Sprintf("%s (%d warnings, %d GOAWAY omitted)", summary, warned, goAwayOmitted)
+	} else if warned > 0 {
+		summary = fmt.Sprintf("%s (%d warnings)", summary, warned)
+	}
+	log.Println(summary)
+}
+
+// sectionCount represents the number of test results of a section.
+type sectionCount struct {
+	label                             string
+	passed, failed, skipped, timedOut int
+}
+
+// total returns the number of test results of the section.
+func (sc *sectionCount) total() int {
+	return sc.passed + sc.failed + sc.skipped + sc.timedOut
+}
+
+// add accounts the test results of the specified section.
+func (sc *sectionCount) add(other *sectionCount) {
+	sc.passed += other.passed
+	sc.failed += other.failed
+	sc.skipped += other.skipped
+	sc.timedOut += other.timedOut
+}
+
+// passRate returns the percentage of the passed tests in the tests
+// that were not skipped.
+func (sc *sectionCount) passRate() string {
+	ran := sc.passed + sc.failed + sc.timedOut
+	if ran == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", float64(sc.passed)*100/float64(ran))
+}
+
+// SectionSummary outputs a table of the number of test results for
+// each top-level section of the specs, followed by the grand total.
+// The sections without any test run are omitted. An inconclusive test
+// is counted as a timeout, and an errored test as a failure.
+func SectionSummary(groups []*spec.TestGroup) {
+	rows := []*sectionCount{}
+	total := &sectionCount{label: "Total"}
+
+	for _, root := range groups {
+		for _, tg := range root.Groups {
+			sc := &sectionCount{label: fmt.Sprintf("%s %s", tg.ID(), tg.Name)}
+			countSection(tg, sc)
+			if sc.total() == 0 {
+				continue
+			}
+
+			rows = append(rows, sc)
+			total.add(sc)
+		}
+	}
+
+	if len(rows) == 0 {
+		return
+	}
+
+	width := len(total.label)
+	for _, sc := range rows {
+		if len(sc.label) > width {
+			width = len(sc.label)
+		}
+	}
+
+	format := fmt.Sprintf("%%-%ds  %%6s  %%6s  %%7s  %%7s  %%9s", width)
+	log.Println(fmt.Sprintf(format, "Section", "Passed", "Failed", "Skipped", "Timeout", "Pass rate"))
+	for _, sc := range append(rows, total) {
+		log.Println(fmt.Sprintf(format, sc.label,
+			fmt.Sprint(sc.passed), fmt.Sprint(sc.failed), fmt.Sprint(sc.skipped), fmt.Sprint(sc.timedOut), sc.passRate()))
+	}
+	log.PrintBlankLine()
+}
+
+// countSection accounts the test results of the group and its
+// sub groups.
+func countSection(tg *spec.TestGroup, sc *sectionCount) {
+	tests := append(tg.Tests, tg.StrictTests...)
+
+	for _, tc := range tests {
+		tr := tc.Result
+		if tr == nil {
+			continue
+		}
+
+		switch {
+		case tr.Errored:
+			sc.failed++
+		case tr.Skipped:
+			sc.skipped++
+		case tr.Inconclusive:
+			sc.timedOut++
+		case tr.Failed:
+			sc.failed++
+		default:
+			sc.passed++
+		}
+	}
+
+	for _, g := range tg.Groups {
+		countSection(g, sc)
+	}
 }
 
 // FailedTests outputs the report of failed tests.
@@ -33,7 +155,7 @@ func FailedTests(groups []*spec.TestGroup) {
 }
 
 func printFailed(tg *spec.TestGroup) {
-	if tg.FailedCount == 0 {
+	if tg.FailedCount == 0 && tg.ErroredCount == 0 {
 		return
 	}
 
@@ -51,7 +173,7 @@ func printFailed(tg *spec.TestGroup) {
 			continue
 		}
 
-		if tc.Result.Failed {
+		if tc.Result.Failed || tc.Result.Errored {
 			tc.Result.Print()
 			failed = true
 		}
@@ -65,3 +187,121 @@ func printFailed(tg *spec.TestGroup) {
 		printFailed(g)
 	}
 }
+
+// FlakyTests outputs the list of the repeated test cases whose
+// iterations had mixed verdicts.
+func FlakyTests(groups []*spec.TestGroup) {
+	flaky := 0
+	for _, tg := range groups {
+		flaky += tg.FlakyCount
+	}
+
+	if flaky == 0 {
+		return
+	}
+
+	log.Println("Flaky tests:")
+	for _, tg := range groups {
+		printFlaky(tg)
+	}
+	log.PrintBlankLine()
+}
+
+func printFlaky(tg *spec.TestGroup) {
+	if tg.FlakyCount == 0 {
+		return
+	}
+
+	tests := append(tg.Tests, tg.StrictTests...)
+	for _, tc := range tests {
+		tr := tc.Result
+		if tr == nil || !tr.Flaky {
+			continue
+		}
+
+		log.Println(fmt.Sprintf("  %s/%d: %s (passed:%d, failed:%d of %d iterations)",
+			tg.ID(), tr.Sequence, tc.Desc, tr.PassedIterations, tr.FailedIterations, tr.Iterations))
+	}
+
+	for _, g := range tg.Groups {
+		printFlaky(g)
+	}
+}
+
+// SettingsAckLatency outputs the distribution of the time the server
+// took to acknowledge the SETTINGS frame of the client connection
+// preface on the connection of each test case. The connections that a
+// test case opens in addition to its own are not measured.
+func SettingsAckLatency(groups []*spec.TestGroup) {
+	latencies := []time.Duration{}
+	for _, tg := range groups {
+		latencies = collectSettingsAckLatency(tg, latencies)
+	}
+
+	if len(latencies) == 0 {
+		return
+	}
+
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+
+	min := latencies[0].Round(time.Microsecond)
+	median := latencies[len(latencies)/2]
+	if len(latencies)%2 == 0 {
+		median = (latencies[len(latencies)/2-1] + median) / 2
+	}
+	median = median.Round(time.Microsecond)
+	max := latencies[len(latencies)-1].Round(time.Microsecond)
+	log.Println(fmt.Sprintf("SETTINGS ACK latency: min %s, median %s, max %s (%d connections)", min, median, max, len(latencies)))
+}
+
+// collectSettingsAckLatency appends the SETTINGS ACK latency of the
+// test results of the group and its sub groups.
+func collectSettingsAckLatency(tg *spec.TestGroup, latencies []time.Duration) []time.Duration {
+	tests := append(tg.Tests, tg.StrictTests...)
+
+	for _, tc := range tests {
+		tr := tc.Result
+		if tr == nil || tr.SettingsAckLatency == 0 {
+			continue
+		}
+		latencies = append(latencies, tr.SettingsAckLatency)
+	}
+
+	for _, g := range tg.Groups {
+		latencies = collectSettingsAckLatency(g, latencies)
+	}
+
+	return latencies
+}
+
+// Traffic outputs the total traffic of the test cases.
+func Traffic(groups []*spec.TestGroup) {
+	total := spec.Traffic{}
+	for _, tg := range groups {
+		collectTraffic(tg, &total)
+	}
+
+	if total.Connections == 0 {
+		return
+	}
+
+	log.Println(fmt.Sprintf("Traffic: %s", total))
+}
+
+// collectTraffic adds the traffic of the test results of the group and
+// its sub groups to the total.
+func collectTraffic(tg *spec.TestGroup, total *spec.Traffic) {
+	tests := append(tg.Tests, tg.StrictTests...)
+
+	for _, tc := range tests {
+		if tc.Result != nil {
+			total.Add(tc.Result.Traffic)
+		}
+	}
+
+	for _, g := range tg.Groups {
+		collectTraffic(g, total)
+	}
+}
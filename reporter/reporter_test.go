@@ -0,0 +1,184 @@
+package reporter
+
+import (
+	"encoding/csv"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/summerwind/h2spec/spec"
+)
+
+// testGroups returns the specs that have a group with the test cases of
+// the specified results.
+func testGroups(results ...*spec.TestResult) []*spec.TestGroup {
+	root := &spec.TestGroup{Key: "test", Name: "Test Spec"}
+	tg := &spec.TestGroup{Key: "test", Section: "1", Name: "Test Section"}
+	root.AddTestGroup(tg)
+
+	for i, tr := range results {
+		tc := &spec.TestCase{
+			Desc:        "Sends a frame",
+			Requirement: "The endpoint MUST respond.",
+		}
+		tg.AddTestCase(tc)
+
+		tr.TestCase = tc
+		tr.Sequence = i + 1
+		tc.Result = tr
+	}
+
+	return []*spec.TestGroup{root}
+}
+
+// captureOutput returns the output written to the standard output by
+// the function.
+func captureOutput(t *testing.T, f func()) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() {
+		os.Stdout = stdout
+	}()
+
+	done := make(chan string)
+	go func() {
+		out, _ := ioutil.ReadAll(r)
+		done <- string(out)
+	}()
+
+	f()
+	w.Close()
+
+	return <-done
+}
+
+func TestCSVReportQuoting(t *testing.T) {
+	actual := "DATA Frame (length:1, flags:0x00)\nthen \"GOAWAY\" Frame"
+	groups := testGroups(&spec.TestResult{
+		Failed: true,
+		Error:  &spec.TestError{Actual: actual},
+	})
+	groups[0].Groups[0].Tests[0].Desc = "Sends a frame, then \"closes\"\nthe connection"
+
+	dir, err := ioutil.TempDir("", "h2spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "report.csv")
+	r := &Report{
+		Target:   "127.0.0.1:443",
+		Metadata: &ReportMetadata{Version: "1.0", Labels: map[string]string{"env": "a,b"}},
+		Results:  convertReportResults(groups),
+	}
+	err = writeCSVReport(r, path)
+	if err != nil {
+		t.Fatalf("writeCSVReport() error: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error: %v", err)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("records - expected:2, actual:%d", len(records))
+	}
+
+	row := map[string]string{}
+	for i, name := range records[0] {
+		row[name] = records[1][i]
+	}
+
+	expected := map[string]string{
+		"id":          "test/1/1",
+		"description": "Sends a frame, then \"closes\"\nthe connection",
+		"requirement": "MUST",
+		"verdict":     "failed",
+		"actual":      actual,
+		"labels":      "env=a,b",
+	}
+	for name, value := range expected {
+		if row[name] != value {
+			t.Errorf("%s - expected:%q, actual:%q", name, value, row[name])
+		}
+	}
+}
+
+func TestSectionSummary(t *testing.T) {
+	groups := testGroups(
+		&spec.TestResult{},
+		&spec.TestResult{Failed: true},
+		&spec.TestResult{Errored: true},
+		&spec.TestResult{Skipped: true},
+		&spec.TestResult{Inconclusive: true},
+	)
+
+	// The section without any test run is omitted.
+	groups[0].AddTestGroup(&spec.TestGroup{Key: "test", Section: "2", Name: "Empty Section"})
+
+	out := captureOutput(t, func() {
+		SectionSummary(groups)
+	})
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("lines - expected:3, actual:%d\n%s", len(lines), out)
+	}
+
+	expected := []string{
+		"test/1 Test Section 1 2 1 1 25.0%",
+		"Total 1 2 1 1 25.0%",
+	}
+	for i, row := range expected {
+		if actual := strings.Join(strings.Fields(lines[i+1]), " "); actual != row {
+			t.Errorf("row - expected:%s, actual:%s", row, actual)
+		}
+	}
+}
+
+func TestSectionSummaryWithoutTests(t *testing.T) {
+	groups := testGroups()
+
+	out := captureOutput(t, func() {
+		SectionSummary(groups)
+	})
+
+	if out != "" {
+		t.Errorf("output - expected:empty, actual:%q", out)
+	}
+}
+
+func TestSuggestedTimeout(t *testing.T) {
+	tests := []struct {
+		median   time.Duration
+		expected time.Duration
+	}{
+		{median: 100 * time.Millisecond, expected: 0},
+		{median: 500 * time.Millisecond, expected: 0},
+		{median: 600 * time.Millisecond, expected: 3 * time.Second},
+		{median: 1500 * time.Millisecond, expected: 6 * time.Second},
+	}
+
+	for _, tt := range tests {
+		rtt := spec.RoundTripTime{Median: tt.median, Samples: 3}
+		if actual := suggestedTimeout(rtt, 2*time.Second); actual != tt.expected {
+			t.Errorf("%s - expected:%s, actual:%s", tt.median, tt.expected, actual)
+		}
+	}
+}
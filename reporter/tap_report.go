@@ -0,0 +1,72 @@
+package reporter
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TAPReport writes a file which contains the report in the format of
+// Test Anything Protocol version 13. The skipped test cases are marked
+// with SKIP directive, and the details of the test cases that did not
+// pass are written as YAML blocks. The metadata of the run is written
+// as diagnostic lines following the plan.
+func TAPReport(r *Report, filePath string) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	fmt.Fprintln(w, "TAP version 13")
+	fmt.Fprintf(w, "1..%d\n", len(r.Results))
+	for _, field := range r.Metadata.Fields() {
+		fmt.Fprintf(w, "# %s: %s\n", field.Name, field.Value)
+	}
+
+	for i, res := range r.Results {
+		desc := strings.ReplaceAll(res.Description, "#", "\\#")
+
+		switch res.Verdict {
+		case "passed", "warning":
+			fmt.Fprintf(w, "ok %d - %s %s\n", i+1, res.ID, desc)
+		case "skipped":
+			fmt.Fprintf(w, "ok %d - %s %s # SKIP %s\n", i+1, res.ID, desc, res.Actual)
+		default:
+			fmt.Fprintf(w, "not ok %d - %s %s\n", i+1, res.ID, desc)
+			fmt.Fprintln(w, "  ---")
+			fmt.Fprintf(w, "  verdict: %s\n", res.Verdict)
+			fmt.Fprintf(w, "  severity: %s\n", res.Severity)
+			fmt.Fprintf(w, "  requirement: %q\n", res.Requirement)
+			if len(res.Expected) > 0 {
+				fmt.Fprintln(w, "  expected:")
+				for _, ex := range res.Expected {
+					fmt.Fprintf(w, "    - %q\n", ex)
+				}
+			}
+			fmt.Fprintf(w, "  actual: %q\n", res.Actual)
+			fmt.Fprintln(w, "  ...")
+		}
+	}
+
+	if r.Interrupted {
+		fmt.Fprintf(w, "Bail out! %s\n", abortReason(r))
+	}
+
+	err = w.Flush()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// abortReason returns the reason of the interrupted run of the report.
+func abortReason(r *Report) string {
+	if r.AbortReason != "" {
+		return r.AbortReason
+	}
+	return "interrupted"
+}
@@ -0,0 +1,75 @@
+package rfc7838
+
+import (
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+	"golang.org/x/net/http2"
+)
+
+func TheALTSVCHTTP2Frame() *spec.TestGroup {
+	tg := NewTestGroup("4", "The ALTSVC HTTP/2 Frame")
+
+	// An ALTSVC frame on stream 0 with empty (length 0) "Origin"
+	// information is invalid and MUST be ignored. An ALTSVC frame on a
+	// stream other than stream 0 containing non-empty "Origin"
+	// information is invalid and MUST be ignored.
+	//
+	// The Alt-Svc-Field-Value field's contents MUST be the same as
+	// the Alt-Svc header field value.
+	//
+	// Note: This test case verifies the ALTSVC frames sent by the
+	// server during a request, and is skipped if the server does not
+	// send any.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Receives ALTSVC frames during a request",
+		Requirement: "The endpoint MUST send the ALTSVC frames with valid origin and field value.",
+		Requires:    []spec.Capability{spec.CapabilityAltSvc},
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyAltSvcFrames(conn, streamID)
+		},
+	})
+
+	// The ALTSVC frame is intended for receipt by clients. A device
+	// acting as a server MUST ignore it.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends an ALTSVC frame",
+		Requirement: "The endpoint MUST ignore the ALTSVC frame.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// ALTSVC frame:
+			// origin: "https://example.com", field_value: h2=":443"
+			origin := "https://example.com"
+			payload := []byte{0x00, byte(len(origin))}
+			payload = append(payload, origin...)
+			payload = append(payload, "h2=\":443\""...)
+			conn.WriteRawFrame(spec.FrameAltSvc, 0x0, 0, payload)
+
+			data := [8]byte{'h', '2', 's', 'p', 'e', 'c'}
+			conn.WritePing(false, data)
+
+			return spec.VerifyPingFrameWithAck(conn, data)
+		},
+	})
+
+	return tg
+}
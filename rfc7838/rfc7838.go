@@ -0,0 +1,24 @@
+package rfc7838
+
+import "github.com/summerwind/h2spec/spec"
+
+var key = "rfc7838"
+
+func NewTestGroup(section string, name string) *spec.TestGroup {
+	return &spec.TestGroup{
+		Key:     key,
+		Section: section,
+		Name:    name,
+	}
+}
+
+func Spec() *spec.TestGroup {
+	tg := &spec.TestGroup{
+		Key:  key,
+		Name: "RFC 7838: HTTP Alternative Services",
+	}
+
+	tg.AddTestGroup(TheALTSVCHTTP2Frame())
+
+	return tg
+}
@@ -0,0 +1,78 @@
+package rfc8336
+
+import (
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+	"golang.org/x/net/http2"
+)
+
+func TheORIGINHTTP2Frame() *spec.TestGroup {
+	tg := NewTestGroup("2", "The ORIGIN HTTP/2 Frame")
+
+	// The ORIGIN frame type is 0xc (decimal 12) and contains zero or
+	// more instances of the Origin-Entry field.
+	//
+	// The ORIGIN frame does not define any flags. However, future
+	// updates to this specification MAY define flags.
+	//
+	// The ORIGIN frame MUST be sent on stream 0; an ORIGIN frame on
+	// any other stream is invalid and MUST be ignored.
+	//
+	// Note: This test case verifies the ORIGIN frames sent by the
+	// server during a request, and is skipped if the server does not
+	// send any. The flags are not verified.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Receives ORIGIN frames during a request",
+		Requirement: "The endpoint MUST send the ORIGIN frames on stream 0 with valid Origin-Entry sequence.",
+		Requires:    []spec.Capability{spec.CapabilityOrigin},
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyOriginFrames(conn, streamID)
+		},
+	})
+
+	// Implementations MUST ignore and discard any frame that has
+	// a type that is unknown.
+	//
+	// Note: The ORIGIN frame is defined only for servers to send, so
+	// the server must ignore the frame sent by the client.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends an ORIGIN frame",
+		Requirement: "The endpoint MUST ignore the ORIGIN frame.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// ORIGIN frame:
+			// origin: "https://example.com"
+			origin := "https://example.com"
+			payload := []byte{0x00, byte(len(origin))}
+			payload = append(payload, origin...)
+			conn.WriteRawFrame(spec.FrameOrigin, 0x0, 0, payload)
+
+			data := [8]byte{'h', '2', 's', 'p', 'e', 'c'}
+			conn.WritePing(false, data)
+
+			return spec.VerifyPingFrameWithAck(conn, data)
+		},
+	})
+
+	return tg
+}
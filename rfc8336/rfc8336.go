@@ -0,0 +1,24 @@
+package rfc8336
+
+import "github.com/summerwind/h2spec/spec"
+
+var key = "rfc8336"
+
+func NewTestGroup(section string, name string) *spec.TestGroup {
+	return &spec.TestGroup{
+		Key:     key,
+		Section: section,
+		Name:    name,
+	}
+}
+
+func Spec() *spec.TestGroup {
+	tg := &spec.TestGroup{
+		Key:  key,
+		Name: "RFC 8336: The ORIGIN HTTP/2 Frame",
+	}
+
+	tg.AddTestGroup(TheORIGINHTTP2Frame())
+
+	return tg
+}
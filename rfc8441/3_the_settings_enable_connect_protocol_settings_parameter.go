@@ -0,0 +1,40 @@
+package rfc8441
+
+import (
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+)
+
+func TheSettingsEnableConnectProtocolParameter() *spec.TestGroup {
+	tg := NewTestGroup("3", "The SETTINGS_ENABLE_CONNECT_PROTOCOL SETTINGS Parameter")
+
+	// The value of the parameter MUST be 0 or 1.
+	//
+	// Upon receipt of SETTINGS_ENABLE_CONNECT_PROTOCOL with a value of
+	// 1, a client MAY use the Extended CONNECT as defined in this
+	// document when creating new streams.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Receives SETTINGS_ENABLE_CONNECT_PROTOCOL",
+		Requirement: "The endpoint MUST send the setting with the value of 0 or 1.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			// The SETTINGS frames sent after the connection preface
+			// are also recorded while waiting for the PING frame.
+			data := [8]byte{'h', '2', 's', 'p', 'e', 'c'}
+			conn.WritePing(false, data)
+
+			err = spec.VerifyPingFrameWithAck(conn, data)
+			if err != nil {
+				return err
+			}
+
+			return spec.VerifySettingValues(conn, spec.SettingEnableConnectProtocol, 0, 1)
+		},
+	})
+
+	return tg
+}
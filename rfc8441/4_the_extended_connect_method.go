@@ -0,0 +1,100 @@
+package rfc8441
+
+import (
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+func TheExtendedCONNECTMethod() *spec.TestGroup {
+	tg := NewTestGroup("4", "The Extended CONNECT Method")
+
+	// On requests that contain the :protocol pseudo-header field, the
+	// :scheme and :path pseudo-header fields of the target URI (see
+	// Section 5) MUST also be included.
+	//
+	// On requests bearing the :protocol pseudo-header field, the
+	// :authority pseudo-header field is interpreted according to
+	// Section 8.1.2.3 of [RFC7540] instead of Section 8.3 of that
+	// document.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends an extended CONNECT request",
+		Requirement: "The endpoint MUST respond to the request without treating it as malformed.",
+		Requires:    []spec.Capability{spec.CapabilityConnectProtocol},
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(extendedConnectHeaders(c)),
+			}
+			conn.WriteHeaders(hp)
+
+			res := spec.NewResponse(streamID)
+			ev := res.ReadHeaders(conn, c.Timeout)
+
+			return spec.VerifyConnectRequest(conn, res, ev)
+		},
+	})
+
+	// Using a SETTINGS parameter to opt into an otherwise incompatible
+	// protocol change is a use of "Extending HTTP/2" defined by
+	// Section 5.5 of [RFC7540]. Specifically, the addition of a new
+	// pseudo-header field, ":protocol", and the change in meaning of
+	// the :authority pseudo-header field in Section 4 require
+	// opt-in negotiation.
+	//
+	// Endpoints MUST NOT generate pseudo-header fields other than those
+	// defined in this document.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends an extended CONNECT request without SETTINGS_ENABLE_CONNECT_PROTOCOL",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			if conn.Settings[spec.SettingEnableConnectProtocol] == 1 {
+				return &spec.TestSkipped{Reason: "SETTINGS_ENABLE_CONNECT_PROTOCOL is advertised"}
+			}
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(extendedConnectHeaders(c)),
+			}
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	return tg
+}
+
+// extendedConnectHeaders returns the header fields of the extended
+// CONNECT request to bootstrap the WebSocket protocol.
+func extendedConnectHeaders(c *config.Config) []hpack.HeaderField {
+	headers := spec.CommonHeaders(c)
+
+	return []hpack.HeaderField{
+		spec.HeaderField(":method", "CONNECT"),
+		spec.HeaderField(":protocol", "websocket"),
+		headers[1], // :scheme
+		headers[2], // :path
+		headers[3], // :authority
+		spec.HeaderField("sec-websocket-version", "13"),
+	}
+}
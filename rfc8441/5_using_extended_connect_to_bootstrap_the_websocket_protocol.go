@@ -0,0 +1,70 @@
+package rfc8441
+
+import (
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+	"golang.org/x/net/http2"
+)
+
+func UsingExtendedCONNECTToBootstrapTheWebSocketProtocol() *spec.TestGroup {
+	tg := NewTestGroup("5", "Using Extended CONNECT to Bootstrap the WebSocket Protocol")
+
+	// The :protocol pseudo-header field MUST be included in the CONNECT
+	// request, and it MUST have a value of "websocket" to initiate a
+	// WebSocket connection on an HTTP/2 stream.
+	//
+	// Once the server returns a 2xx response, each endpoint MAY send
+	// data frames containing the WebSocket framing defined in RFC 6455
+	// on the stream.
+	//
+	// Note: This test case requires the WebSocket endpoint specified
+	// by --websocket-path, which echoes the text message or closes the
+	// WebSocket connection.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a text frame and a close frame over the WebSocket connection",
+		Requirement: "The endpoint MUST exchange the WebSocket frames without HTTP/2 error.",
+		Tags:        []string{"needs-websocket"},
+		Requires: []spec.Capability{
+			spec.CapabilityWebSocketEndpoint,
+			spec.CapabilityConnectProtocol,
+		},
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := extendedConnectHeaders(c)
+			headers[3].Value = c.WebSocketPath
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			res := spec.NewResponse(streamID)
+			ev := res.ReadHeaders(conn, c.Timeout)
+
+			err = spec.VerifyWebSocketHandshake(conn, res, ev)
+			if err != nil {
+				return err
+			}
+
+			// A text frame followed by a close frame with the status
+			// code 1000 (Normal Closure)
+			text := "h2spec"
+			key := [4]byte{'h', '2', 's', 'p'}
+			conn.WriteData(streamID, false, spec.EncodeWebSocketFrame(spec.WebSocketOpcodeText, []byte(text), key))
+			conn.WriteData(streamID, false, spec.EncodeWebSocketFrame(spec.WebSocketOpcodeClose, []byte{0x03, 0xe8}, key))
+
+			return spec.VerifyWebSocketClose(conn, res, text)
+		},
+	})
+
+	return tg
+}
@@ -0,0 +1,26 @@
+package rfc8441
+
+import "github.com/summerwind/h2spec/spec"
+
+var key = "rfc8441"
+
+func NewTestGroup(section string, name string) *spec.TestGroup {
+	return &spec.TestGroup{
+		Key:     key,
+		Section: section,
+		Name:    name,
+	}
+}
+
+func Spec() *spec.TestGroup {
+	tg := &spec.TestGroup{
+		Key:  key,
+		Name: "RFC 8441: Bootstrapping WebSockets with HTTP/2",
+	}
+
+	tg.AddTestGroup(TheSettingsEnableConnectProtocolParameter())
+	tg.AddTestGroup(TheExtendedCONNECTMethod())
+	tg.AddTestGroup(UsingExtendedCONNECTToBootstrapTheWebSocketProtocol())
+
+	return tg
+}
@@ -0,0 +1,78 @@
+package rfc8446
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+)
+
+func ResumptionAndPreSharedKey() *spec.TestGroup {
+	tg := NewTestGroup("2.2", "Resumption and Pre-Shared Key (PSK)")
+
+	// Once a handshake has completed, the server can send the client
+	// a PSK identity that corresponds to a unique key derived from
+	// the initial handshake (see Section 4.6.1). The client can then
+	// use that PSK identity in future handshakes to negotiate the use
+	// of the associated PSK.
+	//
+	// Note: This test case completes a full handshake with a request,
+	// and then resumes the session on a new connection. The failure
+	// to resume is reported as informational, but the resumed
+	// connection must negotiate the same protocol and serve HTTP/2.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a request on a resumed TLS session",
+		Requirement: "The endpoint MUST negotiate the same protocol and process HTTP/2 on the resumed connection.",
+		Requires:    []spec.Capability{spec.CapabilityTLS},
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			sc := sessionConfig(c)
+
+			full, err := conn.Dial(sc)
+			if err != nil {
+				return err
+			}
+			defer full.Close()
+
+			err = request(c, full)
+			if err != nil {
+				return err
+			}
+			fullState, _ := full.TLSConnectionState()
+			full.Close()
+
+			resumed, err := conn.Dial(sc)
+			if err != nil {
+				return err
+			}
+			defer resumed.Close()
+
+			state, ok := resumed.TLSConnectionState()
+			if !ok || !state.DidResume {
+				return &spec.TestInfo{
+					Message: "TLS session not resumed",
+				}
+			}
+
+			if state.NegotiatedProtocol != fullState.NegotiatedProtocol {
+				return &spec.TestError{
+					Expected: []string{
+						fmt.Sprintf("Negotiated protocol: %s", fullState.NegotiatedProtocol),
+					},
+					Actual: fmt.Sprintf("Negotiated protocol: %s", state.NegotiatedProtocol),
+				}
+			}
+
+			err = request(c, resumed)
+			if err != nil {
+				return err
+			}
+
+			return &spec.TestInfo{
+				Message: fmt.Sprintf("TLS session resumed (%s, %s)", tls.VersionName(state.Version), state.NegotiatedProtocol),
+			}
+		},
+	})
+
+	return tg
+}
@@ -0,0 +1,141 @@
+package rfc8446
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+)
+
+func ZeroRTTData() *spec.TestGroup {
+	tg := NewTestGroup("2.3", "0-RTT Data")
+
+	// When clients and servers share a PSK (either obtained
+	// externally or via a previous handshake), TLS 1.3 allows clients
+	// to send data on the first flight ("early data"). The client
+	// uses the PSK to authenticate the server and to encrypt the
+	// early data.
+	//
+	// Note: This test case sends the client connection preface and a
+	// GET request as early data on a resumed session. The server may
+	// either reject the early data, after which the connection
+	// preface must succeed on 1-RTT, or accept it and respond to the
+	// request. crypto/tls does not send early data, so the test case
+	// is registered only when h2spec is used as a library with the
+	// dialer capable of it.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends the connection preface and a request as 0-RTT early data",
+		Requirement: "The endpoint MUST either reject the early data or respond to the request.",
+		Requires:    []spec.Capability{spec.CapabilityTLS, spec.CapabilityEarlyData},
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			sc := sessionConfig(c)
+
+			full, err := conn.Dial(sc)
+			if err != nil {
+				return err
+			}
+			defer full.Close()
+
+			err = request(c, full)
+			if err != nil {
+				return err
+			}
+			full.Close()
+
+			early, accepted, err := conn.DialWithEarlyData(sc, earlyRequest(c, conn, streamID))
+			if err != nil {
+				actual := fmt.Sprintf("TLS handshake failed: %v", err)
+
+				// The alert is reported as is when the server
+				// aborts the handshake with the early data.
+				var alert tls.AlertError
+				if errors.As(err, &alert) {
+					actual = fmt.Sprintf("TLS alert received: %s", alert)
+				}
+
+				return &spec.TestError{
+					Expected: []string{
+						"Early data accepted",
+						"Early data rejected",
+					},
+					Actual: actual,
+				}
+			}
+			defer early.Close()
+
+			if !accepted {
+				err = request(c, early)
+				if err != nil {
+					return err
+				}
+
+				return &spec.TestInfo{
+					Message: "Early data rejected, connection preface succeeded on 1-RTT",
+				}
+			}
+
+			err = spec.ExpectFrame(early, spec.Headers(streamID))
+			if err != nil {
+				return err
+			}
+
+			return &spec.TestInfo{
+				Message: "Early data accepted, request responded",
+			}
+		},
+	})
+
+	return tg
+}
+
+// earlyRequest returns the client connection preface followed by the
+// canonical request on the specified stream, which is sent as early
+// data. The header block is encoded by its own encoder, since the
+// connection is not established yet. The path is expanded on the
+// connection of the test case.
+func earlyRequest(c *config.Config, conn *spec.Conn, streamID uint32) []byte {
+	var buf, block bytes.Buffer
+
+	buf.WriteString(http2.ClientPreface)
+
+	framer := http2.NewFramer(&buf, nil)
+	framer.WriteSettings(c.ClientSettings...)
+
+	encoder := hpack.NewEncoder(&block)
+	headers := spec.RequestHeaders(c)
+	headers[2].Value = conn.ExpandPath(headers[2].Value)
+	for _, field := range headers {
+		encoder.WriteField(field)
+	}
+
+	framer.WriteHeaders(http2.HeadersFrameParam{
+		StreamID:      streamID,
+		EndStream:     !spec.HasRequestBody(c),
+		EndHeaders:    true,
+		BlockFragment: block.Bytes(),
+	})
+
+	// The body is split by the initial maximum frame size, since the
+	// settings of the server are not known yet.
+	if spec.HasRequestBody(c) {
+		body := c.Request.Body
+		for len(body) > 0 {
+			n := len(body)
+			if n > spec.DefaultFrameSize {
+				n = spec.DefaultFrameSize
+			}
+			framer.WriteData(streamID, n == len(body), body[:n])
+			body = body[n:]
+		}
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,60 @@
+package rfc8446
+
+import (
+	"crypto/tls"
+
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+)
+
+var key = "rfc8446"
+
+func NewTestGroup(section string, name string) *spec.TestGroup {
+	return &spec.TestGroup{
+		Key:     key,
+		Section: section,
+		Name:    name,
+	}
+}
+
+// Spec returns the test cases of the specification. The 0-RTT test
+// case is added only if the dialer of the configuration sends early
+// data, which the dialer of the command line does not.
+func Spec(c *config.Config) *spec.TestGroup {
+	tg := &spec.TestGroup{
+		Key:  key,
+		Name: "RFC 8446: The Transport Layer Security (TLS) Protocol Version 1.3",
+		Tags: []string{"resumption"},
+	}
+
+	tg.AddTestGroup(ResumptionAndPreSharedKey())
+	if _, ok := c.Dialer.(config.EarlyDataWrapper); ok {
+		tg.AddTestGroup(ZeroRTTData())
+	}
+
+	return tg
+}
+
+// sessionConfig returns the configuration whose connections share a
+// new cache of TLS sessions, so that each connection resumes the
+// session of the previous one.
+func sessionConfig(c *config.Config) *config.Config {
+	sc := *c
+	sc.SessionCache = tls.NewLRUClientSessionCache(1)
+	return &sc
+}
+
+// request performs the connection preface and sends a request on the
+// connection, and verifies that the server responds to it.
+func request(c *config.Config, conn *spec.Conn) error {
+	var streamID uint32 = 1
+
+	err := conn.Handshake()
+	if err != nil {
+		return err
+	}
+
+	spec.WriteRequest(conn, c, streamID)
+
+	return spec.VerifyHeadersFrame(conn, streamID)
+}
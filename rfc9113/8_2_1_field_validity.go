@@ -0,0 +1,341 @@
+package rfc9113
+
+import (
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+	"golang.org/x/net/http2"
+)
+
+func FieldValidity() *spec.TestGroup {
+	tg := NewTestGroup("8.2.1", "Field Validity")
+
+	// A field value MUST NOT contain the zero value (ASCII NUL, 0x00),
+	// line feed (ASCII LF, 0x0a), or carriage return (ASCII CR, 0x0d) at
+	// any position.
+	//
+	// A request or response that contains a field that violates any of
+	// these conditions MUST be treated as malformed (Section 8.1.1).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame that contains 0x00 (NUL) in the field value",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			blockFragment := conn.EncodeHeaders(spec.CommonHeaders(c))
+			blockFragment = append(blockFragment, spec.LiteralHeaderField("x-test", "te\x00st")...)
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: blockFragment,
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// A field value MUST NOT contain the zero value (ASCII NUL, 0x00),
+	// line feed (ASCII LF, 0x0a), or carriage return (ASCII CR, 0x0d) at
+	// any position.
+	//
+	// A request or response that contains a field that violates any of
+	// these conditions MUST be treated as malformed (Section 8.1.1).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame that contains 0x0a (LF) in the field value",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			blockFragment := conn.EncodeHeaders(spec.CommonHeaders(c))
+			blockFragment = append(blockFragment, spec.LiteralHeaderField("x-test", "te\nst")...)
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: blockFragment,
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// A field value MUST NOT contain the zero value (ASCII NUL, 0x00),
+	// line feed (ASCII LF, 0x0a), or carriage return (ASCII CR, 0x0d) at
+	// any position.
+	//
+	// A request or response that contains a field that violates any of
+	// these conditions MUST be treated as malformed (Section 8.1.1).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame that contains 0x0d (CR) in the field value",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			blockFragment := conn.EncodeHeaders(spec.CommonHeaders(c))
+			blockFragment = append(blockFragment, spec.LiteralHeaderField("x-test", "te\rst")...)
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: blockFragment,
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// A field name MUST NOT contain characters in the ranges 0x00-0x20,
+	// 0x41-0x5a, or 0x7f-0xff (all ranges inclusive). This specifically
+	// excludes all non-visible ASCII characters, ASCII SP (0x20), and
+	// uppercase characters ('A' to 'Z', ASCII 0x41 to 0x5a).
+	//
+	// A request or response that contains a field that violates any of
+	// these conditions MUST be treated as malformed (Section 8.1.1).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame that contains a space in the field name",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			blockFragment := conn.EncodeHeaders(spec.CommonHeaders(c))
+			blockFragment = append(blockFragment, spec.LiteralHeaderField("x test", "test")...)
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: blockFragment,
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// With the exception of pseudo-header fields (Section 8.3), which
+	// have a name that starts with a single colon, field names MUST NOT
+	// include a colon (ASCII COLON, 0x3a).
+	//
+	// A request or response that contains a field that violates any of
+	// these conditions MUST be treated as malformed (Section 8.1.1).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame that contains a colon in the field name",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			blockFragment := conn.EncodeHeaders(spec.CommonHeaders(c))
+			blockFragment = append(blockFragment, spec.LiteralHeaderField("x:test", "test")...)
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: blockFragment,
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// A field name MUST NOT contain characters in the ranges 0x00-0x20,
+	// 0x41-0x5a, or 0x7f-0xff (all ranges inclusive). This specifically
+	// excludes all non-visible ASCII characters, ASCII SP (0x20), and
+	// uppercase characters ('A' to 'Z', ASCII 0x41 to 0x5a).
+	//
+	// A request or response that contains a field that violates any of
+	// these conditions MUST be treated as malformed (Section 8.1.1).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame that contains a DEL character in the field name",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			blockFragment := conn.EncodeHeaders(spec.CommonHeaders(c))
+			blockFragment = append(blockFragment, spec.LiteralHeaderField("x\x7ftest", "test")...)
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: blockFragment,
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// Field names are tokens as defined in Section 5.6.2 of [HTTP],
+	// which consist of one or more characters.
+	//
+	// A request or response that contains a field that violates any of
+	// these conditions MUST be treated as malformed (Section 8.1.1).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame that contains an empty field name",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			blockFragment := conn.EncodeHeaders(spec.CommonHeaders(c))
+			blockFragment = append(blockFragment, spec.LiteralHeaderField("", "test")...)
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: blockFragment,
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// A field value MUST NOT start or end with an ASCII whitespace
+	// character (ASCII SP or HTAB, 0x20 or 0x09).
+	//
+	// A request or response that contains a field that violates any of
+	// these conditions MUST be treated as malformed (Section 8.1.1).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame that contains a leading space in the field value",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			blockFragment := conn.EncodeHeaders(spec.CommonHeaders(c))
+			blockFragment = append(blockFragment, spec.LiteralHeaderField("x-test", " test")...)
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: blockFragment,
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// A field value MUST NOT start or end with an ASCII whitespace
+	// character (ASCII SP or HTAB, 0x20 or 0x09).
+	//
+	// A request or response that contains a field that violates any of
+	// these conditions MUST be treated as malformed (Section 8.1.1).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame that contains a trailing tab in the field value",
+		Requirement: "The endpoint MUST treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			blockFragment := conn.EncodeHeaders(spec.CommonHeaders(c))
+			blockFragment = append(blockFragment, spec.LiteralHeaderField("x-test", "test\t")...)
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: blockFragment,
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyMalformedRequest(conn, streamID)
+		},
+	})
+
+	// A field value MUST NOT start or end with an ASCII whitespace
+	// character (ASCII SP or HTAB, 0x20 or 0x09).
+	//
+	// A request or response that contains a field that violates any of
+	// these conditions MUST be treated as malformed (Section 8.1.1).
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame that contains a space inside the field value",
+		Requirement: "The endpoint MUST accept the whitespace inside the field value.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			blockFragment := conn.EncodeHeaders(spec.CommonHeaders(c))
+			blockFragment = append(blockFragment, spec.LiteralHeaderField("x-test", "te s\tt")...)
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: blockFragment,
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyStreamResponse(conn, streamID)
+		},
+	})
+
+	return tg
+}
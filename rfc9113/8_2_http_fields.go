@@ -0,0 +1,11 @@
+package rfc9113
+
+import "github.com/summerwind/h2spec/spec"
+
+func HTTPFields() *spec.TestGroup {
+	tg := NewTestGroup("8.2", "HTTP Fields")
+
+	tg.AddTestGroup(FieldValidity())
+
+	return tg
+}
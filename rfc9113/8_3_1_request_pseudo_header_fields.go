@@ -0,0 +1,51 @@
+package rfc9113
+
+import (
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+	"golang.org/x/net/http2"
+)
+
+func RequestPseudoHeaderFields() *spec.TestGroup {
+	tg := NewTestGroup("8.3.1", "Request Pseudo-Header Fields")
+
+	// Clients MUST NOT generate a request with a Host header field that
+	// differs from the ":authority" pseudo-header field. A server SHOULD
+	// treat a request as malformed if it contains a Host header field
+	// that identifies an entity that differs from the entity in the
+	// ":authority" pseudo-header field.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a HEADERS frame with the host header field that differs from \":authority\"",
+		Requirement: "The endpoint SHOULD treat the request as malformed.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			echo := (c.EchoPath != "")
+
+			headers := spec.CommonHeaders(c)
+			if echo {
+				headers = spec.EchoHeaders(c)
+			}
+			headers[3].Value = "a.example.com"
+			headers = append(headers, spec.HeaderField("host", "b.example.com"))
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+
+			return spec.VerifyAuthorityMismatch(conn, streamID, "a.example.com", echo)
+		},
+	})
+
+	return tg
+}
@@ -0,0 +1,11 @@
+package rfc9113
+
+import "github.com/summerwind/h2spec/spec"
+
+func HTTPControlData() *spec.TestGroup {
+	tg := NewTestGroup("8.3", "HTTP Control Data")
+
+	tg.AddTestGroup(RequestPseudoHeaderFields())
+
+	return tg
+}
@@ -0,0 +1,12 @@
+package rfc9113
+
+import "github.com/summerwind/h2spec/spec"
+
+func HTTPSemanticsInHTTP2() *spec.TestGroup {
+	tg := NewTestGroup("8", "Expressing HTTP Semantics in HTTP/2")
+
+	tg.AddTestGroup(HTTPFields())
+	tg.AddTestGroup(HTTPControlData())
+
+	return tg
+}
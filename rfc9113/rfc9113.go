@@ -0,0 +1,25 @@
+package rfc9113
+
+import "github.com/summerwind/h2spec/spec"
+
+var key = "rfc9113"
+
+func NewTestGroup(section string, name string) *spec.TestGroup {
+	return &spec.TestGroup{
+		Key:     key,
+		Section: section,
+		Name:    name,
+	}
+}
+
+func Spec() *spec.TestGroup {
+	tg := &spec.TestGroup{
+		Key:      key,
+		Name:     "RFC 9113: HTTP/2",
+		Profiles: []spec.Profile{spec.ProfileRFC9113},
+	}
+
+	tg.AddTestGroup(HTTPSemanticsInHTTP2())
+
+	return tg
+}
@@ -0,0 +1,105 @@
+package rfc9218
+
+import (
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+	"golang.org/x/net/http2"
+)
+
+func DisablingRFC7540Priorities() *spec.TestGroup {
+	tg := NewTestGroup("2.1", "Disabling RFC 7540 Priorities")
+
+	// The SETTINGS_NO_RFC7540_PRIORITIES HTTP/2 SETTINGS parameter is
+	// defined. [...] An implementation that does not understand the
+	// setting ignores it, as required by Section 6.5.2 of [HTTP/2].
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends SETTINGS_NO_RFC7540_PRIORITIES with 1 in the connection preface",
+		Requirement: "The endpoint MUST accept the setting and continue to operate.",
+		Settings: []http2.Setting{
+			http2.Setting{
+				ID:  spec.SettingNoRFC7540Priorities,
+				Val: 1,
+			},
+		},
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			spec.WriteRequest(conn, c, streamID)
+
+			return spec.VerifyStreamResponse(conn, streamID)
+		},
+	})
+
+	// The value of the parameter MUST be 0 or 1. Any value other than 0
+	// or 1 MUST be treated as a connection error (see Section 5.4.1 of
+	// [HTTP/2]) of type PROTOCOL_ERROR.
+	//
+	// Note: The server that does not advertise the setting may not
+	// understand it, so ignoring the setting is also acceptable.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends SETTINGS_NO_RFC7540_PRIORITIES with 2",
+		Requirement: "The endpoint MUST treat this as a connection error of type PROTOCOL_ERROR.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			setting := http2.Setting{
+				ID:  spec.SettingNoRFC7540Priorities,
+				Val: 2,
+			}
+			conn.WriteSettings(setting)
+
+			_, advertised := conn.Settings[spec.SettingNoRFC7540Priorities]
+			if advertised {
+				return spec.VerifyConnectionError(conn, http2.ErrCodeProtocol)
+			}
+
+			data := [8]byte{'h', '2', 's', 'p', 'e', 'c'}
+			conn.WritePing(false, data)
+
+			return spec.VerifyConnectionErrorOrPingFrame(conn, data, http2.ErrCodeProtocol)
+		},
+	})
+
+	// Senders MUST NOT change the SETTINGS_NO_RFC7540_PRIORITIES value
+	// after the first SETTINGS frame. Receivers that detect a change MAY
+	// treat it as a connection error of type PROTOCOL_ERROR.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends multiple requests and verifies SETTINGS_NO_RFC7540_PRIORITIES of the server",
+		Requirement: "The endpoint MUST NOT change the value of the setting after the first SETTINGS frame.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			for _, streamID := range []uint32{1, 3} {
+				spec.WriteRequest(conn, c, streamID)
+
+				err = spec.VerifyStreamResponse(conn, streamID)
+				if err != nil {
+					return err
+				}
+			}
+
+			data := [8]byte{'h', '2', 's', 'p', 'e', 'c'}
+			conn.WritePing(false, data)
+
+			err = spec.VerifyPingFrameWithAck(conn, data)
+			if err != nil {
+				return err
+			}
+
+			return spec.VerifySettingUnchanged(conn, spec.SettingNoRFC7540Priorities)
+		},
+	})
+
+	return tg
+}
@@ -0,0 +1,11 @@
+package rfc9218
+
+import "github.com/summerwind/h2spec/spec"
+
+func MotivationForReplacingRFC7540Priorities() *spec.TestGroup {
+	tg := NewTestGroup("2", "Motivation for Replacing RFC 7540 Priorities")
+
+	tg.AddTestGroup(DisablingRFC7540Priorities())
+
+	return tg
+}
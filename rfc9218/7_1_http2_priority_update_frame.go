@@ -0,0 +1,101 @@
+package rfc9218
+
+import (
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+	"golang.org/x/net/http2"
+)
+
+func HTTP2PriorityUpdateFrame() *spec.TestGroup {
+	tg := NewTestGroup("7.1", "HTTP/2 PRIORITY_UPDATE Frame")
+
+	// The HTTP/2 PRIORITY_UPDATE frame (type=0x10) is used by clients to
+	// signal the initial priority of a response, or to reprioritize a
+	// response or push stream. It carries the stream ID of the response
+	// and the priority in ASCII text, using the same representation as
+	// the Priority header field value.
+	//
+	// Note: The server that does not implement this extension MUST
+	// ignore the frame as an unknown frame type.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a PRIORITY_UPDATE frame for an open stream",
+		Requirement: "The endpoint MUST process or ignore the frame without error.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.RequestHeadersWithMethod(c, "POST")
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+
+			// PRIORITY_UPDATE frame:
+			// prioritized_stream_id: 1, priority_field_value: "u=1"
+			payload := []byte("\x00\x00\x00\x01u=1")
+			conn.WriteRawFrame(spec.FramePriorityUpdate, 0x0, 0, payload)
+
+			data := [8]byte{'h', '2', 's', 'p', 'e', 'c'}
+			conn.WritePing(false, data)
+
+			return spec.VerifyNoErrorBeforePing(conn, streamID, data)
+		},
+	})
+
+	// The PRIORITY_UPDATE frame MUST be sent on stream 0. If a
+	// PRIORITY_UPDATE frame is received with a stream ID other than 0x0,
+	// the recipient MUST respond with a connection error (Section 5.4.1
+	// of [HTTP/2]) of type PROTOCOL_ERROR.
+	//
+	// Note: The server that does not advertise SETTINGS_NO_RFC7540_PRIORITIES
+	// with 1 may not implement this extension, so ignoring the frame is
+	// also acceptable.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a PRIORITY_UPDATE frame with a stream identifier other than 0x0",
+		Requirement: "The endpoint MUST treat this as a connection error of type PROTOCOL_ERROR.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.RequestHeadersWithMethod(c, "POST")
+
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     false,
+				EndHeaders:    true,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+
+			conn.WriteHeaders(hp)
+
+			// PRIORITY_UPDATE frame:
+			// prioritized_stream_id: 1, priority_field_value: "u=1"
+			payload := []byte("\x00\x00\x00\x01u=1")
+			conn.WriteRawFrame(spec.FramePriorityUpdate, 0x0, streamID, payload)
+
+			if conn.Settings[spec.SettingNoRFC7540Priorities] == 1 {
+				return spec.VerifyConnectionError(conn, http2.ErrCodeProtocol)
+			}
+
+			data := [8]byte{'h', '2', 's', 'p', 'e', 'c'}
+			conn.WritePing(false, data)
+
+			return spec.VerifyConnectionErrorOrPingFrame(conn, data, http2.ErrCodeProtocol)
+		},
+	})
+
+	return tg
+}
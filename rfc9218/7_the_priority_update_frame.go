@@ -0,0 +1,11 @@
+package rfc9218
+
+import "github.com/summerwind/h2spec/spec"
+
+func ThePriorityUpdateFrame() *spec.TestGroup {
+	tg := NewTestGroup("7", "The PRIORITY_UPDATE Frame")
+
+	tg.AddTestGroup(HTTP2PriorityUpdateFrame())
+
+	return tg
+}
@@ -0,0 +1,25 @@
+package rfc9218
+
+import "github.com/summerwind/h2spec/spec"
+
+var key = "rfc9218"
+
+func NewTestGroup(section string, name string) *spec.TestGroup {
+	return &spec.TestGroup{
+		Key:     key,
+		Section: section,
+		Name:    name,
+	}
+}
+
+func Spec() *spec.TestGroup {
+	tg := &spec.TestGroup{
+		Key:  key,
+		Name: "RFC 9218: Extensible Prioritization Scheme for HTTP",
+	}
+
+	tg.AddTestGroup(MotivationForReplacingRFC7540Priorities())
+	tg.AddTestGroup(ThePriorityUpdateFrame())
+
+	return tg
+}
@@ -0,0 +1,123 @@
+package selfcheck
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/summerwind/h2spec"
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+)
+
+// Defects is the defects that the misbehaving server can exhibit.
+var Defects = []Defect{
+	DefectNoSettingsAck,
+	DefectAcceptUppercaseHeaders,
+	DefectIgnoreWindowOverflow,
+	DefectNoGoAway,
+}
+
+// defectFailures is the test cases that have to detect each defect. The
+// test cases are run in strict mode, so that the omission of GOAWAY
+// frame is detected.
+var defectFailures = map[Defect][]string{
+	DefectNoSettingsAck: {
+		"http2/3.5/3",
+		"http2/6.5.3/2",
+	},
+	DefectAcceptUppercaseHeaders: {
+		"http2/8.1.2/1",
+	},
+	DefectIgnoreWindowOverflow: {
+		"http2/6.9.1/2",
+		"http2/6.9.1/3",
+	},
+	DefectNoGoAway: {
+		"http2/5.4.1/2",
+		"http2/6.9.1/2",
+	},
+}
+
+// defectRecorder is a Reporter that records the test cases that did not
+// pass.
+type defectRecorder struct {
+	spec.NopReporter
+	passed   map[string]bool
+	detected map[string]bool
+}
+
+func (r *defectRecorder) TestFinished(tr *spec.TestResult) {
+	id := fmt.Sprintf("%s/%d", tr.TestCase.Parent.ID(), tr.Sequence)
+
+	// An inconclusive result is a detection, since ignoring a frame is
+	// observed only as the absence of the expected frame. An errored
+	// result is neither a pass nor a detection.
+	switch {
+	case tr.Errored:
+	case tr.Failed || tr.Inconclusive:
+		r.detected[id] = true
+	case !tr.Skipped && !tr.Warned:
+		r.passed[id] = true
+	}
+}
+
+// RunDefect runs the test cases that have to detect the defect against
+// the misbehaving server, first without the defect and then with it.
+// Error is returned if any test case does not pass without the defect,
+// or does not fail with it.
+func RunDefect(ctx context.Context, c *config.Config, d Defect, reporters ...spec.Reporter) error {
+	ids := defectFailures[d]
+	unexpected := []string{}
+
+	for _, defective := range []bool{false, true} {
+		defects := []Defect{}
+		if defective {
+			defects = append(defects, d)
+		}
+
+		r, err := runMisbehaving(ctx, c, ids, defects, reporters)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			switch {
+			case !defective && !r.passed[id]:
+				unexpected = append(unexpected, fmt.Sprintf("%s: not passed without %s", id, d))
+			case defective && !r.detected[id]:
+				unexpected = append(unexpected, fmt.Sprintf("%s: not failed with %s", id, d))
+			}
+		}
+	}
+
+	if len(unexpected) > 0 {
+		sort.Strings(unexpected)
+		return &Error{Unexpected: unexpected}
+	}
+
+	return nil
+}
+
+func runMisbehaving(ctx context.Context, c *config.Config, ids []string, defects []Defect, reporters []spec.Reporter) (*defectRecorder, error) {
+	s, err := NewMisbehavingServer(defects...)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+
+	sc := targetConfig(c, s.Port())
+	sc.Strict = true
+	sc.Sections = ids
+
+	r := &defectRecorder{
+		passed:   map[string]bool{},
+		detected: map[string]bool{},
+	}
+	_, err = h2spec.RunContext(ctx, sc, append(reporters, r)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
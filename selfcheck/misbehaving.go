@@ -0,0 +1,352 @@
+package selfcheck
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+// Defect is a violation of the specification that the misbehaving
+// server can be told to exhibit.
+type Defect int
+
+const (
+	// DefectNoSettingsAck does not acknowledge the SETTINGS frames of
+	// the client.
+	DefectNoSettingsAck Defect = iota + 1
+	// DefectAcceptUppercaseHeaders responds to the requests containing
+	// uppercase header field names instead of treating them as
+	// malformed.
+	DefectAcceptUppercaseHeaders
+	// DefectIgnoreWindowOverflow ignores the WINDOW_UPDATE frames that
+	// cause a flow control window to exceed 2^31-1.
+	DefectIgnoreWindowOverflow
+	// DefectNoGoAway closes the connection on a connection error
+	// without sending GOAWAY frame.
+	DefectNoGoAway
+)
+
+var defectNames = map[Defect]string{
+	DefectNoSettingsAck:          "no-settings-ack",
+	DefectAcceptUppercaseHeaders: "accept-uppercase-headers",
+	DefectIgnoreWindowOverflow:   "ignore-window-overflow",
+	DefectNoGoAway:               "no-goaway",
+}
+
+func (d Defect) String() string {
+	name, ok := defectNames[d]
+	if !ok {
+		return "unknown"
+	}
+	return name
+}
+
+// maxWindowSize is the maximum size of a flow control window.
+const maxWindowSize = 1<<31 - 1
+
+// MisbehavingServer is a minimal HTTP/2 server listening on the loopback
+// address over TLS, which responds to every request with a small
+// response and exhibits the specified defects. Without defects, it
+// passes the test cases that detect them.
+type MisbehavingServer struct {
+	listener net.Listener
+	defects  map[Defect]bool
+
+	mu    sync.Mutex
+	conns map[net.Conn]bool
+}
+
+// NewMisbehavingServer starts the misbehaving server on a random port of
+// the loopback address with a self-signed certificate.
+func NewMisbehavingServer(defects ...Defect) (*MisbehavingServer, error) {
+	cert, err := selfSignedCertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &MisbehavingServer{
+		listener: tls.NewListener(listener, &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			NextProtos:   []string{http2.NextProtoTLS},
+		}),
+		defects: map[Defect]bool{},
+		conns:   map[net.Conn]bool{},
+	}
+	for _, d := range defects {
+		s.defects[d] = true
+	}
+
+	go s.serve()
+
+	return s, nil
+}
+
+// Port returns the port number the server is listening on.
+func (s *MisbehavingServer) Port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+// Close stops the server and closes its connections.
+func (s *MisbehavingServer) Close() error {
+	err := s.listener.Close()
+
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	return err
+}
+
+func (s *MisbehavingServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = true
+		s.mu.Unlock()
+
+		go func() {
+			s.serveConn(conn)
+
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// misbehavingConn is the state of a connection of the misbehaving
+// server.
+type misbehavingConn struct {
+	*MisbehavingServer
+
+	conn    net.Conn
+	framer  *http2.Framer
+	encoder *hpack.Encoder
+	decoder *hpack.Decoder
+
+	encoderBuf *bytes.Buffer
+
+	lastStreamID      uint32
+	initialWindowSize int64
+	window            int64
+	streamWindows     map[uint32]int64
+
+	// headerBlock is the header block of the stream waiting for
+	// CONTINUATION frames.
+	headerBlock  []byte
+	headerStream uint32
+	headerEnded  bool
+}
+
+func (s *MisbehavingServer) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	magic := make([]byte, len(http2.ClientPreface))
+	_, err := io.ReadFull(conn, magic)
+	if err != nil || string(magic) != http2.ClientPreface {
+		return
+	}
+
+	mc := &misbehavingConn{
+		MisbehavingServer: s,
+		conn:              conn,
+		framer:            http2.NewFramer(conn, conn),
+		decoder:           hpack.NewDecoder(4096, nil),
+		encoderBuf:        &bytes.Buffer{},
+		initialWindowSize: 65535,
+		window:            65535,
+		streamWindows:     map[uint32]int64{},
+	}
+	mc.encoder = hpack.NewEncoder(mc.encoderBuf)
+
+	mc.framer.WriteSettings(http2.Setting{
+		ID:  http2.SettingMaxConcurrentStreams,
+		Val: 100,
+	})
+
+	for {
+		f, err := mc.framer.ReadFrame()
+		if err != nil {
+			switch err := err.(type) {
+			case http2.ConnectionError:
+				mc.connectionError(http2.ErrCode(err))
+			case http2.StreamError:
+				mc.framer.WriteRSTStream(err.StreamID, err.Code)
+				continue
+			}
+			return
+		}
+
+		if !mc.handle(f) {
+			return
+		}
+	}
+}
+
+// handle processes the frame and returns false if the connection has to
+// be closed.
+func (mc *misbehavingConn) handle(f http2.Frame) bool {
+	if mc.headerStream != 0 {
+		cf, ok := f.(*http2.ContinuationFrame)
+		if !ok || cf.StreamID != mc.headerStream {
+			mc.connectionError(http2.ErrCodeProtocol)
+			return false
+		}
+
+		mc.headerBlock = append(mc.headerBlock, cf.HeaderBlockFragment()...)
+		if cf.HeadersEnded() {
+			return mc.handleHeaders(mc.headerStream, mc.headerEnded)
+		}
+		return true
+	}
+
+	switch f := f.(type) {
+	case *http2.SettingsFrame:
+		if f.IsAck() {
+			return true
+		}
+
+		val, ok := f.Value(http2.SettingInitialWindowSize)
+		if ok {
+			if val > maxWindowSize {
+				mc.connectionError(http2.ErrCodeFlowControl)
+				return false
+			}
+			mc.initialWindowSize = int64(val)
+		}
+
+		if !mc.defects[DefectNoSettingsAck] {
+			mc.framer.WriteSettingsAck()
+		}
+
+	case *http2.PingFrame:
+		if !f.IsAck() {
+			mc.framer.WritePing(true, f.Data)
+		}
+
+	case *http2.HeadersFrame:
+		if f.StreamID <= mc.lastStreamID {
+			mc.connectionError(http2.ErrCodeProtocol)
+			return false
+		}
+		mc.lastStreamID = f.StreamID
+
+		mc.headerBlock = append([]byte{}, f.HeaderBlockFragment()...)
+		if !f.HeadersEnded() {
+			mc.headerStream = f.StreamID
+			mc.headerEnded = f.StreamEnded()
+			return true
+		}
+		return mc.handleHeaders(f.StreamID, f.StreamEnded())
+
+	case *http2.DataFrame:
+		// The padding is credited as well as the data.
+		if f.Header().Length > 0 {
+			mc.framer.WriteWindowUpdate(0, f.Header().Length)
+		}
+		if f.StreamEnded() {
+			mc.respond(f.StreamID)
+		}
+
+	case *http2.WindowUpdateFrame:
+		if f.StreamID == 0 {
+			mc.window += int64(f.Increment)
+			if mc.window > maxWindowSize && !mc.defects[DefectIgnoreWindowOverflow] {
+				mc.connectionError(http2.ErrCodeFlowControl)
+				return false
+			}
+			return true
+		}
+
+		window, ok := mc.streamWindows[f.StreamID]
+		if !ok {
+			return true
+		}
+		window += int64(f.Increment)
+		if window > maxWindowSize && !mc.defects[DefectIgnoreWindowOverflow] {
+			delete(mc.streamWindows, f.StreamID)
+			mc.framer.WriteRSTStream(f.StreamID, http2.ErrCodeFlowControl)
+			return true
+		}
+		mc.streamWindows[f.StreamID] = window
+
+	case *http2.RSTStreamFrame:
+		delete(mc.streamWindows, f.StreamID)
+
+	case *http2.GoAwayFrame:
+		return false
+	}
+
+	return true
+}
+
+// handleHeaders decodes the header block of the request and responds to
+// it if the stream is ended.
+func (mc *misbehavingConn) handleHeaders(streamID uint32, ended bool) bool {
+	mc.headerStream = 0
+
+	fields, err := mc.decoder.DecodeFull(mc.headerBlock)
+	if err != nil {
+		mc.connectionError(http2.ErrCodeCompression)
+		return false
+	}
+
+	for _, field := range fields {
+		if strings.ToLower(field.Name) != field.Name && !mc.defects[DefectAcceptUppercaseHeaders] {
+			mc.framer.WriteRSTStream(streamID, http2.ErrCodeProtocol)
+			return true
+		}
+	}
+
+	mc.streamWindows[streamID] = mc.initialWindowSize
+	if ended {
+		mc.respond(streamID)
+	}
+
+	return true
+}
+
+// respond sends the response to the stream.
+func (mc *misbehavingConn) respond(streamID uint32) {
+	if _, ok := mc.streamWindows[streamID]; !ok {
+		return
+	}
+	delete(mc.streamWindows, streamID)
+
+	mc.encoderBuf.Reset()
+	mc.encoder.WriteField(hpack.HeaderField{Name: ":status", Value: "200"})
+	mc.encoder.WriteField(hpack.HeaderField{Name: "content-type", Value: "text/plain"})
+
+	mc.framer.WriteHeaders(http2.HeadersFrameParam{
+		StreamID:      streamID,
+		EndHeaders:    true,
+		BlockFragment: mc.encoderBuf.Bytes(),
+	})
+	mc.framer.WriteData(streamID, true, []byte("h2spec misbehaving server"))
+}
+
+// connectionError sends GOAWAY frame with the error code unless the
+// server omits it.
+func (mc *misbehavingConn) connectionError(code http2.ErrCode) {
+	if mc.defects[DefectNoGoAway] {
+		return
+	}
+	mc.framer.WriteGoAway(mc.lastStreamID, code, []byte(code.String()))
+}
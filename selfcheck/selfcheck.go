@@ -0,0 +1,109 @@
+package selfcheck
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/summerwind/h2spec"
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+)
+
+// knownFailures is the test cases the reference server fails with the
+// reason. Any other failure, and a pass of these test cases, is
+// reported as an unexpected result, since it means the verdict logic
+// of the test case or the list has to be fixed.
+var knownFailures = map[string]string{
+	"http2/4.2/3":     "the headers do not exceed SETTINGS_MAX_FRAME_SIZE of 1MB advertised by net/http",
+	"http2/6.5.3/1":   "net/http rejects duplicate parameters in a SETTINGS frame",
+	"http2/6.5.3/3":   "net/http rejects duplicate parameters in a SETTINGS frame",
+	"rfc9113/8.2.1/8": "net/http accepts a field value with leading whitespace",
+	"rfc9113/8.2.1/9": "net/http accepts a field value with trailing whitespace",
+}
+
+// Error is returned by Run and RunDefect when the result of the run
+// differs from the expected result.
+type Error struct {
+	// Unexpected is the description of each unexpected result.
+	Unexpected []string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d unexpected results of the selfcheck:\n%s", len(e.Unexpected), strings.Join(e.Unexpected, "\n"))
+}
+
+// recorder is a Reporter that records the unexpected results.
+type recorder struct {
+	spec.NopReporter
+	unexpected []string
+}
+
+func (r *recorder) TestFinished(tr *spec.TestResult) {
+	id := fmt.Sprintf("%s/%d", tr.TestCase.Parent.ID(), tr.Sequence)
+	_, known := knownFailures[id]
+
+	switch {
+	case tr.Errored:
+		r.unexpected = append(r.unexpected, fmt.Sprintf("%s: errored: %s: %v", id, tr.TestCase.Desc, tr.Error))
+	case tr.Failed && !known:
+		r.unexpected = append(r.unexpected, fmt.Sprintf("%s: failed: %s", id, tr.TestCase.Desc))
+	case tr.Inconclusive:
+		r.unexpected = append(r.unexpected, fmt.Sprintf("%s: inconclusive: %s", id, tr.TestCase.Desc))
+	case !tr.Failed && !tr.Skipped && known:
+		r.unexpected = append(r.unexpected, fmt.Sprintf("%s: passed but known to fail: %s", id, tr.TestCase.Desc))
+	}
+}
+
+// Config returns the configuration to run the server specs against the
+// reference server, based on the specified configuration. The options
+// that change the expected results are reset.
+func Config(c *config.Config, s *Server) *config.Config {
+	return targetConfig(c, s.Port())
+}
+
+func targetConfig(c *config.Config, port int) *config.Config {
+	sc := *c
+	sc.Host = "127.0.0.1"
+	sc.Port = port
+	sc.Path = "/"
+	sc.ResourcePath = ResourcePath
+	sc.UploadPath = UploadPath
+	sc.EchoPath = ""
+	sc.TrailersPath = TrailersPath
+	sc.WebSocketPath = ""
+	sc.TLS = true
+	sc.Insecure = true
+	sc.Dialer = nil
+	sc.Strict = false
+	sc.Profile = ""
+	sc.ClientSettings = nil
+
+	return &sc
+}
+
+// Run runs the server specs against a new reference server in the same
+// process. The test cases that the reference server cannot exercise,
+// such as server push and the echo endpoint, are skipped. Error is
+// returned if any test case has an unexpected result.
+func Run(ctx context.Context, c *config.Config, reporters ...spec.Reporter) error {
+	s, err := NewServer()
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	r := &recorder{}
+	_, err = h2spec.RunContext(ctx, Config(c, s), append(reporters, r)...)
+	if err != nil {
+		return err
+	}
+
+	if len(r.unexpected) > 0 {
+		sort.Strings(r.unexpected)
+		return &Error{Unexpected: r.unexpected}
+	}
+
+	return nil
+}
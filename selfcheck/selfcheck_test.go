@@ -0,0 +1,44 @@
+package selfcheck
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/summerwind/h2spec/config"
+)
+
+func TestSelfCheck(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the run of all test cases in short mode")
+	}
+
+	// The timeout must exceed the delay in which net/http closes the
+	// connection after sending GOAWAY frame.
+	c := &config.Config{
+		Timeout:      2 * time.Second,
+		MaxHeaderLen: 4000,
+		UploadSize:   1 << 20,
+	}
+
+	err := Run(context.Background(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDefects(t *testing.T) {
+	c := &config.Config{
+		Timeout:      time.Second,
+		MaxHeaderLen: 4000,
+	}
+
+	for _, d := range Defects {
+		t.Run(d.String(), func(t *testing.T) {
+			err := RunDefect(context.Background(), c, d)
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
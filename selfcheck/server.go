@@ -0,0 +1,151 @@
+package selfcheck
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// ResourcePath is the path of the large resource served by the
+	// reference server.
+	ResourcePath = "/resource"
+
+	// TrailersPath is the path of the endpoint that sends trailers in
+	// the response of the reference server.
+	TrailersPath = "/trailers"
+
+	// UploadPath is the path of the endpoint that accepts large request
+	// bodies of the reference server.
+	UploadPath = "/upload"
+
+	// resourceSize is the size of the large resource, which exceeds
+	// the default initial window size.
+	resourceSize = 200000
+)
+
+// Server is the reference server, which is the HTTP/2 server of
+// net/http listening on the loopback address over TLS.
+type Server struct {
+	listener net.Listener
+	server   *http.Server
+}
+
+// NewServer starts the reference server on a random port of the
+// loopback address with a self-signed certificate.
+func NewServer() (*Server, error) {
+	cert, err := selfSignedCertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	resource := strings.Repeat("x", resourceSize)
+	mux := http.NewServeMux()
+	mux.HandleFunc(ResourcePath, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte(resource))
+	})
+	mux.HandleFunc(UploadPath, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte("h2spec reference server"))
+	})
+	mux.HandleFunc(TrailersPath, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Trailer", "x-h2spec-trailer")
+		w.Write([]byte("h2spec reference server"))
+		w.Header().Set("x-h2spec-trailer", "ok")
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		// The request body is read before the response so that the
+		// test cases sending DATA frames exercise the server. The body
+		// shorter than its content-length is rejected here.
+		_, err := io.Copy(io.Discard, r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("h2spec reference server"))
+	})
+
+	// CONNECT request has no path to route, so it is handled before the
+	// mux as a tunnel that discards the data.
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		io.Copy(io.Discard, r.Body)
+	})
+
+	server := &http.Server{
+		Handler: handler,
+		TLSConfig: &tls.Config{
+			Certificates: []tls.Certificate{cert},
+		},
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		listener: listener,
+		server:   server,
+	}
+	// ServeTLS enables HTTP/2 of net/http.
+	go server.ServeTLS(listener, "", "")
+
+	return s, nil
+}
+
+// Port returns the port number the server is listening on.
+func (s *Server) Port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+// Close stops the server.
+func (s *Server) Close() error {
+	return s.server.Close()
+}
+
+// selfSignedCertificate returns a certificate for the loopback address
+// signed by itself.
+func selfSignedCertificate() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{Organization: []string{"h2spec"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+	}, nil
+}
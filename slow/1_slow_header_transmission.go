@@ -0,0 +1,46 @@
+package slow
+
+import (
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+	"golang.org/x/net/http2"
+)
+
+func SlowHeaderTransmission() *spec.TestGroup {
+	tg := NewTestGroup("1", "Slow Header Transmission")
+
+	// An attacker can hold a stream open by sending the header block
+	// slowly in small CONTINUATION frames. The server cannot process
+	// the request until the header block ends, so an implementation
+	// without a timeout for reading the header block keeps the stream
+	// and its buffers indefinitely, in the manner of Slowloris.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Sends a small CONTINUATION frame at every interval without END_HEADERS flag",
+		Requirement: "The endpoint SHOULD reset the stream or terminate the connection within bounded time.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			var streamID uint32 = 1
+
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			headers := spec.CommonHeaders(c)
+			hp := http2.HeadersFrameParam{
+				StreamID:      streamID,
+				EndStream:     true,
+				EndHeaders:    false,
+				BlockFragment: conn.EncodeHeaders(headers),
+			}
+			conn.WriteHeaders(hp)
+
+			fragment := spec.LiteralHeaderField("x-dummy", "x")
+
+			return dribble(c, conn, streamID, func() error {
+				return conn.WriteContinuation(streamID, false, fragment)
+			})
+		},
+	})
+
+	return tg
+}
@@ -0,0 +1,86 @@
+package slow
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/net/http2"
+
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+)
+
+func IdleConnection() *spec.TestGroup {
+	tg := NewTestGroup("2", "Idle Connection")
+
+	// A server can close a connection that has been idle for a while
+	// to release its resources, preferably after sending GOAWAY frame
+	// with NO_ERROR so that the client knows that no request has been
+	// lost.
+	//
+	// Note: This test case measures how long the server keeps the idle
+	// connection alive. It always passes, since the idle timeout is a
+	// choice of the server.
+	tg.AddTestCase(&spec.TestCase{
+		Desc:        "Keeps the connection idle after the connection preface",
+		Requirement: "The endpoint MAY close the idle connection, preferably after sending GOAWAY frame with NO_ERROR.",
+		Run: func(c *config.Config, conn *spec.Conn) error {
+			err := conn.Handshake()
+			if err != nil {
+				return err
+			}
+
+			return idle(c, conn)
+		},
+	})
+
+	return tg
+}
+
+// idle waits without sending frames until the server closes the
+// connection or the duration has elapsed, and reports the time the
+// connection was kept alive. PING frames of the server are answered,
+// since they are not requests of the client.
+func idle(c *config.Config, conn *spec.Conn) error {
+	var goAway string
+
+	started := time.Now()
+	for {
+		remaining := c.SlowDuration - time.Since(started)
+		if remaining <= 0 {
+			break
+		}
+
+		ev := conn.WaitEventWithTimeout(remaining)
+		elapsed := time.Since(started).Round(time.Millisecond)
+
+		switch event := ev.(type) {
+		case spec.PingFrameEvent:
+			if !event.IsAck() {
+				conn.WritePing(true, event.Data)
+			}
+		case spec.GoAwayFrameEvent:
+			if goAway == "" {
+				noError := "without NO_ERROR"
+				if event.ErrCode == http2.ErrCodeNo {
+					noError = "with NO_ERROR"
+				}
+				goAway = fmt.Sprintf("GOAWAY Frame %s after %s", noError, elapsed)
+			}
+		case spec.ConnectionClosedEvent, spec.ErrorEvent:
+			if goAway == "" {
+				goAway = "no GOAWAY Frame"
+			}
+			return &spec.TestInfo{
+				Message: fmt.Sprintf("Connection closed after %s, %s", elapsed, goAway),
+			}
+		}
+	}
+
+	observed := fmt.Sprintf("Kept alive for %s", c.SlowDuration)
+	if goAway != "" {
+		observed = fmt.Sprintf("%s, %s", observed, goAway)
+	}
+
+	return &spec.TestInfo{Message: observed}
+}
@@ -0,0 +1,97 @@
+package slow
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/summerwind/h2spec/config"
+	"github.com/summerwind/h2spec/spec"
+)
+
+var key = "slow"
+
+func NewTestGroup(section string, name string) *spec.TestGroup {
+	return &spec.TestGroup{
+		Key:     key,
+		Section: section,
+		Name:    name,
+	}
+}
+
+func Spec() *spec.TestGroup {
+	tg := &spec.TestGroup{
+		Key:  key,
+		Name: "Slow Attack Resilience of HTTP/2 Servers",
+		Tags: []string{"slow"},
+	}
+
+	tg.AddTestGroup(SlowHeaderTransmission())
+	tg.AddTestGroup(IdleConnection())
+
+	return tg
+}
+
+// dribble sends a frame with write at every interval until the server
+// resets the stream, terminates the connection or the duration has
+// elapsed. The server is considered to have reacted when it resets the
+// specified stream, sends GOAWAY frame or closes the connection, and
+// the time it tolerated the dribble is reported. The dribble also stops
+// when reading from the connection fails.
+func dribble(c *config.Config, conn *spec.Conn, streamID uint32, write func() error) error {
+	var actual spec.Event
+
+	started := time.Now()
+
+	sent := 0
+	for time.Since(started) < c.SlowDuration {
+		ev := conn.WaitEventWithTimeout(c.SlowInterval)
+		elapsed := time.Since(started).Round(time.Second)
+
+		switch event := ev.(type) {
+		case spec.TimeoutEvent:
+			if write() != nil {
+				return &spec.TestInfo{
+					Message: fmt.Sprintf("Connection closed after %s (%d frames sent)", elapsed, sent),
+				}
+			}
+			sent++
+			continue
+		case spec.GoAwayFrameEvent:
+			return &spec.TestInfo{
+				Message: fmt.Sprintf("Terminated after %s: %s (%d frames sent)", elapsed, event, sent),
+			}
+		case spec.RSTStreamFrameEvent:
+			if event.Header().StreamID == streamID {
+				return &spec.TestInfo{
+					Message: fmt.Sprintf("Stream reset after %s: %s (%d frames sent)", elapsed, event, sent),
+				}
+			}
+		case spec.ConnectionClosedEvent:
+			return &spec.TestInfo{
+				Message: fmt.Sprintf("Connection closed after %s (%d frames sent)", elapsed, sent),
+			}
+		case spec.ErrorEvent:
+			// The connection is no longer readable, so the dribble
+			// stops as the connection has been closed.
+			return &spec.TestInfo{
+				Message: fmt.Sprintf("Connection lost after %s: %s (%d frames sent)", elapsed, event, sent),
+			}
+		}
+
+		actual = ev
+	}
+
+	observed := fmt.Sprintf("Tolerated for %s (%d frames sent)", c.SlowDuration, sent)
+	if actual != nil {
+		observed = fmt.Sprintf("%s, last frame: %s", observed, actual)
+	}
+
+	return &spec.TestError{
+		Expected: []string{
+			fmt.Sprintf("RST_STREAM Frame (stream_id:%d)", streamID),
+			"GOAWAY Frame",
+			spec.ExpectedConnectionClosed,
+		},
+		Actual: observed,
+	}
+}
@@ -0,0 +1,169 @@
+package spec
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// AltSvcFrame represents the payload of ALTSVC frame described in
+// RFC 7838 Section 4.
+type AltSvcFrame struct {
+	Origin     string
+	FieldValue string
+}
+
+// ParseAltSvcFrame parses the payload of the specified ALTSVC frame.
+func ParseAltSvcFrame(ev UnknownFrameEvent) (*AltSvcFrame, error) {
+	payload := ev.Payload
+	if len(payload) < 2 {
+		return nil, errors.New("ALTSVC frame is too short to contain Origin-Len")
+	}
+
+	originLen := int(binary.BigEndian.Uint16(payload))
+	payload = payload[2:]
+	if len(payload) < originLen {
+		return nil, errors.New("Origin-Len exceeds the frame payload")
+	}
+
+	f := &AltSvcFrame{
+		Origin:     string(payload[:originLen]),
+		FieldValue: string(payload[originLen:]),
+	}
+
+	return f, nil
+}
+
+// ValidAltSvcFieldValue returns true if the specified value conforms
+// to the syntax of Alt-Svc header field described in RFC 7838
+// Section 3.
+//
+//	Alt-Svc       = clear / 1#alt-value
+//	alt-value     = alternative *( OWS ";" OWS parameter )
+//	alternative   = protocol-id "=" alt-authority
+//	alt-authority = quoted-string
+//	parameter     = token "=" ( token / quoted-string )
+func ValidAltSvcFieldValue(v string) bool {
+	if v == "clear" {
+		return true
+	}
+
+	p := &fieldParser{s: v}
+	values := 0
+	for {
+		// Empty list elements are allowed by the list syntax.
+		p.ows()
+		if p.consume(',') {
+			continue
+		}
+		if p.eof() {
+			break
+		}
+
+		if !p.token() || !p.consume('=') || !p.quotedString() {
+			return false
+		}
+		values++
+
+		for {
+			p.ows()
+			if !p.consume(';') {
+				break
+			}
+
+			p.ows()
+			if !p.token() || !p.consume('=') {
+				return false
+			}
+			if !p.token() && !p.quotedString() {
+				return false
+			}
+		}
+
+		if !p.eof() && !p.consume(',') {
+			return false
+		}
+	}
+
+	return values > 0
+}
+
+// fieldParser scans the value of a HTTP header field.
+type fieldParser struct {
+	s string
+	i int
+}
+
+func (p *fieldParser) eof() bool {
+	return p.i >= len(p.s)
+}
+
+// ows skips the optional whitespaces.
+func (p *fieldParser) ows() {
+	for !p.eof() && (p.s[p.i] == ' ' || p.s[p.i] == '\t') {
+		p.i++
+	}
+}
+
+// consume skips the specified character if it is the next one.
+func (p *fieldParser) consume(c byte) bool {
+	if p.eof() || p.s[p.i] != c {
+		return false
+	}
+
+	p.i++
+	return true
+}
+
+// token skips a token and returns true if it is not empty.
+func (p *fieldParser) token() bool {
+	start := p.i
+	for !p.eof() && isTokenChar(p.s[p.i]) {
+		p.i++
+	}
+
+	return p.i > start
+}
+
+// quotedString skips a quoted-string and returns true if it is
+// terminated by DQUOTE.
+func (p *fieldParser) quotedString() bool {
+	if !p.consume('"') {
+		return false
+	}
+
+	for !p.eof() {
+		c := p.s[p.i]
+		p.i++
+
+		switch {
+		case c == '"':
+			return true
+		case c == '\\':
+			if p.eof() {
+				return false
+			}
+			p.i++
+		case c == '\t' || c == ' ' || c == 0x21 || (c >= 0x23 && c <= 0x5b) || (c >= 0x5d && c <= 0x7e) || c >= 0x80:
+		default:
+			return false
+		}
+	}
+
+	return false
+}
+
+// isTokenChar returns true if the specified character is tchar
+// described in RFC 7230 Section 3.2.6.
+func isTokenChar(c byte) bool {
+	switch {
+	case c >= '0' && c <= '9', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		return true
+	}
+
+	switch c {
+	case '!', '#', '$', '%', '&', '\'', '*', '+', '-', '.', '^', '_', '`', '|', '~':
+		return true
+	}
+
+	return false
+}
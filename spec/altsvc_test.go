@@ -0,0 +1,111 @@
+package spec
+
+import (
+	"testing"
+
+	"golang.org/x/net/http2"
+)
+
+// altSvcFrame returns ALTSVC frame on the specified stream with the
+// payload of the origin and the field value.
+func altSvcFrame(streamID uint32, origin, value string) UnknownFrameEvent {
+	payload := []byte{byte(len(origin) >> 8), byte(len(origin))}
+	payload = append(payload, origin...)
+	payload = append(payload, value...)
+
+	return UnknownFrameEvent{
+		FrameHeader: http2.FrameHeader{Type: FrameAltSvc, StreamID: streamID, Length: uint32(len(payload))},
+		Payload:     payload,
+	}
+}
+
+func TestParseAltSvcFrame(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload []byte
+		origin  string
+		value   string
+		err     bool
+	}{
+		{name: "origin and field value", payload: altSvcFrame(0, "https://example.com", `h2=":443"`).Payload, origin: "https://example.com", value: `h2=":443"`},
+		{name: "empty origin", payload: altSvcFrame(1, "", `h2=":443"`).Payload, value: `h2=":443"`},
+		{name: "no field value", payload: altSvcFrame(0, "https://example.com", "").Payload, origin: "https://example.com"},
+		{name: "no Origin-Len", payload: []byte{0x00}, err: true},
+		{name: "truncated origin", payload: []byte{0x00, 0x13, 'h', 't', 't', 'p', 's'}, err: true},
+	}
+
+	for _, test := range tests {
+		ev := UnknownFrameEvent{
+			FrameHeader: http2.FrameHeader{Type: FrameAltSvc},
+			Payload:     test.payload,
+		}
+
+		f, err := ParseAltSvcFrame(ev)
+		if test.err {
+			if err == nil {
+				t.Errorf("%s - expected:error, actual:%+v", test.name, f)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s - unexpected error: %v", test.name, err)
+			continue
+		}
+		if f.Origin != test.origin || f.FieldValue != test.value {
+			t.Errorf("%s - expected:(%q, %q), actual:(%q, %q)", test.name, test.origin, test.value, f.Origin, f.FieldValue)
+		}
+	}
+}
+
+func TestVerifyAltSvcFrame(t *testing.T) {
+	tests := []struct {
+		name  string
+		ev    UnknownFrameEvent
+		valid bool
+	}{
+		{name: "origin on stream 0", ev: altSvcFrame(0, "https://example.com", `h2=":443"`), valid: true},
+		{name: "empty origin on stream 1", ev: altSvcFrame(1, "", `h2=":443"`), valid: true},
+		{name: "empty origin on stream 0", ev: altSvcFrame(0, "", `h2=":443"`)},
+		{name: "origin on stream 1", ev: altSvcFrame(1, "https://example.com", `h2=":443"`)},
+		{name: "malformed field value", ev: altSvcFrame(0, "https://example.com", `h2=443`)},
+	}
+
+	for _, test := range tests {
+		err := verifyAltSvcFrame(test.ev)
+		if test.valid && err != nil {
+			t.Errorf("%s - expected:nil, actual:%v", test.name, err)
+		}
+		if _, ok := err.(*TestError); !test.valid && !ok {
+			t.Errorf("%s - expected:TestError, actual:%v", test.name, err)
+		}
+	}
+}
+
+func TestValidAltSvcFieldValue(t *testing.T) {
+	tests := []struct {
+		value string
+		valid bool
+	}{
+		{value: "clear", valid: true},
+		{value: `h2=":443"`, valid: true},
+		{value: `h2="alt.example.com:443"; ma=3600`, valid: true},
+		{value: `h2=":443"; ma=3600; persist=1, h3=":443"`, valid: true},
+		{value: `h2=":443", , h3=":8443"`, valid: true},
+		{value: ""},
+		{value: "h2"},
+		{value: `h2=:443`},
+		{value: `h2=":443`},
+		{value: `=":443"`},
+		{value: `h2=":443"; ma`},
+		{value: `h2=":443"; ma=`},
+		{value: `h2=":443" h3=":443"`},
+		{value: "clear, clear"},
+	}
+
+	for _, test := range tests {
+		if valid := ValidAltSvcFieldValue(test.value); valid != test.valid {
+			t.Errorf("%q - expected:%v, actual:%v", test.value, test.valid, valid)
+		}
+	}
+}
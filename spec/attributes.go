@@ -0,0 +1,196 @@
+package spec
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/http2"
+
+	"github.com/summerwind/h2spec/log"
+)
+
+// FrameAttributes represents the attributes of a frame, or the state of
+// the connection, compared by a test case. An empty attribute of the
+// expected frame matches any value, and the expected attribute prefixed
+// with ">=" matches the number greater than or equal to it.
+type FrameAttributes struct {
+	Type         string `json:"type,omitempty"`
+	Flags        string `json:"flags,omitempty"`
+	ErrorCode    string `json:"error_code,omitempty"`
+	StreamID     string `json:"stream_id,omitempty"`
+	LastStreamID string `json:"last_stream_id,omitempty"`
+	State        string `json:"state,omitempty"`
+}
+
+// attributeNames is the names of the attributes in the order of the
+// table of the failure.
+var attributeNames = []string{"type", "flags", "error_code", "stream_id", "last_stream_id", "state"}
+
+// values returns the attributes in the order of attributeNames.
+func (a *FrameAttributes) values() []string {
+	return []string{a.Type, a.Flags, a.ErrorCode, a.StreamID, a.LastStreamID, a.State}
+}
+
+// GoAwayAttributes returns the attributes of the expected GOAWAY frame
+// with the specified error code.
+func GoAwayAttributes(code http2.ErrCode) FrameAttributes {
+	return FrameAttributes{
+		Type:      http2.FrameGoAway.String(),
+		ErrorCode: code.String(),
+		StreamID:  "0",
+	}
+}
+
+// RSTStreamAttributes returns the attributes of the expected RST_STREAM
+// frame with the specified error code on the stream. The stream
+// identifier is left unspecified for AnyStreamID.
+func RSTStreamAttributes(streamID uint32, code http2.ErrCode) FrameAttributes {
+	attrs := FrameAttributes{
+		Type:      http2.FrameRSTStream.String(),
+		ErrorCode: code.String(),
+	}
+	if streamID != AnyStreamID {
+		attrs.StreamID = fmt.Sprintf("%d", streamID)
+	}
+	return attrs
+}
+
+// ackAttributes returns the attributes of the expected frame of the
+// type with ACK flag on stream 0.
+func ackAttributes(t http2.FrameType) FrameAttributes {
+	return FrameAttributes{
+		Type:     t.String(),
+		Flags:    fmt.Sprintf("0x%02x", http2.FlagSettingsAck),
+		StreamID: "0",
+	}
+}
+
+// streamAttributes returns the attributes of the expected frame of the
+// type on the specified stream.
+func streamAttributes(t http2.FrameType, streamID uint32) FrameAttributes {
+	return FrameAttributes{
+		Type:     t.String(),
+		StreamID: fmt.Sprintf("%d", streamID),
+	}
+}
+
+// ClosedAttributes returns the attributes of the expected connection
+// close.
+func ClosedAttributes() FrameAttributes {
+	return FrameAttributes{State: "closed"}
+}
+
+// EventAttributes returns the attributes of the received event. The
+// connection is open while frames are received.
+func EventAttributes(ev Event) *FrameAttributes {
+	switch event := ev.(type) {
+	case nil:
+		return nil
+	case ConnectionClosedEvent:
+		return &FrameAttributes{State: "closed"}
+	case TimeoutEvent:
+		return &FrameAttributes{State: "timeout"}
+	case EventFrame:
+		header := event.Header()
+
+		name, ok := extensionFrameName[header.Type]
+		if !ok {
+			name = header.Type.String()
+		}
+
+		attrs := &FrameAttributes{
+			Type:     name,
+			Flags:    fmt.Sprintf("0x%02x", header.Flags),
+			StreamID: fmt.Sprintf("%d", header.StreamID),
+			State:    "open",
+		}
+
+		switch frame := ev.(type) {
+		case GoAwayFrameEvent:
+			attrs.ErrorCode = frame.ErrCode.String()
+			attrs.LastStreamID = fmt.Sprintf("%d", frame.LastStreamID)
+		case RSTStreamFrameEvent:
+			attrs.ErrorCode = frame.ErrCode.String()
+		}
+
+		return attrs
+	default:
+		return nil
+	}
+}
+
+// printAttributes prints the table of the attributes of the actual
+// frame and each acceptable alternative of the expected frame. The
+// cells of the alternatives that do not match the actual attribute are
+// highlighted.
+func printAttributes(expected []FrameAttributes, actual *FrameAttributes) {
+	header := []string{"", "Actual"}
+	for i := range expected {
+		header = append(header, fmt.Sprintf("Expected #%d", i+1))
+	}
+
+	rows := [][]string{header}
+	for i, name := range attributeNames {
+		row := []string{name, dash(actual.values()[i])}
+		for _, ex := range expected {
+			row = append(row, dash(ex.values()[i]))
+		}
+		rows = append(rows, row)
+	}
+
+	widths := make([]int, len(header))
+	for _, row := range rows {
+		for i, cell := range row {
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+
+	for r, row := range rows {
+		cells := []string{}
+		for i, cell := range row {
+			padded := cell
+			if i < len(row)-1 {
+				padded += strings.Repeat(" ", widths[i]-len(cell))
+			}
+
+			switch {
+			case r == 0 || i == 0:
+				padded = gray(padded)
+			case i > 1 && mismatches(expected[i-2].values()[r-1], actual.values()[r-1]):
+				padded = red(padded)
+			}
+			cells = append(cells, padded)
+		}
+		log.Println(fmt.Sprintf("   %s", strings.Join(cells, "  ")))
+	}
+}
+
+// mismatches returns true if the expected attribute is specified and
+// differs from the actual one.
+func mismatches(expected, actual string) bool {
+	if expected == "" {
+		return false
+	}
+
+	if strings.HasPrefix(expected, ">=") {
+		minVal, err := strconv.ParseUint(strings.TrimPrefix(expected, ">="), 10, 32)
+		if err != nil {
+			return true
+		}
+		val, err := strconv.ParseUint(actual, 10, 32)
+		return err != nil || val < minVal
+	}
+
+	return expected != actual
+}
+
+// dash returns "-" for the unspecified attribute.
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
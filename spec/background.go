@@ -0,0 +1,37 @@
+package spec
+
+// isBackground returns true if the event is a frame that the peer may
+// send at any time regardless of the frames sent by the test case:
+// SETTINGS and PING frames without ACK flag, and WINDOW_UPDATE frames.
+func isBackground(ev Event) bool {
+	switch event := ev.(type) {
+	case SettingsFrameEvent:
+		return !event.IsAck()
+	case PingFrameEvent:
+		return !event.IsAck()
+	case WindowUpdateFrameEvent:
+		return true
+	default:
+		return false
+	}
+}
+
+// skipBackground returns true if the event is a frame of the background
+// traffic, which does not settle the result of the verification. The
+// frame has been logged when it is received, and SETTINGS and PING
+// frames are acknowledged here as required. Nothing is skipped if
+// KeepBackground of the connection is set.
+func (conn *Conn) skipBackground(ev Event) bool {
+	if conn.KeepBackground || !isBackground(ev) {
+		return false
+	}
+
+	switch event := ev.(type) {
+	case SettingsFrameEvent:
+		conn.WriteSettingsAck()
+	case PingFrameEvent:
+		conn.WritePing(true, event.Data)
+	}
+
+	return true
+}
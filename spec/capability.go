@@ -0,0 +1,400 @@
+package spec
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sort"
+	"time"
+
+	"golang.org/x/net/http2"
+
+	"github.com/summerwind/h2spec/config"
+)
+
+// Capability represents a capability of the server or the endpoints
+// of the target that a test case requires to be meaningful.
+type Capability int
+
+const (
+	// CapabilityPush requires the server to push in the response of
+	// the target path.
+	CapabilityPush Capability = iota + 1
+	// CapabilityConnectProtocol requires the server to advertise
+	// SETTINGS_ENABLE_CONNECT_PROTOCOL with 1.
+	CapabilityConnectProtocol
+	// CapabilityAltSvc requires the server to send ALTSVC frames in
+	// the response of the target path.
+	CapabilityAltSvc
+	// CapabilityOrigin requires the server to send ORIGIN frames in
+	// the response of the target path.
+	CapabilityOrigin
+	// CapabilityEchoEndpoint requires the path of the echo endpoint.
+	CapabilityEchoEndpoint
+	// CapabilityTrailersEndpoint requires the path of an endpoint that
+	// sends trailers.
+	CapabilityTrailersEndpoint
+	// CapabilityWebSocketEndpoint requires the path of the WebSocket
+	// endpoint.
+	CapabilityWebSocketEndpoint
+	// CapabilityLargeResource requires the path of a large resource.
+	CapabilityLargeResource
+	// CapabilityUploadEndpoint requires the path of an endpoint that
+	// accepts large request bodies.
+	CapabilityUploadEndpoint
+	// CapabilityTLS requires the connection over TLS.
+	CapabilityTLS
+	// CapabilityEarlyData requires the dialer that sends TLS 0-RTT
+	// early data.
+	CapabilityEarlyData
+)
+
+// unmetReasons is the reason of the skip of the test case that requires
+// each capability.
+var unmetReasons = map[Capability]string{
+	CapabilityPush:              "server push is not observed",
+	CapabilityConnectProtocol:   "SETTINGS_ENABLE_CONNECT_PROTOCOL is not advertised",
+	CapabilityAltSvc:            "ALTSVC frame is not observed",
+	CapabilityOrigin:            "ORIGIN frame is not observed",
+	CapabilityEchoEndpoint:      "requires echo endpoint",
+	CapabilityTrailersEndpoint:  "requires trailers endpoint",
+	CapabilityWebSocketEndpoint: "requires websocket endpoint",
+	CapabilityLargeResource:     "requires large resource path",
+	CapabilityUploadEndpoint:    "requires upload endpoint",
+	CapabilityTLS:               "requires TLS",
+	CapabilityEarlyData:         "requires a TLS client that sends early data",
+}
+
+// Capabilities represents the capabilities of the server detected
+// before the test cases are run.
+type Capabilities struct {
+	// Settings is the settings advertised by the server in the server
+	// connection preface.
+	Settings map[http2.SettingID]uint32
+
+	// Push is true if the server sent PUSH_PROMISE frame in the
+	// response of the target path.
+	Push bool
+
+	// AltSvc and Origin are true if the server sent ALTSVC and ORIGIN
+	// frame respectively while responding to the target path.
+	AltSvc bool
+	Origin bool
+
+	// Address is the address of the server the connection was made
+	// to, which is chosen from the addresses of the host.
+	Address string
+
+	// RTT is the round-trip time measured by PING frames.
+	RTT RoundTripTime
+
+	// Server is the value of "server" header field of the response to
+	// the target path, or empty if it was not sent.
+	Server string
+
+	// TLS is the parameters negotiated by TLS handshake, or nil if the
+	// connection is not over TLS.
+	TLS *TLSParameters
+}
+
+// TLSParameters represents the parameters negotiated by TLS handshake.
+type TLSParameters struct {
+	Version     string `json:"version"`
+	CipherSuite string `json:"cipher_suite"`
+	ALPN        string `json:"alpn"`
+}
+
+// AdvertisedSetting represents a setting advertised by the server.
+// Default is true if the value is the initial value defined by the
+// specification.
+type AdvertisedSetting struct {
+	Name    string `json:"name"`
+	Value   uint32 `json:"value"`
+	Default bool   `json:"default,omitempty"`
+}
+
+// extensionSettingName is the names of the settings defined by the
+// extensions, which are unknown to the http2 package.
+var extensionSettingName = map[http2.SettingID]string{
+	SettingEnableConnectProtocol: "ENABLE_CONNECT_PROTOCOL",
+	SettingNoRFC7540Priorities:   "NO_RFC7540_PRIORITIES",
+}
+
+// initialSettingValues is the initial value of each setting. The
+// settings whose initial value is unlimited are not included.
+var initialSettingValues = map[http2.SettingID]uint32{
+	http2.SettingHeaderTableSize:   4096,
+	http2.SettingEnablePush:        1,
+	http2.SettingInitialWindowSize: DefaultWindowSize,
+	http2.SettingMaxFrameSize:      DefaultFrameSize,
+	SettingEnableConnectProtocol:   0,
+	SettingNoRFC7540Priorities:     0,
+}
+
+// AdvertisedSettings returns the settings advertised by the server in
+// the order of their identifiers.
+func (caps *Capabilities) AdvertisedSettings() []AdvertisedSetting {
+	ids := []http2.SettingID{}
+	for id := range caps.Settings {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	settings := []AdvertisedSetting{}
+	for _, id := range ids {
+		name, ok := extensionSettingName[id]
+		if !ok {
+			name = id.String()
+		}
+
+		val := caps.Settings[id]
+		initial, ok := initialSettingValues[id]
+
+		settings = append(settings, AdvertisedSetting{
+			Name:    name,
+			Value:   val,
+			Default: ok && initial == val,
+		})
+	}
+
+	return settings
+}
+
+// rttPings is the number of PING frames sent to measure the round-trip
+// time.
+const rttPings = 5
+
+// RoundTripTime represents the round-trip time of the connection
+// measured from sending PING frames to receiving their ACKs.
+type RoundTripTime struct {
+	Min    time.Duration
+	Median time.Duration
+
+	// Samples is the number of PING frames acknowledged within the
+	// timeout, or zero if the round-trip time was not measured.
+	Samples int
+}
+
+// detection is the result of the capability detection stored in the
+// configuration of the run.
+type detection struct {
+	caps *Capabilities
+	err  error
+}
+
+// DetectCapabilities connects to the server and detects its
+// capabilities from the settings advertised and the frames sent in the
+// response of the target path with server push enabled. The result is
+// stored in the configuration and used to skip the test cases whose
+// requirements are not met. If the detection fails, the test cases
+// requiring the capabilities of the server are skipped.
+func DetectCapabilities(ctx context.Context, c *config.Config) (*Capabilities, error) {
+	caps, err := detectCapabilities(ctx, c)
+	c.Detection = &detection{caps: caps, err: err}
+	return caps, err
+}
+
+// DetectedCapabilities returns the capabilities detected by
+// DetectCapabilities with the configuration, or nil if the detection
+// failed or has not been performed.
+func DetectedCapabilities(c *config.Config) *Capabilities {
+	d, ok := c.Detection.(*detection)
+	if !ok {
+		return nil
+	}
+	return d.caps
+}
+
+func detectCapabilities(ctx context.Context, c *config.Config) (*Capabilities, error) {
+	conn, err := DialContext(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// The detection is not a test case, so its frames are written only
+	// to the event log.
+	conn.Verbose = false
+	conn.testID = "detection"
+
+	setting := http2.Setting{
+		ID:  http2.SettingEnablePush,
+		Val: 1,
+	}
+	err = conn.HandshakeWithSettings(setting)
+	if err != nil {
+		return nil, err
+	}
+
+	caps := &Capabilities{
+		Settings: map[http2.SettingID]uint32{},
+		Address:  conn.RemoteAddr().String(),
+	}
+	for id, val := range conn.Settings {
+		caps.Settings[id] = val
+	}
+	if state, ok := conn.TLSConnectionState(); ok {
+		caps.TLS = &TLSParameters{
+			Version:     tls.VersionName(state.Version),
+			CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+			ALPN:        state.NegotiatedProtocol,
+		}
+	}
+	caps.RTT = measureRTT(conn, caps.observe)
+
+	WriteRequest(conn, c, 1)
+
+	res := NewResponse(1)
+	for !conn.Closed && !res.Ended && !res.stopped {
+		ev := conn.WaitEvent()
+		if _, ok := ev.(ErrorEvent); ok {
+			break
+		}
+
+		caps.observe(ev)
+		res.add(ev)
+	}
+	caps.Server, _ = headerFieldValue(res.Headers, "server")
+
+	return caps, nil
+}
+
+// observe records the capabilities indicated by the frame received
+// during the detection.
+func (caps *Capabilities) observe(ev Event) {
+	switch event := ev.(type) {
+	case PushPromiseFrameEvent:
+		caps.Push = true
+	case UnknownFrameEvent:
+		switch event.Header().Type {
+		case FrameAltSvc:
+			caps.AltSvc = true
+		case FrameOrigin:
+			caps.Origin = true
+		}
+	}
+}
+
+// measureRTT sends PING frames one by one and measures the time until
+// each ACK is received. The measurement stops at the first PING frame
+// that is not acknowledged within the timeout. The other frames
+// received meanwhile are passed to observe.
+func measureRTT(conn *Conn, observe func(Event)) RoundTripTime {
+	samples := []time.Duration{}
+
+	for i := 0; i < rttPings && !conn.Closed; i++ {
+		data := [8]byte{'h', '2', 's', 'p', 'e', 'c', 0, byte(i)}
+
+		sent := time.Now()
+		err := conn.WritePing(false, data)
+		if err != nil {
+			break
+		}
+
+		acked := false
+		for !conn.Closed && !acked {
+			ev := conn.WaitEventWithTimeout(conn.Timeout)
+			if _, ok := ev.(TimeoutEvent); ok {
+				break
+			}
+			if _, ok := ev.(ErrorEvent); ok {
+				break
+			}
+
+			ping, ok := ev.(PingFrameEvent)
+			acked = ok && ping.IsAck() && ping.Data == data
+			if !ok {
+				observe(ev)
+			}
+		}
+		if !acked {
+			break
+		}
+
+		samples = append(samples, time.Since(sent))
+	}
+
+	if len(samples) == 0 {
+		return RoundTripTime{}
+	}
+
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i] < samples[j]
+	})
+
+	median := samples[len(samples)/2]
+	if len(samples)%2 == 0 {
+		median = (samples[len(samples)/2-1] + median) / 2
+	}
+
+	return RoundTripTime{
+		Min:     samples[0],
+		Median:  median,
+		Samples: len(samples),
+	}
+}
+
+// unmetReason returns the reason why the capability is not met, or an
+// empty string if it is met.
+func (cp Capability) unmetReason(c *config.Config) string {
+	met := false
+
+	switch cp {
+	case CapabilityEchoEndpoint:
+		met = (c.EchoPath != "")
+	case CapabilityTrailersEndpoint:
+		met = (c.TrailersPath != "")
+	case CapabilityWebSocketEndpoint:
+		met = (c.WebSocketPath != "")
+	case CapabilityLargeResource:
+		met = (c.ResourcePath != "")
+	case CapabilityUploadEndpoint:
+		met = (c.UploadPath != "")
+	case CapabilityTLS:
+		met = c.TLS
+	case CapabilityEarlyData:
+		_, ok := c.Dialer.(config.EarlyDataWrapper)
+		met = (c.TLS && ok)
+	default:
+		d, ok := c.Detection.(*detection)
+		if !ok {
+			return "capability detection is not performed"
+		}
+		if d.caps == nil {
+			return fmt.Sprintf("capability detection failed: %v", d.err)
+		}
+		detected := d.caps
+
+		switch cp {
+		case CapabilityPush:
+			met = detected.Push
+		case CapabilityConnectProtocol:
+			met = (detected.Settings[SettingEnableConnectProtocol] == 1)
+		case CapabilityAltSvc:
+			met = detected.AltSvc
+		case CapabilityOrigin:
+			met = detected.Origin
+		}
+	}
+
+	if met {
+		return ""
+	}
+
+	return unmetReasons[cp]
+}
+
+// unmetRequirement returns the reason of the first requirement of the
+// test case that is not met, or an empty string if all of them are
+// met.
+func (tc *TestCase) unmetRequirement(c *config.Config) string {
+	for _, cp := range tc.Requires {
+		if reason := cp.unmetReason(c); reason != "" {
+			return reason
+		}
+	}
+
+	return ""
+}
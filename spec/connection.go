@@ -2,12 +2,16 @@ package spec
 
 import (
 	"bytes"
+	"context"
+	"crypto/rand"
 	"crypto/tls"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"io"
 	"net"
-	"syscall"
+	"net/url"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/net/http2"
@@ -24,6 +28,26 @@ const (
 	DefaultFrameSize = 16384
 )
 
+// connectionAttemptDelay is the delay before the connection attempt to
+// the address of the other family starts.
+const connectionAttemptDelay = 250 * time.Millisecond
+
+const (
+	// SettingNoRFC7540Priorities is the identifier of
+	// SETTINGS_NO_RFC7540_PRIORITIES defined in RFC 9218.
+	SettingNoRFC7540Priorities http2.SettingID = 0x9
+	// SettingEnableConnectProtocol is the identifier of
+	// SETTINGS_ENABLE_CONNECT_PROTOCOL defined in RFC 8441.
+	SettingEnableConnectProtocol http2.SettingID = 0x8
+	// FramePriorityUpdate is the type of PRIORITY_UPDATE frame defined
+	// in RFC 9218.
+	FramePriorityUpdate http2.FrameType = 0x10
+	// FrameAltSvc is the type of ALTSVC frame defined in RFC 7838.
+	FrameAltSvc http2.FrameType = 0xa
+	// FrameOrigin is the type of ORIGIN frame defined in RFC 8336.
+	FrameOrigin http2.FrameType = 0xc
+)
+
 // Conn represent a HTTP/2 connection.
 // This struct contains settings information, current window size,
 // encoder of HPACK and frame encoder.
@@ -33,59 +57,125 @@ type Conn struct {
 	Settings map[http2.SettingID]uint32
 	Timeout  time.Duration
 	Verbose  bool
+	Strict   bool
 	Closed   bool
 
+	// KeepBackground disables the skipping of SETTINGS, PING and
+	// WINDOW_UPDATE frames received in the background by the
+	// verifications, for the test cases that expect these frames.
+	KeepBackground bool
+
+	// SettingsHistory is the list of settings of each SETTINGS frame
+	// without ACK flag received from the peer.
+	SettingsHistory [][]http2.Setting
+
 	WindowUpdate bool
 	WindowSize   map[uint32]int
 
-	framer     *http2.Framer
-	encoder    *hpack.Encoder
-	encoderBuf *bytes.Buffer
-	decoder    *hpack.Decoder
+	// GoAwayDebugData is the additional debug data of the last GOAWAY
+	// frame received from the peer.
+	GoAwayDebugData []byte
+
+	// SettingsAckLatency is the time from sending the SETTINGS frame of
+	// the client connection preface to receiving its ACK, or zero if
+	// the handshake has not completed.
+	SettingsAckLatency time.Duration
+
+	framer       *http2.Framer
+	encoder      *hpack.Encoder
+	encoderBuf   *bytes.Buffer
+	decoder      *hpack.Decoder
+	headerFields []hpack.HeaderField
+
+	maxHeaderListSize uint32
+
+	// clientSettings is the settings sent in the SETTINGS frame of the
+	// client connection preface.
+	clientSettings []http2.Setting
+
+	// initialWindowSize is the initial window size of the streams
+	// sent in the client connection preface.
+	initialWindowSize int
+
+	// sendWindows is the flow control windows of the peer available
+	// for sending DATA frames, whose key 0x0 is the connection, and
+	// peerInitialWindowSize is the initial window size of the streams
+	// advertised by the peer.
+	sendWindows           map[uint32]int
+	peerInitialWindowSize int
 
 	debugFramer    *http2.Framer
 	debugFramerBuf *bytes.Buffer
 
+	// pendingFrames is the frames other than SETTINGS frame received
+	// during the handshake, which are returned by WaitEvent before
+	// reading the next frame.
+	pendingFrames []http2.Frame
+
+	// ctx is the context of the test case using the connection. The
+	// connection is closed when the context is done.
+	ctx  context.Context
+	stop func() bool
+
+	// closed is true once Close has been called, and peerClosed is true
+	// once the peer has closed the connection. The close is logged only
+	// by the first call of Close before the peer closed the connection.
+	closed     bool
+	peerClosed bool
+
+	// testID is the identifier of the test case using the connection,
+	// which is written to the event log.
+	testID string
+
+	// nonce is the random value of the connection substituted for
+	// "{rand}" in the path of the requests.
+	nonce string
+
+	// loggedPaths is the expanded paths of the requests that have been
+	// logged on the connection.
+	loggedPaths map[string]bool
+
+	// traffic is the traffic of the test case using the connection,
+	// which is shared by the connections of the test case.
+	traffic *Traffic
+
 	server bool
 }
 
 // Dial connects to the server based on configuration.
 func Dial(c *config.Config) (*Conn, error) {
-	var baseConn net.Conn
-	var err error
-
-	if c.TLS {
-		dialer := &net.Dialer{}
-		dialer.Timeout = c.Timeout
-
-		tlsConfig, err := c.TLSConfig()
-		if err != nil {
-			return nil, err
-		}
+	return DialContext(context.Background(), c)
+}
 
-		tlsConn, err := tls.DialWithDialer(dialer, "tcp", c.Addr(), tlsConfig)
-		if err != nil {
-			return nil, err
-		}
+// DialContext connects to the server based on configuration. The
+// connection is closed when the context is done, so that waiting for
+// the next event stops promptly.
+func DialContext(ctx context.Context, c *config.Config) (*Conn, error) {
+	baseConn, _, err := dialTransport(ctx, c, nil)
+	if err != nil {
+		return nil, err
+	}
 
-		cs := tlsConn.ConnectionState()
-		if !cs.NegotiatedProtocolIsMutual {
-			return nil, errors.New("Protocol negotiation failed")
-		}
+	return newConn(ctx, c, baseConn), nil
+}
 
-		baseConn = tlsConn
-	} else {
-		baseConn, err = net.DialTimeout("tcp", c.Addr(), c.Timeout)
-		if err != nil {
-			return nil, err
-		}
+// DialWithEarlyData connects to the server resuming the TLS session with
+// the specified data sent as 0-RTT early data, and returns whether the
+// server accepted the early data. The Dialer of the configuration must
+// implement config.EarlyDataWrapper, since crypto/tls does not send
+// early data. The connection is closed when the context is done.
+func DialWithEarlyData(ctx context.Context, c *config.Config, earlyData []byte) (*Conn, bool, error) {
+	baseConn, accepted, err := dialTransport(ctx, c, earlyData)
+	if err != nil {
+		return nil, false, err
 	}
 
-	settings := map[http2.SettingID]uint32{}
+	return newConn(ctx, c, baseConn), accepted, nil
+}
 
-	framer := http2.NewFramer(baseConn, baseConn)
-	framer.AllowIllegalWrites = true
-	framer.AllowIllegalReads = true
+// newConn returns the client connection on the transport connection.
+func newConn(ctx context.Context, c *config.Config, baseConn net.Conn) *Conn {
+	settings := map[http2.SettingID]uint32{}
 
 	var encoderBuf bytes.Buffer
 	encoder := hpack.NewEncoder(&encoderBuf)
@@ -97,35 +187,171 @@ func Dial(c *config.Config) (*Conn, error) {
 		Settings: settings,
 		Timeout:  c.Timeout,
 		Verbose:  c.Verbose,
+		Strict:   c.Strict,
 		Closed:   false,
 
 		WindowUpdate: true,
 		WindowSize:   map[uint32]int{0: DefaultWindowSize},
 
-		framer:     framer,
 		encoder:    encoder,
 		encoderBuf: &encoderBuf,
 		decoder:    decoder,
 
-		server: false,
+		clientSettings:    mergeSettings(defaultClientSettings, c.ClientSettings...),
+		initialWindowSize: DefaultWindowSize,
+
+		sendWindows:           map[uint32]int{0: DefaultWindowSize},
+		peerInitialWindowSize: DefaultWindowSize,
+
+		ctx:     ctx,
+		traffic: trafficFromContext(ctx),
+		server:  false,
 	}
+	conn.framer = newFramer(&conn)
+	atomic.AddInt64(&conn.traffic.Connections, 1)
 
-	if conn.Verbose {
+	conn.decoder.SetEmitFunc(func(f hpack.HeaderField) {
+		conn.headerFields = append(conn.headerFields, f)
+	})
+
+	if conn.Verbose || eventLog != nil {
 		conn.debugFramerBuf = new(bytes.Buffer)
 		conn.debugFramer = http2.NewFramer(conn.debugFramerBuf, conn.debugFramerBuf)
 		conn.debugFramer.AllowIllegalWrites = true
 		conn.debugFramer.AllowIllegalReads = true
 	}
 
-	return &conn, nil
-}
+	conn.stop = context.AfterFunc(ctx, func() {
+		conn.Conn.Close()
+	})
 
-func Accept(c *config.Config, baseConn net.Conn) (*Conn, error) {
-	settings := map[http2.SettingID]uint32{}
+	return &conn
+}
 
-	framer := http2.NewFramer(baseConn, baseConn)
+// newFramer returns the framer that reads and writes the frames on the
+// connection, counting them in the traffic.
+func newFramer(conn *Conn) *http2.Framer {
+	framer := http2.NewFramer(frameWriter{conn}, conn)
 	framer.AllowIllegalWrites = true
 	framer.AllowIllegalReads = true
+	return framer
+}
+
+// Dial opens another connection for the test case using the
+// connection. The connection is closed when the test case is cancelled,
+// and its traffic is counted in the test case.
+func (conn *Conn) Dial(c *config.Config) (*Conn, error) {
+	dc, err := DialContext(conn.ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	dc.testID = conn.testID
+	return dc, nil
+}
+
+// DialWithEarlyData opens another connection for the test case using
+// the connection as DialWithEarlyData does. The connection is closed
+// when the test case is cancelled, and its traffic is counted in the
+// test case.
+func (conn *Conn) DialWithEarlyData(c *config.Config, earlyData []byte) (*Conn, bool, error) {
+	dc, accepted, err := DialWithEarlyData(conn.ctx, c, earlyData)
+	if err != nil {
+		return nil, false, err
+	}
+
+	dc.testID = conn.testID
+	return dc, accepted, nil
+}
+
+// dialTransport establishes the transport connection to the target
+// with the dialer of the configuration, and establishes TLS on it if
+// TLS is enabled. If the early data is specified, it is sent in TLS
+// handshake and whether the server accepted it is returned.
+func dialTransport(ctx context.Context, c *config.Config, earlyData []byte) (net.Conn, bool, error) {
+	// The default dialer races the connections over IPv6 and IPv4 if
+	// the host has addresses of both families, starting the fallback
+	// after the delay recommended by RFC 8305.
+	dialer := c.Dialer
+	if dialer == nil {
+		dialer = &net.Dialer{
+			Timeout:       c.Timeout,
+			FallbackDelay: connectionAttemptDelay,
+		}
+	}
+
+	ew, ok := dialer.(config.EarlyDataWrapper)
+	if earlyData != nil && (!ok || !c.TLS) {
+		return nil, false, errors.New("Early data is not supported by the dialer")
+	}
+
+	nc, err := dialer.DialContext(ctx, c.Network(), c.Addr())
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !c.TLS {
+		return nc, false, nil
+	}
+
+	tlsConfig, err := c.TLSConfig()
+	if err != nil {
+		nc.Close()
+		return nil, false, err
+	}
+
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = c.Host
+	}
+
+	tctx := ctx
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		tctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	var tlsConn net.Conn
+	accepted := false
+	if earlyData != nil {
+		tlsConn, accepted, err = ew.WrapTLSWithEarlyData(tctx, nc, tlsConfig, earlyData)
+	} else if w, ok := dialer.(config.TLSWrapper); ok {
+		tlsConn, err = w.WrapTLS(tctx, nc, tlsConfig)
+	} else {
+		tc := tls.Client(nc, tlsConfig)
+		err = tc.HandshakeContext(tctx)
+		tlsConn = tc
+	}
+	if err != nil {
+		nc.Close()
+		return nil, false, err
+	}
+
+	// The protocol negotiation can be verified only if the connection
+	// exposes the state of TLS.
+	if cs, ok := tlsConn.(interface{ ConnectionState() tls.ConnectionState }); ok {
+		if !cs.ConnectionState().NegotiatedProtocolIsMutual {
+			tlsConn.Close()
+			return nil, false, errors.New("Protocol negotiation failed")
+		}
+	}
+
+	return tlsConn, accepted, nil
+}
+
+// TLSConnectionState returns the state of TLS of the connection. It
+// returns false if the connection is not over TLS or does not expose
+// the state.
+func (conn *Conn) TLSConnectionState() (tls.ConnectionState, bool) {
+	cs, ok := conn.Conn.(interface{ ConnectionState() tls.ConnectionState })
+	if !ok {
+		return tls.ConnectionState{}, false
+	}
+	return cs.ConnectionState(), true
+}
+
+func Accept(c *config.Config, baseConn net.Conn) (*Conn, error) {
+	settings := map[http2.SettingID]uint32{}
 
 	var encoderBuf bytes.Buffer
 	encoder := hpack.NewEncoder(&encoderBuf)
@@ -137,20 +363,28 @@ func Accept(c *config.Config, baseConn net.Conn) (*Conn, error) {
 		Settings: settings,
 		Timeout:  c.Timeout,
 		Verbose:  c.Verbose,
+		Strict:   c.Strict,
 		Closed:   false,
 
 		WindowUpdate: true,
 		WindowSize:   map[uint32]int{0: DefaultWindowSize},
 
-		framer:     framer,
 		encoder:    encoder,
 		encoderBuf: &encoderBuf,
 		decoder:    decoder,
 
-		server: true,
+		initialWindowSize: DefaultWindowSize,
+
+		sendWindows:           map[uint32]int{0: DefaultWindowSize},
+		peerInitialWindowSize: DefaultWindowSize,
+
+		ctx:     context.Background(),
+		traffic: &Traffic{Connections: 1},
+		server:  true,
 	}
+	conn.framer = newFramer(&conn)
 
-	if conn.Verbose {
+	if conn.Verbose || eventLog != nil {
 		conn.debugFramerBuf = new(bytes.Buffer)
 		conn.debugFramer = http2.NewFramer(conn.debugFramerBuf, conn.debugFramerBuf)
 		conn.debugFramer.AllowIllegalWrites = true
@@ -169,6 +403,18 @@ func (conn *Conn) Handshake() error {
 	}
 }
 
+// HandshakeWithSettings performs HTTP/2 handshake with the server
+// including the specified settings in the SETTINGS frame of the
+// client connection preface. The specified settings override the
+// settings of the configuration and the test case.
+func (conn *Conn) HandshakeWithSettings(settings ...http2.Setting) error {
+	if conn.server {
+		return conn.handshakeAsServer()
+	} else {
+		return conn.handshakeAsClient(settings...)
+	}
+}
+
 // MaxFrameSize returns value of Handshake performs HTTP/2 handshake
 // with the server.
 func (conn *Conn) MaxFrameSize() int {
@@ -186,6 +432,13 @@ func (conn *Conn) EncodeHeaders(headers []hpack.HeaderField) []byte {
 	conn.encoderBuf.Reset()
 
 	for _, hf := range headers {
+		if hf.Name == ":path" {
+			path := conn.ExpandPath(hf.Value)
+			if path != hf.Value {
+				conn.logPath(path)
+				hf.Value = path
+			}
+		}
 		conn.encoder.WriteField(hf)
 	}
 
@@ -195,11 +448,179 @@ func (conn *Conn) EncodeHeaders(headers []hpack.HeaderField) []byte {
 	return dst
 }
 
+// ExpandPath returns the path whose placeholders are substituted:
+// "{testid}" with the escaped identifier of the test case using the
+// connection, and "{rand}" with the random value of the connection. The
+// placeholders make the requests unique, so that they are not served
+// from the caches of the server or intermediaries.
+func (conn *Conn) ExpandPath(path string) string {
+	if !strings.Contains(path, "{") {
+		return path
+	}
+
+	if conn.nonce == "" {
+		b := make([]byte, 8)
+		rand.Read(b)
+		conn.nonce = hex.EncodeToString(b)
+	}
+
+	testID := url.PathEscape(conn.testID)
+	return strings.NewReplacer("{testid}", testID, "{rand}", conn.nonce).Replace(path)
+}
+
+// logPath writes a log of the expanded path of the request to be sent.
+// Each distinct path is logged once per connection, since the same
+// request may be encoded many times.
+func (conn *Conn) logPath(path string) {
+	if conn.loggedPaths[path] {
+		return
+	}
+	if conn.loggedPaths == nil {
+		conn.loggedPaths = map[string]bool{}
+	}
+	conn.loggedPaths[path] = true
+
+	logRequestPath(conn.testID, path)
+
+	if conn.Verbose {
+		log.Println(gray(fmt.Sprintf("     [info] Request path: %s", path)))
+	}
+}
+
 // SetMaxDynamicTableSize changes the dynamic header table size to v.
 func (conn *Conn) SetMaxDynamicTableSize(v uint32) {
 	conn.encoder.SetMaxDynamicTableSize(v)
 }
 
+// SetDecoderMaxDynamicTableSize changes the dynamic header table size
+// used to decode the header blocks received from the peer to v.
+func (conn *Conn) SetDecoderMaxDynamicTableSize(v uint32) {
+	conn.decoder.SetAllowedMaxDynamicTableSize(v)
+	conn.decoder.SetMaxDynamicTableSize(v)
+}
+
+// SetDecoderMaxHeaderListSize sets the maximum size of the header list
+// received from the peer to v. A header block exceeding it is treated
+// as a decoding error. The value of 0 disables the limit.
+func (conn *Conn) SetDecoderMaxHeaderListSize(v uint32) {
+	conn.maxHeaderListSize = v
+}
+
+// SetWriteBuffer sets the size of the send buffer of the underlying
+// TCP connection, so that the frames are not buffered on the client
+// side when the server stops reading.
+func (conn *Conn) SetWriteBuffer(bytes int) error {
+	baseConn := conn.Conn
+	if c, ok := baseConn.(interface{ NetConn() net.Conn }); ok {
+		baseConn = c.NetConn()
+	}
+
+	tcpConn, ok := baseConn.(*net.TCPConn)
+	if !ok {
+		return errors.New("Not a TCP connection")
+	}
+
+	return tcpConn.SetWriteBuffer(bytes)
+}
+
+// Close closes the connection and writes the record of the close to
+// the event log, unless the connection has already been closed by
+// either side.
+func (conn *Conn) Close() error {
+	if conn.stop != nil {
+		conn.stop()
+	}
+
+	if !conn.closed && !conn.peerClosed {
+		logRecord(EventRecord{
+			Test:      conn.testID,
+			Event:     "connection_closed",
+			Direction: "send",
+		})
+	}
+	conn.closed = true
+
+	return conn.Conn.Close()
+}
+
+// Read reads data from the connection, counting the bytes received.
+func (conn *Conn) Read(b []byte) (int, error) {
+	n, err := conn.Conn.Read(b)
+	atomic.AddInt64(&conn.traffic.BytesReceived, int64(n))
+	return n, err
+}
+
+// Write writes data to the connection, counting the bytes sent.
+func (conn *Conn) Write(b []byte) (int, error) {
+	n, err := conn.Conn.Write(b)
+	atomic.AddInt64(&conn.traffic.BytesSent, int64(n))
+	return n, err
+}
+
+// readFrame reads the next frame, counting the frames received.
+func (conn *Conn) readFrame() (http2.Frame, error) {
+	f, err := conn.framer.ReadFrame()
+	if err == nil {
+		atomic.AddInt64(&conn.traffic.FramesReceived, 1)
+	}
+	return f, err
+}
+
+// nextFrame returns the first frame received during the handshake if
+// any, or reads the next frame from the connection.
+func (conn *Conn) nextFrame() (http2.Frame, error) {
+	if len(conn.pendingFrames) > 0 {
+		f := conn.pendingFrames[0]
+		conn.pendingFrames = conn.pendingFrames[1:]
+		return f, nil
+	}
+
+	return conn.readFrame()
+}
+
+// copyFrame returns a copy of the frame whose payload does not refer to
+// the read buffer of the framer, so that the frame remains valid after
+// the next frame is read. The padding of the frame is not copied.
+func copyFrame(f http2.Frame) http2.Frame {
+	var buf bytes.Buffer
+	framer := http2.NewFramer(&buf, &buf)
+	framer.AllowIllegalWrites = true
+	framer.AllowIllegalReads = true
+
+	switch f := f.(type) {
+	case *http2.DataFrame:
+		framer.WriteData(f.StreamID, f.StreamEnded(), f.Data())
+	case *http2.HeadersFrame:
+		framer.WriteHeaders(http2.HeadersFrameParam{
+			StreamID:      f.StreamID,
+			BlockFragment: f.HeaderBlockFragment(),
+			EndStream:     f.StreamEnded(),
+			EndHeaders:    f.HeadersEnded(),
+			Priority:      f.Priority,
+		})
+	case *http2.PushPromiseFrame:
+		framer.WritePushPromise(http2.PushPromiseParam{
+			StreamID:      f.StreamID,
+			PromiseID:     f.PromiseID,
+			BlockFragment: f.HeaderBlockFragment(),
+			EndHeaders:    f.HeadersEnded(),
+		})
+	case *http2.ContinuationFrame:
+		framer.WriteContinuation(f.StreamID, f.HeadersEnded(), f.HeaderBlockFragment())
+	case *http2.UnknownFrame:
+		framer.WriteRawFrame(f.Type, f.Flags, f.StreamID, f.Payload())
+	default:
+		// The other frames do not refer to the read buffer.
+		return f
+	}
+
+	copied, err := framer.ReadFrame()
+	if err != nil {
+		return f
+	}
+	return copied
+}
+
 // Send sends a byte sequense. This function is used to send a raw
 // data in tests.
 func (conn *Conn) Send(payload []byte) error {
@@ -210,27 +631,35 @@ func (conn *Conn) Send(payload []byte) error {
 
 // WriteData sends a DATA frame.
 func (conn *Conn) WriteData(streamID uint32, endStream bool, data []byte) error {
-	if conn.Verbose {
+	if conn.debugFramer != nil {
 		conn.debugFramer.WriteData(streamID, endStream, data)
 		conn.logFrameSend()
 	}
 
+	conn.consumeSendWindow(streamID, len(data))
 	return conn.framer.WriteData(streamID, endStream, data)
 }
 
 // WriteDataPadded sends a DATA frame with padding.
 func (conn *Conn) WriteDataPadded(streamID uint32, endStream bool, data, pad []byte) error {
-	if conn.Verbose {
+	if conn.debugFramer != nil {
 		conn.debugFramer.WriteDataPadded(streamID, endStream, data, pad)
 		conn.logFrameSend()
 	}
 
+	// The pad length field and the padding are flow controlled as well.
+	n := len(data)
+	if pad != nil {
+		n += 1 + len(pad)
+	}
+	conn.consumeSendWindow(streamID, n)
+
 	return conn.framer.WriteDataPadded(streamID, endStream, data, pad)
 }
 
 // WriteHeaders sends a HEADERS frame.
 func (conn *Conn) WriteHeaders(p http2.HeadersFrameParam) error {
-	if conn.Verbose {
+	if conn.debugFramer != nil {
 		conn.debugFramer.WriteHeaders(p)
 		conn.logFrameSend()
 	}
@@ -240,7 +669,7 @@ func (conn *Conn) WriteHeaders(p http2.HeadersFrameParam) error {
 
 // WritePriority sends a PRIORITY frame.
 func (conn *Conn) WritePriority(streamID uint32, p http2.PriorityParam) error {
-	if conn.Verbose {
+	if conn.debugFramer != nil {
 		conn.debugFramer.WritePriority(streamID, p)
 		conn.logFrameSend()
 	}
@@ -250,7 +679,7 @@ func (conn *Conn) WritePriority(streamID uint32, p http2.PriorityParam) error {
 
 // WriteRSTStream sends a RST_STREAM frame.
 func (conn *Conn) WriteRSTStream(streamID uint32, code http2.ErrCode) error {
-	if conn.Verbose {
+	if conn.debugFramer != nil {
 		conn.debugFramer.WriteRSTStream(streamID, code)
 		conn.logFrameSend()
 	}
@@ -260,7 +689,7 @@ func (conn *Conn) WriteRSTStream(streamID uint32, code http2.ErrCode) error {
 
 // WriteSettings sends a SETTINGS frame.
 func (conn *Conn) WriteSettings(settings ...http2.Setting) error {
-	if conn.Verbose {
+	if conn.debugFramer != nil {
 		conn.debugFramer.WriteSettings(settings...)
 		conn.logFrameSend()
 	}
@@ -270,7 +699,7 @@ func (conn *Conn) WriteSettings(settings ...http2.Setting) error {
 
 // WriteSettingsAck sends a SETTINGS frame with ACK flag.
 func (conn *Conn) WriteSettingsAck() error {
-	if conn.Verbose {
+	if conn.debugFramer != nil {
 		conn.debugFramer.WriteSettingsAck()
 		conn.logFrameSend()
 	}
@@ -280,7 +709,7 @@ func (conn *Conn) WriteSettingsAck() error {
 
 // WritePushPromise sends a PUSH_PROMISE frame.
 func (conn *Conn) WritePushPromise(p http2.PushPromiseParam) error {
-	if conn.Verbose {
+	if conn.debugFramer != nil {
 		conn.debugFramer.WritePushPromise(p)
 		conn.logFrameSend()
 	}
@@ -290,7 +719,7 @@ func (conn *Conn) WritePushPromise(p http2.PushPromiseParam) error {
 
 // WritePing sends a PING frame.
 func (conn *Conn) WritePing(ack bool, data [8]byte) error {
-	if conn.Verbose {
+	if conn.debugFramer != nil {
 		conn.debugFramer.WritePing(ack, data)
 		conn.logFrameSend()
 	}
@@ -300,7 +729,7 @@ func (conn *Conn) WritePing(ack bool, data [8]byte) error {
 
 // WritePing sends a PING frame.
 func (conn *Conn) WriteGoAway(maxStreamID uint32, code http2.ErrCode, debugData []byte) error {
-	if conn.Verbose {
+	if conn.debugFramer != nil {
 		conn.debugFramer.WriteGoAway(maxStreamID, code, debugData)
 		conn.logFrameSend()
 	}
@@ -310,7 +739,7 @@ func (conn *Conn) WriteGoAway(maxStreamID uint32, code http2.ErrCode, debugData
 
 // WriteWindowUpdate sends a WINDOW_UPDATE frame.
 func (conn *Conn) WriteWindowUpdate(streamID, incr uint32) error {
-	if conn.Verbose {
+	if conn.debugFramer != nil {
 		conn.debugFramer.WriteWindowUpdate(streamID, incr)
 		conn.logFrameSend()
 	}
@@ -320,7 +749,7 @@ func (conn *Conn) WriteWindowUpdate(streamID, incr uint32) error {
 
 // WriteContinuation sends a CONTINUATION frame.
 func (conn *Conn) WriteContinuation(streamID uint32, endHeaders bool, headerBlockFragment []byte) error {
-	if conn.Verbose {
+	if conn.debugFramer != nil {
 		conn.debugFramer.WriteContinuation(streamID, endHeaders, headerBlockFragment)
 		conn.logFrameSend()
 	}
@@ -328,8 +757,47 @@ func (conn *Conn) WriteContinuation(streamID uint32, endHeaders bool, headerBloc
 	return conn.framer.WriteContinuation(streamID, endHeaders, headerBlockFragment)
 }
 
+// WriteHeaderBlock sends the header block in a HEADERS frame followed
+// by CONTINUATION frames as needed to fit the maximum frame size of
+// the server.
+func (conn *Conn) WriteHeaderBlock(streamID uint32, endStream bool, blockFragment []byte) error {
+	max := conn.MaxFrameSize()
+
+	fragment := blockFragment
+	if len(fragment) > max {
+		fragment = fragment[:max]
+	}
+	blockFragment = blockFragment[len(fragment):]
+
+	hp := http2.HeadersFrameParam{
+		StreamID:      streamID,
+		EndStream:     endStream,
+		EndHeaders:    len(blockFragment) == 0,
+		BlockFragment: fragment,
+	}
+	err := conn.WriteHeaders(hp)
+	if err != nil {
+		return err
+	}
+
+	for len(blockFragment) > 0 {
+		fragment = blockFragment
+		if len(fragment) > max {
+			fragment = fragment[:max]
+		}
+		blockFragment = blockFragment[len(fragment):]
+
+		err = conn.WriteContinuation(streamID, len(blockFragment) == 0, fragment)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (conn *Conn) WriteRawFrame(t http2.FrameType, flags http2.Flags, streamID uint32, payload []byte) error {
-	if conn.Verbose {
+	if conn.debugFramer != nil {
 		conn.debugFramer.WriteRawFrame(t, flags, streamID, payload)
 		conn.logFrameSend()
 	}
@@ -351,35 +819,47 @@ func (conn *Conn) WriteSuccessResponse(streamID uint32, c *config.Config) {
 // WaitEvent returns a event occured on connection. This function is
 // used to wait the next event on the connection.
 func (conn *Conn) WaitEvent() Event {
+	ev := conn.WaitEventWithTimeout(conn.Timeout)
+	if ev.Type() == EventTimeout {
+		conn.Closed = true
+	}
+
+	return ev
+}
+
+// WaitEventWithTimeout returns the next event occured on connection
+// within the specified duration. Unlike WaitEvent, the connection is
+// not marked as closed when the duration expires, so that the caller
+// can continue to use it after waiting for the absence of frames.
+func (conn *Conn) WaitEventWithTimeout(d time.Duration) Event {
 	var ev Event
 
-	rd := time.Now().Add(conn.Timeout)
+	rd := time.Now().Add(d)
 	conn.SetReadDeadline(rd)
 
-	f, err := conn.framer.ReadFrame()
+	f, err := conn.nextFrame()
 	if err != nil {
-		if err == io.EOF {
-			ev = ConnectionClosedEvent{}
+		// The connection has been closed by the cancellation of
+		// the run.
+		if conn.ctx.Err() != nil {
+			ev = ErrorEvent{conn.ctx.Err()}
 			conn.vlog(ev, false)
 			conn.Closed = true
 			return ev
 		}
 
-		opErr, ok := err.(*net.OpError)
-		if ok {
-			if opErr.Err == syscall.ECONNRESET {
-				ev = ConnectionClosedEvent{}
-				conn.vlog(ev, false)
-				conn.Closed = true
-				return ev
-			}
+		if isConnectionClosed(err) {
+			ev = ConnectionClosedEvent{}
+			conn.vlog(ev, false)
+			conn.Closed = true
+			conn.peerClosed = true
+			return ev
+		}
 
-			if opErr.Timeout() {
-				ev = TimeoutEvent{}
-				conn.vlog(ev, false)
-				conn.Closed = true
-				return ev
-			}
+		if isTimeout(err) {
+			ev = TimeoutEvent{}
+			conn.vlog(ev, false)
+			return ev
 		}
 
 		ev = ErrorEvent{err}
@@ -391,8 +871,20 @@ func (conn *Conn) WaitEvent() Event {
 	if ok {
 		conn.updateWindowSize(f)
 	}
+	conn.trackSendWindow(f)
+
+	sf, ok := f.(*http2.SettingsFrame)
+	if ok && !sf.IsAck() {
+		conn.recordSettings(sf)
+	}
 
 	ev = getEventByFrame(f)
+	if !conn.server {
+		ev = conn.decodeHeaders(ev)
+	}
+	if goAway, ok := ev.(GoAwayFrameEvent); ok {
+		conn.GoAwayDebugData = goAway.Debug
+	}
 	conn.vlog(ev, false)
 
 	return ev
@@ -476,12 +968,12 @@ func (conn *Conn) updateWindowSize(f http2.Frame) {
 
 	_, ok := conn.WindowSize[streamID]
 	if !ok {
-		conn.WindowSize[streamID] = DefaultWindowSize
+		conn.WindowSize[streamID] = conn.initialWindowSize
 	}
 
 	conn.WindowSize[streamID] -= len
 	if conn.WindowSize[streamID] <= 0 {
-		incr := DefaultWindowSize + (conn.WindowSize[streamID] * -1)
+		incr := conn.initialWindowSize + (conn.WindowSize[streamID] * -1)
 		conn.WriteWindowUpdate(streamID, uint32(incr))
 		conn.WindowSize[streamID] += incr
 	}
@@ -494,6 +986,112 @@ func (conn *Conn) updateWindowSize(f http2.Frame) {
 	}
 }
 
+// SendWindow returns the flow control windows of the stream and the
+// connection available for sending DATA frames, which are based on the
+// DATA frames sent, and the WINDOW_UPDATE frames and
+// SETTINGS_INITIAL_WINDOW_SIZE received.
+func (conn *Conn) SendWindow(streamID uint32) (int, int) {
+	window, ok := conn.sendWindows[streamID]
+	if !ok {
+		window = conn.peerInitialWindowSize
+	}
+	return window, conn.sendWindows[0]
+}
+
+// consumeSendWindow reduces the flow control windows by the flow
+// controlled length of the DATA frame sent on the stream.
+func (conn *Conn) consumeSendWindow(streamID uint32, n int) {
+	window, _ := conn.SendWindow(streamID)
+	conn.sendWindows[streamID] = window - n
+	conn.sendWindows[0] -= n
+}
+
+// trackSendWindow updates the flow control windows with the
+// WINDOW_UPDATE frame or SETTINGS_INITIAL_WINDOW_SIZE received, which
+// changes the windows of all the streams by the difference.
+func (conn *Conn) trackSendWindow(f http2.Frame) {
+	switch f := f.(type) {
+	case *http2.WindowUpdateFrame:
+		window, _ := conn.SendWindow(f.StreamID)
+		conn.sendWindows[f.StreamID] = window + int(f.Increment)
+	case *http2.SettingsFrame:
+		val, ok := f.Value(http2.SettingInitialWindowSize)
+		if f.IsAck() || !ok {
+			return
+		}
+
+		delta := int(val) - conn.peerInitialWindowSize
+		for streamID := range conn.sendWindows {
+			if streamID != 0 {
+				conn.sendWindows[streamID] += delta
+			}
+		}
+		conn.peerInitialWindowSize = int(val)
+	}
+}
+
+// recordSettings appends the settings of the specified SETTINGS frame
+// to the history of the settings received from the peer.
+func (conn *Conn) recordSettings(sf *http2.SettingsFrame) {
+	settings := []http2.Setting{}
+	sf.ForeachSetting(func(setting http2.Setting) error {
+		settings = append(settings, setting)
+		return nil
+	})
+	conn.SettingsHistory = append(conn.SettingsHistory, settings)
+}
+
+// decodeHeaders decodes the header block fragment of HEADERS,
+// PUSH_PROMISE and CONTINUATION frame with the HPACK decoding context
+// of the connection. The decoded header fields are set to the event of
+// the frame that ends the header block.
+func (conn *Conn) decodeHeaders(ev Event) Event {
+	switch event := ev.(type) {
+	case HeadersFrameEvent:
+		event.Fields, event.DecodeError = conn.decodeHeaderBlock(event.HeaderBlockFragment(), event.HeadersEnded())
+		return event
+	case PushPromiseFrameEvent:
+		// The header block of PUSH_PROMISE frame shares the decoding
+		// context, so that it must be decoded to keep the dynamic
+		// table in sync with the peer.
+		event.Fields, event.DecodeError = conn.decodeHeaderBlock(event.HeaderBlockFragment(), event.HeadersEnded())
+		return event
+	case ContinuationFrameEvent:
+		event.Fields, event.DecodeError = conn.decodeHeaderBlock(event.HeaderBlockFragment(), event.HeadersEnded())
+		return event
+	}
+
+	return ev
+}
+
+// decodeHeaderBlock writes the header block fragment to the decoder
+// and returns the header fields when the header block has ended.
+func (conn *Conn) decodeHeaderBlock(fragment []byte, ended bool) ([]hpack.HeaderField, error) {
+	_, err := conn.decoder.Write(fragment)
+	if err == nil && ended {
+		err = conn.decoder.Close()
+	}
+
+	if err == nil && ended && conn.maxHeaderListSize > 0 {
+		var size uint32
+		for _, f := range conn.headerFields {
+			size += f.Size()
+		}
+
+		if size > conn.maxHeaderListSize {
+			err = fmt.Errorf("header list size %d exceeds SETTINGS_MAX_HEADER_LIST_SIZE %d", size, conn.maxHeaderListSize)
+		}
+	}
+
+	if err != nil || ended {
+		fields := conn.headerFields
+		conn.headerFields = nil
+		return fields, err
+	}
+
+	return nil, nil
+}
+
 // logFrameSend writes a log of the frame to be sent.
 func (conn *Conn) logFrameSend() {
 	f, err := conn.debugFramer.ReadFrame()
@@ -510,8 +1108,10 @@ func (conn *Conn) logFrameSend() {
 	conn.vlog(ev, true)
 }
 
-// vlog writes a verbose log.
+// vlog writes a verbose log and the record of the event log.
 func (conn *Conn) vlog(ev Event, send bool) {
+	logEvent(conn.testID, ev, send)
+
 	if !conn.Verbose {
 		return
 	}
@@ -531,7 +1131,7 @@ func getEventByFrame(f http2.Frame) Event {
 	case *http2.DataFrame:
 		ev = DataFrameEvent{*f}
 	case *http2.HeadersFrame:
-		ev = HeadersFrameEvent{*f}
+		ev = HeadersFrameEvent{HeadersFrame: *f}
 	case *http2.PriorityFrame:
 		ev = PriorityFrameEvent{*f}
 	case *http2.RSTStreamFrame:
@@ -539,23 +1139,71 @@ func getEventByFrame(f http2.Frame) Event {
 	case *http2.SettingsFrame:
 		ev = SettingsFrameEvent{*f}
 	case *http2.PushPromiseFrame:
-		ev = PushPromiseFrameEvent{*f}
+		ev = PushPromiseFrameEvent{PushPromiseFrame: *f}
 	case *http2.PingFrame:
 		ev = PingFrameEvent{*f}
 	case *http2.GoAwayFrame:
-		ev = GoAwayFrameEvent{*f}
+		ev = GoAwayFrameEvent{
+			GoAwayFrame: *f,
+			Debug:       append([]byte{}, f.DebugData()...),
+		}
 	case *http2.WindowUpdateFrame:
 		ev = WindowUpdateFrameEvent{*f}
 	case *http2.ContinuationFrame:
-		ev = ContinuationFrameEvent{*f}
-		//default:
-		//	ev = EventUnknownFrame(f)
+		ev = ContinuationFrameEvent{ContinuationFrame: *f}
+	case *http2.UnknownFrame:
+		ev = UnknownFrameEvent{
+			FrameHeader: f.FrameHeader,
+			Payload:     append([]byte{}, f.Payload()...),
+		}
 	}
 
 	return ev
 }
 
-func (conn *Conn) handshakeAsClient() error {
+// defaultClientSettings is the settings sent in the client connection
+// preface unless they are overridden.
+var defaultClientSettings = []http2.Setting{
+	http2.Setting{
+		ID:  http2.SettingInitialWindowSize,
+		Val: DefaultWindowSize,
+	},
+}
+
+// mergeSettings returns the settings replaced with the overriding
+// settings of the same identifier. The other overriding settings are
+// appended.
+func mergeSettings(settings []http2.Setting, overrides ...http2.Setting) []http2.Setting {
+	merged := append([]http2.Setting{}, settings...)
+	for _, o := range overrides {
+		replaced := false
+		for i, s := range merged {
+			if s.ID == o.ID {
+				merged[i] = o
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			merged = append(merged, o)
+		}
+	}
+
+	return merged
+}
+
+// settingsString returns a string representation of the settings, such
+// as "INITIAL_WINDOW_SIZE=65535, ENABLE_PUSH=0".
+func settingsString(settings []http2.Setting) string {
+	list := []string{}
+	for _, s := range settings {
+		list = append(list, fmt.Sprintf("%s=%d", s.ID, s.Val))
+	}
+
+	return strings.Join(list, ", ")
+}
+
+func (conn *Conn) handshakeAsClient(settings ...http2.Setting) error {
 	done := make(chan error)
 
 	fmt.Fprintf(conn, "PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n")
@@ -564,35 +1212,58 @@ func (conn *Conn) handshakeAsClient() error {
 		local := false
 		remote := false
 
-		setting := http2.Setting{
-			ID:  http2.SettingInitialWindowSize,
-			Val: DefaultWindowSize,
+		preface := mergeSettings(conn.clientSettings, settings...)
+		for _, setting := range preface {
+			if setting.ID == http2.SettingInitialWindowSize {
+				conn.initialWindowSize = int(setting.Val)
+			}
 		}
-		conn.WriteSettings(setting)
+		if conn.Verbose {
+			log.Println(gray(fmt.Sprintf("     [info] Client settings: %s", settingsString(preface))))
+		}
+		sent := time.Now()
+		conn.WriteSettings(preface...)
 
 		for !(local && remote) {
-			f, err := conn.framer.ReadFrame()
+			f, err := conn.readFrame()
 			if err != nil {
 				done <- err
 				return
 			}
 
-			ev := getEventByFrame(f)
-			conn.vlog(ev, false)
-
+			// The other frames, such as ORIGIN and ALTSVC frames
+			// sent with the server connection preface, are queued
+			// to be returned by WaitEvent.
 			sf, ok := f.(*http2.SettingsFrame)
 			if !ok {
+				conn.pendingFrames = append(conn.pendingFrames, copyFrame(f))
 				continue
 			}
 
+			ev := getEventByFrame(f)
+			conn.vlog(ev, false)
+			conn.trackSendWindow(f)
+
 			if sf.IsAck() {
 				local = true
+				conn.SettingsAckLatency = time.Since(sent)
+
+				// The peer may use the header table size once it
+				// has acknowledged the settings, so that the
+				// decoder accepts the dynamic table size updates
+				// up to the advertised size.
+				for _, setting := range preface {
+					if setting.ID == http2.SettingHeaderTableSize {
+						conn.SetDecoderMaxDynamicTableSize(setting.Val)
+					}
+				}
 			} else {
 				remote = true
 				sf.ForeachSetting(func(setting http2.Setting) error {
 					conn.Settings[setting.ID] = setting.Val
 					return nil
 				})
+				conn.recordSettings(sf)
 				conn.WriteSettingsAck()
 			}
 		}
@@ -622,7 +1293,7 @@ func (conn *Conn) handshakeAsServer() error {
 			return
 		}
 
-		f, err := conn.framer.ReadFrame()
+		f, err := conn.readFrame()
 		if err != nil {
 			done <- err
 			return
@@ -0,0 +1,750 @@
+package spec
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+
+	"github.com/summerwind/h2spec/config"
+)
+
+// pipeServer is the server side of an in-memory connection, which
+// performs the server connection preface with the specified settings.
+type pipeServer struct {
+	conn     net.Conn
+	settings []http2.Setting
+
+	// preface is the settings received in the client connection
+	// preface.
+	preface chan []http2.Setting
+}
+
+// dialer returns the Dialer that connects to the server.
+func (s *pipeServer) dialer() config.Dialer {
+	return config.DialerFunc(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		client, server := net.Pipe()
+		s.conn = server
+		go s.serve()
+		return client, nil
+	})
+}
+
+func (s *pipeServer) serve() {
+	defer s.conn.Close()
+
+	magic := make([]byte, len(http2.ClientPreface))
+	_, err := io.ReadFull(s.conn, magic)
+	if err != nil || string(magic) != http2.ClientPreface {
+		return
+	}
+
+	framer := http2.NewFramer(s.conn, s.conn)
+
+	// The frames are read in the background since the writes on the
+	// pipe block until the peer reads them. The settings of SETTINGS
+	// frames without ACK flag are passed, since the frame is reused by
+	// the next read.
+	received := make(chan []http2.Setting)
+	go func() {
+		defer close(received)
+		for {
+			f, err := framer.ReadFrame()
+			if err != nil {
+				return
+			}
+
+			sf, ok := f.(*http2.SettingsFrame)
+			if !ok || sf.IsAck() {
+				continue
+			}
+
+			settings := []http2.Setting{}
+			sf.ForeachSetting(func(setting http2.Setting) error {
+				settings = append(settings, setting)
+				return nil
+			})
+			received <- settings
+		}
+	}()
+
+	if s.settings == nil {
+		// Wait until the client gives up.
+		for range received {
+		}
+		return
+	}
+
+	framer.WriteSettings(s.settings...)
+
+	for settings := range received {
+		s.preface <- settings
+		framer.WriteSettingsAck()
+	}
+}
+
+func newPipeServer(settings ...http2.Setting) *pipeServer {
+	return &pipeServer{
+		settings: settings,
+		preface:  make(chan []http2.Setting, 1),
+	}
+}
+
+func TestHandshake(t *testing.T) {
+	server := newPipeServer(http2.Setting{ID: http2.SettingMaxConcurrentStreams, Val: 100})
+
+	c := &config.Config{
+		Host:    "127.0.0.1",
+		Port:    8443,
+		Timeout: time.Second,
+		Dialer:  server.dialer(),
+		ClientSettings: []http2.Setting{
+			{ID: http2.SettingEnablePush, Val: 0},
+			{ID: http2.SettingInitialWindowSize, Val: 1024},
+		},
+	}
+
+	conn, err := Dial(c)
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	defer conn.Close()
+
+	err = conn.HandshakeWithSettings(http2.Setting{ID: http2.SettingHeaderTableSize, Val: 0})
+	if err != nil {
+		t.Fatalf("Handshake() error: %v", err)
+	}
+
+	if v := conn.Settings[http2.SettingMaxConcurrentStreams]; v != 100 {
+		t.Errorf("SETTINGS_MAX_CONCURRENT_STREAMS - expected:100, actual:%d", v)
+	}
+
+	if len(conn.SettingsHistory) != 1 {
+		t.Errorf("settings history - expected:1, actual:%d", len(conn.SettingsHistory))
+	}
+
+	expected := []http2.Setting{
+		{ID: http2.SettingInitialWindowSize, Val: 1024},
+		{ID: http2.SettingEnablePush, Val: 0},
+		{ID: http2.SettingHeaderTableSize, Val: 0},
+	}
+
+	actual := <-server.preface
+	if len(actual) != len(expected) {
+		t.Fatalf("client settings - expected:%v, actual:%v", expected, actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("client settings - expected:%v, actual:%v", expected, actual)
+		}
+	}
+}
+
+func TestHandshakeHeaderTableSize(t *testing.T) {
+	var tableSize uint32 = 65536
+	field := hpack.HeaderField{Name: ":status", Value: "200"}
+
+	c := &config.Config{
+		Host:    "127.0.0.1",
+		Port:    8443,
+		Timeout: time.Second,
+		Dialer: config.DialerFunc(func(ctx context.Context, network, addr string) (net.Conn, error) {
+			client, server := net.Pipe()
+			go func() {
+				defer server.Close()
+
+				magic := make([]byte, len(http2.ClientPreface))
+				io.ReadFull(server, magic)
+
+				framer := http2.NewFramer(server, server)
+				framer.ReadFrame()
+				go io.Copy(ioutil.Discard, server)
+
+				framer.WriteSettings()
+				framer.WriteSettingsAck()
+
+				// The header block starts with the dynamic table
+				// size update to the advertised size.
+				var block bytes.Buffer
+				encoder := hpack.NewEncoder(&block)
+				encoder.SetMaxDynamicTableSizeLimit(tableSize)
+				encoder.SetMaxDynamicTableSize(tableSize)
+				encoder.WriteField(field)
+				framer.WriteHeaders(http2.HeadersFrameParam{
+					StreamID:      1,
+					BlockFragment: block.Bytes(),
+					EndStream:     true,
+					EndHeaders:    true,
+				})
+			}()
+			return client, nil
+		}),
+	}
+
+	conn, err := Dial(c)
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	defer conn.Close()
+
+	err = conn.HandshakeWithSettings(http2.Setting{ID: http2.SettingHeaderTableSize, Val: tableSize})
+	if err != nil {
+		t.Fatalf("Handshake() error: %v", err)
+	}
+
+	ev := conn.WaitEvent()
+	hf, ok := ev.(HeadersFrameEvent)
+	if !ok || hf.DecodeError != nil || len(hf.Fields) != 1 || hf.Fields[0] != field {
+		t.Errorf("HEADERS Frame - expected:[%v], actual:%v", field, ev)
+	}
+}
+
+func TestHandshakeQueuesFrames(t *testing.T) {
+	origins := []string{"https://a.example.com", "https://b.example.com"}
+
+	c := &config.Config{
+		Host:    "127.0.0.1",
+		Port:    8443,
+		Timeout: time.Second,
+		Dialer: config.DialerFunc(func(ctx context.Context, network, addr string) (net.Conn, error) {
+			client, server := net.Pipe()
+			go func() {
+				defer server.Close()
+				io.ReadFull(server, make([]byte, len(http2.ClientPreface)))
+
+				// The writes on the pipe block until the client
+				// reads them, so the SETTINGS frames of the client
+				// are read before writing the next frame.
+				framer := http2.NewFramer(server, server)
+				framer.ReadFrame()
+				for _, origin := range origins {
+					payload := append([]byte{0, byte(len(origin))}, origin...)
+					framer.WriteRawFrame(FrameOrigin, 0, 0, payload)
+				}
+				framer.WriteSettings()
+				framer.ReadFrame()
+				framer.WriteSettingsAck()
+				io.Copy(ioutil.Discard, server)
+			}()
+			return client, nil
+		}),
+	}
+
+	conn, err := Dial(c)
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	defer conn.Close()
+
+	err = conn.Handshake()
+	if err != nil {
+		t.Fatalf("Handshake() error: %v", err)
+	}
+
+	for _, origin := range origins {
+		ev := conn.WaitEvent()
+		uf, ok := ev.(UnknownFrameEvent)
+		if !ok || uf.Header().Type != FrameOrigin {
+			t.Fatalf("WaitEvent() - expected:ORIGIN Frame, actual:%v", ev)
+		}
+		if actual := string(uf.Payload[2:]); actual != origin {
+			t.Errorf("ORIGIN Frame - expected:%s, actual:%s", origin, actual)
+		}
+	}
+}
+
+func TestHandshakeTimeout(t *testing.T) {
+	server := newPipeServer()
+
+	c := &config.Config{
+		Host:    "127.0.0.1",
+		Port:    8443,
+		Timeout: 100 * time.Millisecond,
+		Dialer:  server.dialer(),
+	}
+
+	conn, err := Dial(c)
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	defer conn.Close()
+
+	err = conn.Handshake()
+	if err != ErrTimeout {
+		t.Errorf("Handshake() - expected:%v, actual:%v", ErrTimeout, err)
+	}
+}
+
+func TestDialContextCancel(t *testing.T) {
+	server := newPipeServer()
+
+	c := &config.Config{
+		Host:    "127.0.0.1",
+		Port:    8443,
+		Timeout: 5 * time.Second,
+		Dialer:  server.dialer(),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	conn, err := DialContext(ctx, c)
+	if err != nil {
+		t.Fatalf("DialContext() error: %v", err)
+	}
+	defer conn.Close()
+
+	conn.Send([]byte(http2.ClientPreface))
+	cancel()
+
+	ev := conn.WaitEvent()
+	if _, ok := ev.(ErrorEvent); !ok || !conn.Closed {
+		t.Errorf("WaitEvent() - expected:%v, actual:%v", ErrorEvent{context.Canceled}, ev)
+	}
+}
+
+// earlyDataDialer is the Dialer of pipeServer that writes the early
+// data on the connection in place of TLS handshake.
+type earlyDataDialer struct {
+	config.Dialer
+}
+
+func (d earlyDataDialer) WrapTLSWithEarlyData(ctx context.Context, conn net.Conn, config *tls.Config, earlyData []byte) (net.Conn, bool, error) {
+	_, err := conn.Write(earlyData)
+	if err != nil {
+		return nil, false, err
+	}
+	return conn, true, nil
+}
+
+func TestDialWithEarlyData(t *testing.T) {
+	server := newPipeServer(http2.Setting{ID: http2.SettingMaxConcurrentStreams, Val: 100})
+
+	c := &config.Config{
+		Host:    "127.0.0.1",
+		Port:    8443,
+		Timeout: time.Second,
+		TLS:     true,
+		Dialer:  server.dialer(),
+	}
+
+	_, _, err := DialWithEarlyData(context.Background(), c, []byte(http2.ClientPreface))
+	if err == nil {
+		t.Errorf("DialWithEarlyData() without EarlyDataWrapper - expected error")
+	}
+
+	c.Dialer = earlyDataDialer{server.dialer()}
+
+	var buf bytes.Buffer
+	buf.WriteString(http2.ClientPreface)
+	http2.NewFramer(&buf, nil).WriteSettings(http2.Setting{ID: http2.SettingEnablePush, Val: 0})
+
+	conn, accepted, err := DialWithEarlyData(context.Background(), c, buf.Bytes())
+	if err != nil {
+		t.Fatalf("DialWithEarlyData() error: %v", err)
+	}
+	defer conn.Close()
+
+	if !accepted {
+		t.Errorf("early data accepted - expected:true, actual:false")
+	}
+
+	ev := conn.WaitEvent()
+	if _, ok := ev.(SettingsFrameEvent); !ok {
+		t.Errorf("WaitEvent() - expected:SETTINGS Frame, actual:%v", ev)
+	}
+
+	actual := <-server.preface
+	if len(actual) != 1 || actual[0].ID != http2.SettingEnablePush {
+		t.Errorf("early data settings - expected:[ENABLE_PUSH = 0], actual:%v", actual)
+	}
+}
+
+// frameDialer returns the Dialer whose server runs the function on the
+// framer of the connection, and then reads the frames until the client
+// closes the connection.
+func frameDialer(serve func(framer *http2.Framer)) config.Dialer {
+	return config.DialerFunc(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		client, server := net.Pipe()
+		go func() {
+			defer server.Close()
+			framer := http2.NewFramer(server, server)
+			serve(framer)
+			io.Copy(ioutil.Discard, server)
+		}()
+		return client, nil
+	})
+}
+
+func TestSkipBackground(t *testing.T) {
+	data := [8]byte{'h', '2', 's', 'p', 'e', 'c'}
+	acked := make(chan bool, 1)
+
+	c := &config.Config{
+		Host:    "127.0.0.1",
+		Port:    8443,
+		Timeout: time.Second,
+		Dialer: frameDialer(func(framer *http2.Framer) {
+			framer.WriteWindowUpdate(0, 1024)
+			framer.WritePing(false, data)
+
+			f, err := framer.ReadFrame()
+			ping, ok := f.(*http2.PingFrame)
+			acked <- err == nil && ok && ping.IsAck() && ping.Data == data
+
+			framer.WriteRSTStream(1, http2.ErrCodeProtocol)
+		}),
+	}
+
+	conn, err := Dial(c)
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	defer conn.Close()
+
+	err = VerifyStreamErrorOnStream(conn, 1, http2.ErrCodeProtocol)
+	if err != nil {
+		t.Errorf("VerifyStreamErrorOnStream() error: %v", err)
+	}
+
+	if !<-acked {
+		t.Errorf("PING Frame with ACK flag - expected:true, actual:false")
+	}
+}
+
+func TestKeepBackground(t *testing.T) {
+	c := &config.Config{
+		Host:    "127.0.0.1",
+		Port:    8443,
+		Timeout: 100 * time.Millisecond,
+		Dialer: frameDialer(func(framer *http2.Framer) {
+			framer.WriteWindowUpdate(0, 1024)
+		}),
+	}
+
+	conn, err := Dial(c)
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	defer conn.Close()
+
+	conn.KeepBackground = true
+
+	err = VerifyStreamErrorOnStream(conn, 1, http2.ErrCodeProtocol)
+	testErr, ok := err.(*TestError)
+	if !ok || !strings.HasPrefix(testErr.Actual, "WINDOW_UPDATE Frame") {
+		t.Errorf("VerifyStreamErrorOnStream() - expected:WINDOW_UPDATE Frame, actual:%v", err)
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	conn := &Conn{testID: "http2/6.5/1"}
+
+	path := conn.ExpandPath("/?t={testid}&r={rand}")
+	if !strings.HasPrefix(path, "/?t=http2%2F6.5%2F1&r=") || len(path) != len("/?t=http2%2F6.5%2F1&r=")+16 {
+		t.Errorf("ExpandPath() - expected:/?t=http2%%2F6.5%%2F1&r=<16 hex digits>, actual:%s", path)
+	}
+
+	if again := conn.ExpandPath("/?t={testid}&r={rand}"); again != path {
+		t.Errorf("ExpandPath() on the same connection - expected:%s, actual:%s", path, again)
+	}
+
+	other := (&Conn{testID: "http2/6.5/1"}).ExpandPath("/?t={testid}&r={rand}")
+	if other == path {
+		t.Errorf("ExpandPath() on another connection - expected other than:%s", path)
+	}
+
+	if p := (&Conn{testID: "generic/4/1 GET"}).ExpandPath("/{testid}"); p != "/generic%2F4%2F1%20GET" {
+		t.Errorf("ExpandPath() with \"/\" in the test ID - expected:/generic%%2F4%%2F1%%20GET, actual:%s", p)
+	}
+
+	if p := conn.ExpandPath("/index.html"); p != "/index.html" {
+		t.Errorf("ExpandPath() without placeholders - expected:/index.html, actual:%s", p)
+	}
+}
+
+func TestDecodePushPromise(t *testing.T) {
+	field := hpack.HeaderField{Name: "x-pushed", Value: "yes"}
+
+	c := &config.Config{
+		Host:    "127.0.0.1",
+		Port:    8443,
+		Timeout: time.Second,
+		Dialer: frameDialer(func(framer *http2.Framer) {
+			var block bytes.Buffer
+			encoder := hpack.NewEncoder(&block)
+
+			// The field is added to the dynamic table by PUSH_PROMISE
+			// frame, and referred to by the index in HEADERS frame.
+			encoder.WriteField(field)
+			framer.WritePushPromise(http2.PushPromiseParam{
+				StreamID:      1,
+				PromiseID:     2,
+				BlockFragment: append([]byte{}, block.Bytes()...),
+				EndHeaders:    true,
+			})
+
+			block.Reset()
+			encoder.WriteField(field)
+			framer.WriteHeaders(http2.HeadersFrameParam{
+				StreamID:      1,
+				BlockFragment: block.Bytes(),
+				EndHeaders:    true,
+			})
+		}),
+	}
+
+	conn, err := Dial(c)
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	defer conn.Close()
+
+	ev := conn.WaitEvent()
+	pp, ok := ev.(PushPromiseFrameEvent)
+	if !ok || len(pp.Fields) != 1 || pp.Fields[0] != field {
+		t.Fatalf("PUSH_PROMISE Frame - expected:[%v], actual:%v", field, ev)
+	}
+
+	ev = conn.WaitEvent()
+	hf, ok := ev.(HeadersFrameEvent)
+	if !ok || hf.DecodeError != nil || len(hf.Fields) != 1 || hf.Fields[0] != field {
+		t.Errorf("HEADERS Frame - expected:[%v], actual:%v", field, ev)
+	}
+}
+
+func TestVerifyStreamErrorOrConnectionError(t *testing.T) {
+	tests := []struct {
+		name  string
+		serve func(framer *http2.Framer)
+		check func(err error) bool
+	}{
+		{
+			name: "RST_STREAM of another error code",
+			serve: func(framer *http2.Framer) {
+				framer.WriteRSTStream(1, http2.ErrCodeCancel)
+			},
+			check: func(err error) bool {
+				e, ok := err.(*TestError)
+				return ok && strings.Contains(e.Actual, "CANCEL")
+			},
+		},
+		{
+			name:  "connection close",
+			serve: func(framer *http2.Framer) {},
+			check: func(err error) bool {
+				w, ok := err.(*TestWarning)
+				return ok && w.Reason == ReasonStreamErrorOmitted
+			},
+		},
+	}
+
+	for _, test := range tests {
+		serve := test.serve
+		c := &config.Config{
+			Host:    "127.0.0.1",
+			Port:    8443,
+			Timeout: time.Second,
+			Dialer: config.DialerFunc(func(ctx context.Context, network, addr string) (net.Conn, error) {
+				client, server := net.Pipe()
+				go func() {
+					defer server.Close()
+					serve(http2.NewFramer(server, server))
+				}()
+				return client, nil
+			}),
+		}
+
+		conn, err := Dial(c)
+		if err != nil {
+			t.Fatalf("Dial() error: %v", err)
+		}
+
+		err = VerifyStreamErrorOrConnectionError(conn, http2.ErrCodeProtocol)
+		if !test.check(err) {
+			t.Errorf("%s - unexpected result: %v", test.name, err)
+		}
+		conn.Close()
+	}
+}
+
+func TestVerifyConnectionErrorOfAnotherCode(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		c := &config.Config{
+			Host:    "127.0.0.1",
+			Port:    8443,
+			Timeout: time.Second,
+			Dialer: config.DialerFunc(func(ctx context.Context, network, addr string) (net.Conn, error) {
+				client, server := net.Pipe()
+				go func() {
+					defer server.Close()
+					http2.NewFramer(server, server).WriteGoAway(0, http2.ErrCodeCancel, nil)
+				}()
+				return client, nil
+			}),
+		}
+
+		conn, err := Dial(c)
+		if err != nil {
+			t.Fatalf("Dial() error: %v", err)
+		}
+		conn.Strict = strict
+
+		// The GOAWAY frame followed by the close is reported with
+		// its error code in both modes.
+		err = VerifyConnectionError(conn, http2.ErrCodeProtocol)
+		switch result := err.(type) {
+		case *TestWarning:
+			if strict || !strings.Contains(result.Reason, "CANCEL") {
+				t.Errorf("strict:%v - unexpected result: %v", strict, err)
+			}
+		case *TestError:
+			if !strict || !strings.Contains(result.Actual, "CANCEL") {
+				t.Errorf("strict:%v - unexpected result: %v", strict, err)
+			}
+		default:
+			t.Errorf("strict:%v - unexpected result: %v", strict, err)
+		}
+		conn.Close()
+	}
+}
+
+func TestVerifyConnectionErrorWithoutResponse(t *testing.T) {
+	for _, closed := range []bool{true, false} {
+		closed := closed
+		c := &config.Config{
+			Host:    "127.0.0.1",
+			Port:    8443,
+			Timeout: 100 * time.Millisecond,
+			Dialer: config.DialerFunc(func(ctx context.Context, network, addr string) (net.Conn, error) {
+				client, server := net.Pipe()
+				go func() {
+					http2.NewFramer(server, server).WriteGoAway(0, http2.ErrCodeProtocol, nil)
+					if closed {
+						server.Close()
+					}
+				}()
+				return client, nil
+			}),
+		}
+
+		conn, err := Dial(c)
+		if err != nil {
+			t.Fatalf("Dial() error: %v", err)
+		}
+
+		// The GOAWAY frame passes only if the connection is closed
+		// before the timeout.
+		err = VerifyConnectionErrorWithoutResponse(conn, 1, http2.ErrCodeProtocol)
+		if closed && err != nil {
+			t.Errorf("closed:%v - expected:nil, actual:%v", closed, err)
+		}
+		if _, ok := err.(*TestError); !closed && !ok {
+			t.Errorf("closed:%v - expected:TestError, actual:%v", closed, err)
+		}
+		conn.Close()
+	}
+}
+
+func TestExpectFrameAttributes(t *testing.T) {
+	c := &config.Config{
+		Host:    "127.0.0.1",
+		Port:    8443,
+		Timeout: time.Second,
+		Dialer: frameDialer(func(framer *http2.Framer) {
+			framer.WriteRSTStream(3, http2.ErrCodeCancel)
+		}),
+	}
+
+	conn, err := Dial(c)
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	defer conn.Close()
+
+	err = ExpectFrame(conn, StreamError(http2.ErrCodeProtocol))
+	testErr, ok := err.(*TestError)
+	if !ok {
+		t.Fatalf("ExpectFrame() - expected:TestError, actual:%v", err)
+	}
+
+	expected := []FrameAttributes{
+		GoAwayAttributes(http2.ErrCodeProtocol),
+		RSTStreamAttributes(AnyStreamID, http2.ErrCodeProtocol),
+		ClosedAttributes(),
+	}
+	if len(testErr.ExpectedAttrs) != len(expected) {
+		t.Fatalf("expected attributes - expected:%v, actual:%v", expected, testErr.ExpectedAttrs)
+	}
+	for i := range expected {
+		if testErr.ExpectedAttrs[i] != expected[i] {
+			t.Errorf("expected attributes - expected:%v, actual:%v", expected, testErr.ExpectedAttrs)
+		}
+	}
+
+	actual := FrameAttributes{Type: "RST_STREAM", Flags: "0x00", ErrorCode: "CANCEL", StreamID: "3", State: "open"}
+	if testErr.ActualAttrs == nil || *testErr.ActualAttrs != actual {
+		t.Errorf("actual attributes - expected:%v, actual:%v", actual, testErr.ActualAttrs)
+	}
+}
+
+func TestVerifyStatus(t *testing.T) {
+	tests := []struct {
+		statuses []string
+		expected []string
+		err      bool
+	}{
+		{statuses: []string{"100", "200"}, expected: []string{"2xx"}},
+		{statuses: []string{"204"}, expected: []string{"200", "204"}},
+		{statuses: []string{"200"}},
+		{statuses: []string{"404"}, expected: []string{"2xx"}, err: true},
+		{statuses: []string{"20"}, err: true},
+		{statuses: []string{}, err: true},
+	}
+
+	for _, test := range tests {
+		statuses := test.statuses
+		c := &config.Config{
+			Host:    "127.0.0.1",
+			Port:    8443,
+			Timeout: 100 * time.Millisecond,
+			Dialer: frameDialer(func(framer *http2.Framer) {
+				var block bytes.Buffer
+				encoder := hpack.NewEncoder(&block)
+
+				for i, status := range statuses {
+					block.Reset()
+					encoder.WriteField(hpack.HeaderField{Name: ":status", Value: status})
+					framer.WriteHeaders(http2.HeadersFrameParam{
+						StreamID:      1,
+						BlockFragment: block.Bytes(),
+						EndStream:     i == len(statuses)-1,
+						EndHeaders:    true,
+					})
+				}
+			}),
+		}
+
+		conn, err := Dial(c)
+		if err != nil {
+			t.Fatalf("Dial() error: %v", err)
+		}
+
+		err = VerifyStatus(conn, 1, test.expected...)
+		if test.err {
+			if _, ok := err.(*TestError); !ok {
+				t.Errorf("%v - expected:TestError, actual:%v", statuses, err)
+			}
+		} else if err != nil {
+			t.Errorf("%v - expected:nil, actual:%v", statuses, err)
+		}
+		conn.Close()
+	}
+}
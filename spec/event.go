@@ -5,6 +5,7 @@ import (
 	"math"
 
 	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
 )
 
 var (
@@ -30,6 +31,7 @@ const (
 	EventConnectionClosed  EventType = 0x11
 	EventError             EventType = 0x12
 	EventTimeout           EventType = 0x13
+	EventUnknownFrame      EventType = 0x14
 )
 
 var eventName = map[EventType]string{
@@ -47,6 +49,7 @@ var eventName = map[EventType]string{
 	EventConnectionClosed:  "Connection closed",
 	EventError:             "Error",
 	EventTimeout:           "Timeout",
+	EventUnknownFrame:      "Unknown frame",
 }
 
 func (et EventType) String() string {
@@ -125,6 +128,11 @@ func (ev DataFrameEvent) String() string {
 
 type HeadersFrameEvent struct {
 	http2.HeadersFrame
+
+	// Fields is the decoded header fields, which is set when this
+	// frame ends the header block.
+	Fields      []hpack.HeaderField
+	DecodeError error
 }
 
 func (ev HeadersFrameEvent) Type() EventType {
@@ -156,7 +164,7 @@ func (ev RSTStreamFrameEvent) Type() EventType {
 }
 
 func (ev RSTStreamFrameEvent) String() string {
-	return frameString(ev.Header())
+	return rstStreamString(ev)
 }
 
 type SettingsFrameEvent struct {
@@ -173,6 +181,11 @@ func (ev SettingsFrameEvent) String() string {
 
 type PushPromiseFrameEvent struct {
 	http2.PushPromiseFrame
+
+	// Fields is the decoded header fields of the promised request,
+	// which is set when this frame ends the header block.
+	Fields      []hpack.HeaderField
+	DecodeError error
 }
 
 func (ev PushPromiseFrameEvent) Type() EventType {
@@ -197,6 +210,10 @@ func (ev PingFrameEvent) String() string {
 
 type GoAwayFrameEvent struct {
 	http2.GoAwayFrame
+
+	// Debug is the copy of the additional debug data, which remains
+	// valid after the next frame is read.
+	Debug []byte
 }
 
 func (ev GoAwayFrameEvent) Type() EventType {
@@ -204,7 +221,7 @@ func (ev GoAwayFrameEvent) Type() EventType {
 }
 
 func (ev GoAwayFrameEvent) String() string {
-	return frameString(ev.Header())
+	return goAwayString(ev)
 }
 
 type WindowUpdateFrameEvent struct {
@@ -221,6 +238,11 @@ func (ev WindowUpdateFrameEvent) String() string {
 
 type ContinuationFrameEvent struct {
 	http2.ContinuationFrame
+
+	// Fields is the decoded header fields, which is set when this
+	// frame ends the header block.
+	Fields      []hpack.HeaderField
+	DecodeError error
 }
 
 func (ev ContinuationFrameEvent) Type() EventType {
@@ -231,10 +253,39 @@ func (ev ContinuationFrameEvent) String() string {
 	return frameString(ev.Header())
 }
 
+// UnknownFrameEvent represents a frame of the type that is not defined
+// in RFC 7540, such as the frames of extensions. The payload is copied
+// since the framer reuses its buffer for the next frame.
+type UnknownFrameEvent struct {
+	http2.FrameHeader
+	Payload []byte
+}
+
+func (ev UnknownFrameEvent) Type() EventType {
+	return EventUnknownFrame
+}
+
+func (ev UnknownFrameEvent) String() string {
+	return frameString(ev.Header())
+}
+
+// extensionFrameName is the name of the frame types defined by the
+// extensions, which are not known by the http2 package.
+var extensionFrameName = map[http2.FrameType]string{
+	FrameAltSvc:         "ALTSVC",
+	FrameOrigin:         "ORIGIN",
+	FramePriorityUpdate: "PRIORITY_UPDATE",
+}
+
 func frameString(header http2.FrameHeader) string {
+	name, ok := extensionFrameName[header.Type]
+	if !ok {
+		name = header.Type.String()
+	}
+
 	return fmt.Sprintf(
 		"%s Frame (length:%d, flags:0x%02x, stream_id:%d)",
-		header.Type,
+		name,
 		header.Length,
 		header.Flags,
 		header.StreamID,
@@ -0,0 +1,264 @@
+package spec
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+// eventLog is the destination of the event log of the run, or nil if
+// the event log is disabled.
+var eventLog *EventLog
+
+// EventLog writes the events of the run to a file as JSON lines. Each
+// line is a JSON object of an EventRecord.
+type EventLog struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// EventRecord represents a line of the event log.
+type EventRecord struct {
+	Time      time.Time              `json:"time"`
+	Test      string                 `json:"test,omitempty"`
+	Event     string                 `json:"event"`
+	Direction string                 `json:"direction,omitempty"`
+	Frame     map[string]interface{} `json:"frame,omitempty"`
+	Bytes     string                 `json:"bytes,omitempty"`
+	Verdict   string                 `json:"verdict,omitempty"`
+	Message   string                 `json:"message,omitempty"`
+	Duration  string                 `json:"duration,omitempty"`
+	Attempts  int                    `json:"attempts,omitempty"`
+	DebugData string                 `json:"goaway_debug_data,omitempty"`
+
+	Iterations       int  `json:"iterations,omitempty"`
+	PassedIterations int  `json:"passed_iterations,omitempty"`
+	FailedIterations int  `json:"failed_iterations,omitempty"`
+	Flaky            bool `json:"flaky,omitempty"`
+
+	// Stack is the stack trace of the test case that panicked.
+	Stack string `json:"stack,omitempty"`
+
+	// NotAttempted is the number of the test cases not attempted in
+	// the aborted run.
+	NotAttempted int `json:"not_attempted,omitempty"`
+}
+
+// OpenEventLog creates the file of the specified path and enables the
+// event log of the run.
+func OpenEventLog(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	eventLog = &EventLog{
+		file: f,
+		enc:  json.NewEncoder(f),
+	}
+
+	return nil
+}
+
+// CloseEventLog closes the file of the event log and disables it.
+func CloseEventLog() error {
+	if eventLog == nil {
+		return nil
+	}
+
+	err := eventLog.file.Close()
+	eventLog = nil
+
+	return err
+}
+
+// logRecord writes the specified record to the event log if it is
+// enabled.
+func logRecord(rec EventRecord) {
+	if eventLog == nil {
+		return
+	}
+
+	rec.Time = time.Now()
+
+	eventLog.mu.Lock()
+	defer eventLog.mu.Unlock()
+	eventLog.enc.Encode(rec)
+}
+
+// logTestFinished writes the record of the test result to the event
+// log.
+func logTestFinished(testID string, tr *TestResult) {
+	rec := EventRecord{
+		Test:     testID,
+		Event:    "test_finished",
+		Verdict:  tr.Verdict(),
+		Duration: tr.Duration.String(),
+		Attempts: tr.Attempts,
+
+		Iterations:       tr.Iterations,
+		PassedIterations: tr.PassedIterations,
+		FailedIterations: tr.FailedIterations,
+		Flaky:            tr.Flaky,
+	}
+	if len(tr.GoAwayDebugData) > 0 {
+		rec.DebugData = DebugDataString(tr.GoAwayDebugData)
+	}
+	if tr.Error != nil {
+		rec.Message = tr.Error.Error()
+	}
+	if p, ok := tr.Error.(*TestPanic); ok {
+		rec.Stack = string(p.Stack)
+	}
+
+	logRecord(rec)
+}
+
+// LogRunAborted writes the record of the interrupted run with the
+// reason and the number of the test cases not attempted to the event
+// log.
+func LogRunAborted(summary *RunSummary) {
+	reason := summary.AbortReason
+	if reason == "" {
+		reason = "interrupted"
+	}
+
+	logRecord(EventRecord{
+		Event:        "run_aborted",
+		Message:      reason,
+		NotAttempted: summary.NotAttempted,
+	})
+}
+
+// logRequestPath writes the record of the path of the request whose
+// placeholders are expanded, since the header block is not decoded in
+// the record of the sent frame.
+func logRequestPath(testID, path string) {
+	logRecord(EventRecord{
+		Test:    testID,
+		Event:   "request_path",
+		Message: path,
+	})
+}
+
+// logEvent writes the record of the event sent or received on the
+// connection of the specified test to the event log.
+func logEvent(testID string, ev Event, send bool) {
+	if eventLog == nil {
+		return
+	}
+
+	rec := EventRecord{
+		Test:      testID,
+		Direction: "recv",
+	}
+	if send {
+		rec.Direction = "send"
+	}
+
+	switch event := ev.(type) {
+	case RawDataEvent:
+		rec.Event = "bytes_written"
+		rec.Bytes = hex.EncodeToString(event.Payload)
+	case ConnectionClosedEvent:
+		rec.Event = "connection_closed"
+	case TimeoutEvent:
+		rec.Event = "timeout"
+	case ErrorEvent:
+		rec.Event = "error"
+		rec.Message = event.Error.Error()
+	case EventFrame:
+		rec.Event = "frame_received"
+		if send {
+			rec.Event = "frame_sent"
+		}
+		rec.Frame = frameFields(event)
+	default:
+		rec.Event = "unknown"
+		rec.Message = ev.String()
+	}
+
+	logRecord(rec)
+}
+
+// frameFields returns the decoded fields of the specified frame.
+func frameFields(ev EventFrame) map[string]interface{} {
+	header := ev.Header()
+
+	name, ok := extensionFrameName[header.Type]
+	if !ok {
+		name = header.Type.String()
+	}
+
+	fields := map[string]interface{}{
+		"type":      name,
+		"length":    header.Length,
+		"flags":     header.Flags,
+		"stream_id": header.StreamID,
+	}
+
+	switch event := ev.(type) {
+	case DataFrameEvent:
+		fields["data"] = hex.EncodeToString(event.Data())
+	case HeadersFrameEvent:
+		fields["header_block_fragment"] = hex.EncodeToString(event.HeaderBlockFragment())
+		if event.Fields != nil {
+			fields["header_fields"] = headerFieldList(event.Fields)
+		}
+	case ContinuationFrameEvent:
+		fields["header_block_fragment"] = hex.EncodeToString(event.HeaderBlockFragment())
+		if event.Fields != nil {
+			fields["header_fields"] = headerFieldList(event.Fields)
+		}
+	case PriorityFrameEvent:
+		fields["stream_dependency"] = event.StreamDep
+		fields["exclusive"] = event.Exclusive
+		fields["weight"] = event.Weight
+	case RSTStreamFrameEvent:
+		fields["error_code"] = event.ErrCode.String()
+	case SettingsFrameEvent:
+		settings := map[string]uint32{}
+		event.ForeachSetting(func(s http2.Setting) error {
+			settings[s.ID.String()] = s.Val
+			return nil
+		})
+		fields["settings"] = settings
+	case PushPromiseFrameEvent:
+		fields["promise_id"] = event.PromiseID
+		fields["header_block_fragment"] = hex.EncodeToString(event.HeaderBlockFragment())
+		if event.Fields != nil {
+			fields["header_fields"] = headerFieldList(event.Fields)
+		}
+	case PingFrameEvent:
+		fields["data"] = hex.EncodeToString(event.Data[:])
+	case GoAwayFrameEvent:
+		fields["last_stream_id"] = event.LastStreamID
+		fields["error_code"] = event.ErrCode.String()
+		fields["debug_data"] = ""
+		if len(event.Debug) > 0 {
+			fields["debug_data"] = DebugDataString(event.Debug)
+		}
+	case WindowUpdateFrameEvent:
+		fields["window_size_increment"] = event.Increment
+	case UnknownFrameEvent:
+		fields["payload"] = hex.EncodeToString(event.Payload)
+	}
+
+	return fields
+}
+
+// headerFieldList returns the list of "name: value" of the header
+// fields.
+func headerFieldList(hfs []hpack.HeaderField) []string {
+	list := []string{}
+	for _, hf := range hfs {
+		list = append(list, hf.Name+": "+hf.Value)
+	}
+	return list
+}
@@ -0,0 +1,871 @@
+package spec
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+// maxHistory is the number of the last events kept in the history of
+// the result of ExpectFrame.
+const maxHistory = 10
+
+// Matcher matches the event expected on the connection by ExpectFrame.
+type Matcher interface {
+	// Match returns true if the event is the expected one.
+	Match(ev Event) bool
+
+	// Expected returns the description of the expected events.
+	Expected() []string
+}
+
+// attributesMatcher is implemented by the matcher whose expected events
+// have the attributes, which are compared with the attributes of the
+// actual event in the table of the failure.
+type attributesMatcher interface {
+	expectedAttributes() []FrameAttributes
+}
+
+// resultMatcher is implemented by the matcher that decides the result
+// of the matched event by itself instead of passing.
+type resultMatcher interface {
+	result(conn *Conn, ev Event, history []Event) error
+}
+
+// ExpectFrame reads the events from the connection until the event
+// matched by the matcher is received. The connection close and the
+// timeout end the reading as well, and an error other than them fails
+// immediately. The failure contains the last event as the actual
+// result, or the timeout if no frame is received, and the history of
+// the last events. The frames of the background traffic that are not
+// matched, such as WINDOW_UPDATE frames, are kept in the history but
+// never become the actual result. The reading is cancelled with the
+// context of the connection.
+func ExpectFrame(conn *Conn, m Matcher) error {
+	_, err := ExpectFrameEvent(conn, m)
+	return err
+}
+
+// ExpectFrameEvent is the same as ExpectFrame, but also returns the
+// matched event, or the actual event of the failure, so that the caller
+// can inspect how the expectation ended.
+func ExpectFrameEvent(conn *Conn, m Matcher) (Event, error) {
+	var actual Event
+
+	history := []Event{}
+	for !conn.Closed {
+		ev := conn.WaitEvent()
+
+		if _, ok := ev.(TimeoutEvent); !ok {
+			history = append(history, ev)
+			if len(history) > maxHistory {
+				history = history[1:]
+			}
+		}
+
+		if m.Match(ev) {
+			if r, ok := m.(resultMatcher); ok {
+				return ev, r.result(conn, ev, history)
+			}
+			return ev, nil
+		}
+
+		if conn.skipBackground(ev) {
+			continue
+		}
+
+		switch ev.(type) {
+		case TimeoutEvent:
+			if actual == nil {
+				actual = ev
+			}
+		case ErrorEvent:
+			return ev, expectError(m, ev, history)
+		default:
+			actual = ev
+		}
+	}
+
+	if actual == nil {
+		actual = ConnectionClosedEvent{}
+	}
+
+	return actual, expectError(m, actual, history)
+}
+
+// expectError returns the failure of ExpectFrame. The failure has the
+// attributes of the expected and actual events if the matcher has them.
+func expectError(m Matcher, actual Event, history []Event) error {
+	err := &TestError{
+		Expected: m.Expected(),
+		Actual:   actual.String(),
+	}
+
+	if am, ok := m.(attributesMatcher); ok {
+		if attrs := am.expectedAttributes(); len(attrs) > 0 {
+			err.ExpectedAttrs = attrs
+			err.ActualAttrs = EventAttributes(actual)
+		}
+	}
+
+	for _, ev := range history {
+		err.History = append(err.History, ev.String())
+	}
+
+	return err
+}
+
+// eventMatcher is a Matcher of the function.
+type eventMatcher struct {
+	match    func(ev Event) bool
+	expected []string
+	attrs    []FrameAttributes
+}
+
+func (m *eventMatcher) Match(ev Event) bool {
+	return m.match(ev)
+}
+
+func (m *eventMatcher) Expected() []string {
+	return m.expected
+}
+
+func (m *eventMatcher) expectedAttributes() []FrameAttributes {
+	return m.attrs
+}
+
+// SettingsAck returns a Matcher of SETTINGS frame with ACK flag.
+func SettingsAck() Matcher {
+	return &eventMatcher{
+		match: func(ev Event) bool {
+			event, ok := ev.(SettingsFrameEvent)
+			return ok && event.IsAck()
+		},
+		expected: []string{"SETTINGS Frame (length:0, flags:0x01, stream_id:0)"},
+		attrs:    []FrameAttributes{ackAttributes(http2.FrameSettings)},
+	}
+}
+
+// PingAck returns a Matcher of PING frame with ACK flag and the
+// specified opaque data.
+func PingAck(data [8]byte) Matcher {
+	return &eventMatcher{
+		match: func(ev Event) bool {
+			event, ok := ev.(PingFrameEvent)
+			return ok && event.IsAck() && reflect.DeepEqual(event.Data, data)
+		},
+		expected: []string{
+			fmt.Sprintf("PING Frame (length:8, flags:0x01, stream_id:0, opaque_data:%s)", data),
+		},
+		attrs: []FrameAttributes{ackAttributes(http2.FramePing)},
+	}
+}
+
+// Headers returns a Matcher of HEADERS frame on the specified stream.
+func Headers(streamID uint32) Matcher {
+	return &eventMatcher{
+		match: func(ev Event) bool {
+			event, ok := ev.(HeadersFrameEvent)
+			return ok && event.Header().StreamID == streamID
+		},
+		expected: []string{fmt.Sprintf("HEADERS Frame (stream_id:%d)", streamID)},
+		attrs:    []FrameAttributes{streamAttributes(http2.FrameHeaders, streamID)},
+	}
+}
+
+// WindowUpdate returns a Matcher of WINDOW_UPDATE frames on the
+// specified stream, which matches when the sum of their window size
+// increments reaches the specified total.
+func WindowUpdate(streamID uint32, total int) Matcher {
+	received := 0
+
+	return &eventMatcher{
+		match: func(ev Event) bool {
+			event, ok := ev.(WindowUpdateFrameEvent)
+			if !ok || event.Header().StreamID != streamID {
+				return false
+			}
+			received += int(event.Increment)
+			return received >= total
+		},
+		expected: []string{
+			fmt.Sprintf("WINDOW_UPDATE Frame (stream_id:%d, total window_size_increment:%d)", streamID, total),
+		},
+		attrs: []FrameAttributes{streamAttributes(http2.FrameWindowUpdate, streamID)},
+	}
+}
+
+// RSTStream returns a Matcher of RST_STREAM frame on the specified
+// stream with one of the error codes. AnyStreamID matches any stream.
+func RSTStream(streamID uint32, codes ...http2.ErrCode) Matcher {
+	expected := []string{}
+	attrs := []FrameAttributes{}
+	for _, code := range codes {
+		expected = append(expected, expectedRSTStreamFrame(streamID, code))
+		attrs = append(attrs, RSTStreamAttributes(streamID, code))
+	}
+
+	return &eventMatcher{
+		match: func(ev Event) bool {
+			event, ok := ev.(RSTStreamFrameEvent)
+			if !ok || !matchesStream(streamID, event.Header().StreamID) {
+				return false
+			}
+			return VerifyErrorCode(codes, event.ErrCode)
+		},
+		expected: expected,
+		attrs:    attrs,
+	}
+}
+
+// GoAway returns a Matcher of GOAWAY frame with one of the error codes.
+func GoAway(codes ...http2.ErrCode) Matcher {
+	expected := []string{}
+	attrs := []FrameAttributes{}
+	for _, code := range codes {
+		expected = append(expected, fmt.Sprintf(ExpectedGoAwayFrame, code))
+		attrs = append(attrs, GoAwayAttributes(code))
+	}
+
+	return &eventMatcher{
+		match: func(ev Event) bool {
+			event, ok := ev.(GoAwayFrameEvent)
+			return ok && VerifyErrorCode(codes, event.ErrCode)
+		},
+		expected: expected,
+		attrs:    attrs,
+	}
+}
+
+// ConnectionClosed returns a Matcher of the connection close.
+func ConnectionClosed() Matcher {
+	return &eventMatcher{
+		match: func(ev Event) bool {
+			_, ok := ev.(ConnectionClosedEvent)
+			return ok
+		},
+		expected: []string{ExpectedConnectionClosed},
+		attrs:    []FrameAttributes{ClosedAttributes()},
+	}
+}
+
+// StreamError returns a Matcher of the stream error with one of the
+// error codes. The connection error with the same error code and the
+// connection close are also matched as VerifyStreamError.
+func StreamError(codes ...http2.ErrCode) Matcher {
+	expected := []string{}
+	attrs := []FrameAttributes{}
+	for _, code := range codes {
+		expected = append(expected, fmt.Sprintf(ExpectedGoAwayFrame, code))
+		expected = append(expected, fmt.Sprintf(ExpectedRSTStreamFrame, code))
+		attrs = append(attrs, GoAwayAttributes(code), RSTStreamAttributes(AnyStreamID, code))
+	}
+	expected = append(expected, ExpectedConnectionClosed)
+	attrs = append(attrs, ClosedAttributes())
+
+	m := AnyOf(GoAway(codes...), RSTStream(AnyStreamID, codes...), ConnectionClosed()).(*anyMatcher)
+	m.expected = expected
+	m.attrs = attrs
+
+	return m
+}
+
+// connectionErrorMatcher is a Matcher of the connection error.
+type connectionErrorMatcher struct {
+	codes  []http2.ErrCode
+	goAway Matcher
+}
+
+// ConnectionError returns a Matcher of the connection error with one of
+// the error codes. The connection close is also matched, which is
+// treated as VerifyConnectionError does.
+func ConnectionError(codes ...http2.ErrCode) Matcher {
+	return &connectionErrorMatcher{
+		codes:  codes,
+		goAway: GoAway(codes...),
+	}
+}
+
+func (m *connectionErrorMatcher) Match(ev Event) bool {
+	_, closed := ev.(ConnectionClosedEvent)
+	return closed || m.goAway.Match(ev)
+}
+
+func (m *connectionErrorMatcher) Expected() []string {
+	return append(m.goAway.Expected(), ExpectedConnectionClosed)
+}
+
+func (m *connectionErrorMatcher) expectedAttributes() []FrameAttributes {
+	attrs := []FrameAttributes{}
+	for _, code := range m.codes {
+		attrs = append(attrs, GoAwayAttributes(code))
+	}
+	return append(attrs, ClosedAttributes())
+}
+
+// result returns the result of the connection close without GOAWAY
+// frame of the error codes.
+func (m *connectionErrorMatcher) result(conn *Conn, ev Event, history []Event) error {
+	if _, ok := ev.(ConnectionClosedEvent); !ok {
+		return nil
+	}
+
+	var goAway Event
+	for _, h := range history {
+		if _, ok := h.(GoAwayFrameEvent); ok {
+			goAway = h
+		}
+	}
+
+	return verifyGoAwayOmitted(conn, goAway, m.codes)
+}
+
+// anyMatcher is a Matcher of any of the matchers.
+type anyMatcher struct {
+	matchers []Matcher
+	matched  Matcher
+	expected []string
+	attrs    []FrameAttributes
+}
+
+// AnyOf returns a Matcher that matches the event matched by any of the
+// matchers. The matcher has the attributes of the expected events only
+// if all of the matchers have them.
+func AnyOf(matchers ...Matcher) Matcher {
+	expected := []string{}
+	attrs := []FrameAttributes{}
+	for _, m := range matchers {
+		expected = append(expected, m.Expected()...)
+
+		am, ok := m.(attributesMatcher)
+		if !ok || attrs == nil || len(am.expectedAttributes()) == 0 {
+			attrs = nil
+			continue
+		}
+		attrs = append(attrs, am.expectedAttributes()...)
+	}
+
+	return &anyMatcher{
+		matchers: matchers,
+		expected: expected,
+		attrs:    attrs,
+	}
+}
+
+func (m *anyMatcher) Match(ev Event) bool {
+	for _, matcher := range m.matchers {
+		if matcher.Match(ev) {
+			m.matched = matcher
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *anyMatcher) Expected() []string {
+	return m.expected
+}
+
+func (m *anyMatcher) expectedAttributes() []FrameAttributes {
+	return m.attrs
+}
+
+func (m *anyMatcher) result(conn *Conn, ev Event, history []Event) error {
+	if r, ok := m.matched.(resultMatcher); ok {
+		return r.result(conn, ev, history)
+	}
+
+	return nil
+}
+
+// repeatedMatcher is a Matcher of the number of events.
+type repeatedMatcher struct {
+	count   int
+	matched int
+	matcher Matcher
+}
+
+// Repeated returns a Matcher that matches when the events matched by
+// the matcher have been received the specified number of times.
+func Repeated(count int, matcher Matcher) Matcher {
+	return &repeatedMatcher{
+		count:   count,
+		matcher: matcher,
+	}
+}
+
+func (m *repeatedMatcher) Match(ev Event) bool {
+	if m.matcher.Match(ev) {
+		m.matched++
+	}
+
+	return m.matched == m.count
+}
+
+func (m *repeatedMatcher) expectedAttributes() []FrameAttributes {
+	if am, ok := m.matcher.(attributesMatcher); ok {
+		return am.expectedAttributes()
+	}
+	return nil
+}
+
+func (m *repeatedMatcher) Expected() []string {
+	expected := []string{}
+	for _, e := range m.matcher.Expected() {
+		expected = append(expected, fmt.Sprintf("%d %s", m.count, e))
+	}
+
+	return expected
+}
+
+// responseMatcher is a Matcher of the response on a stream, which
+// matches when the response has ended or has been stopped by RST_STREAM
+// or GOAWAY frame, or when done returns true if it is specified. The
+// result of the matched response is decided by verify. If check is
+// specified, it is called with each event before the event is accounted
+// to the response, and its failure ends the matching as the result.
+type responseMatcher struct {
+	res      *Response
+	expected []string
+	done     func(res *Response) bool
+	check    func(ev Event) error
+	verify   func(conn *Conn, res *Response) error
+	failure  error
+}
+
+func (m *responseMatcher) Match(ev Event) bool {
+	if m.check != nil {
+		m.failure = m.check(ev)
+		if m.failure != nil {
+			return true
+		}
+	}
+
+	m.res.add(ev)
+	if m.done != nil && m.done(m.res) {
+		return true
+	}
+
+	return m.res.Ended || m.res.stopped
+}
+
+func (m *responseMatcher) Expected() []string {
+	return m.expected
+}
+
+func (m *responseMatcher) result(conn *Conn, ev Event, history []Event) error {
+	if m.failure != nil {
+		return m.failure
+	}
+
+	if m.res.DecodeError != nil {
+		return &TestError{
+			Expected: m.expected,
+			Actual:   fmt.Sprintf("Header block decoding error: %v", m.res.DecodeError),
+		}
+	}
+
+	// The stopped response is reported as the failure of the frame
+	// that stopped it.
+	if !m.res.Ended && m.done == nil {
+		return expectError(m, ev, history)
+	}
+
+	return m.verify(conn, m.res)
+}
+
+// Trailers returns a Matcher of the response on the specified stream
+// that ends with the trailers. The header block of the trailers must
+// carry the END_STREAM flag and contain no pseudo-header field.
+func Trailers(streamID uint32) Matcher {
+	expected := []string{
+		fmt.Sprintf("HEADERS Frame (flags:0x05, stream_id:%d) with trailer fields", streamID),
+	}
+
+	m := &responseMatcher{
+		res:      NewResponse(streamID),
+		expected: expected,
+		done: func(res *Response) bool {
+			return res.Trailers != nil || res.stopped
+		},
+	}
+
+	m.verify = func(conn *Conn, res *Response) error {
+		switch {
+		case res.Trailers == nil && res.Ended:
+			return &TestError{
+				Expected: expected,
+				Actual:   fmt.Sprintf("Response (stream_id:%d) ended without trailers", streamID),
+			}
+		case res.Trailers == nil:
+			return &TestError{
+				Expected: expected,
+				Actual:   res.actual.String(),
+			}
+		case !res.Ended:
+			return &TestError{
+				Expected: expected,
+				Actual:   fmt.Sprintf("Trailers (stream_id:%d) without END_STREAM flag", streamID),
+			}
+		}
+
+		for _, hf := range res.Trailers {
+			if strings.HasPrefix(hf.Name, ":") {
+				return &TestError{
+					Expected: expected,
+					Actual:   fmt.Sprintf("Trailers (stream_id:%d) with %s pseudo-header field", streamID, hf.Name),
+				}
+			}
+		}
+
+		return nil
+	}
+
+	return m
+}
+
+// HeadResponse returns a Matcher of the response to HEAD request on the
+// specified stream, which must end with the HEADERS frame or with an
+// empty DATA frame. A DATA frame with payload on the stream fails.
+func HeadResponse(streamID uint32) Matcher {
+	expected := []string{
+		fmt.Sprintf("HEADERS Frame (flags:0x05, stream_id:%d)", streamID),
+		fmt.Sprintf("DATA Frame (length:0, flags:0x01, stream_id:%d)", streamID),
+	}
+
+	return &responseMatcher{
+		res:      NewResponse(streamID),
+		expected: expected,
+		check: func(ev Event) error {
+			df, ok := ev.(DataFrameEvent)
+			if ok && df.Header().StreamID == streamID && len(df.Data()) > 0 {
+				return &TestError{
+					Expected: expected,
+					Actual:   ev.String(),
+				}
+			}
+			return nil
+		},
+		verify: func(conn *Conn, res *Response) error {
+			if res.Headers == nil {
+				return &TestError{
+					Expected: expected,
+					Actual:   res.actual.String(),
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// ResponseContentLength returns a Matcher of the response on the
+// specified stream whose payload, excluding the padding, matches the
+// content-length header field of the response. No DATA frame may follow
+// the END_STREAM flag until the PING frame sent after the response is
+// acknowledged. TestSkipped is the result if the response does not have
+// the content-length.
+func ResponseContentLength(streamID uint32) Matcher {
+	return &responseMatcher{
+		res: NewResponse(streamID),
+		expected: []string{
+			fmt.Sprintf("HEADERS Frame (stream_id:%d, :status:xxx)", streamID),
+			fmt.Sprintf("DATA Frame (flags:0x01, stream_id:%d)", streamID),
+		},
+		verify: verifyContentLength,
+	}
+}
+
+// verifyContentLength verifies the content-length of the ended response
+// as described in ResponseContentLength.
+func verifyContentLength(conn *Conn, res *Response) error {
+	streamID := res.StreamID
+
+	if res.Headers == nil {
+		return &TestError{
+			Expected: []string{fmt.Sprintf("HEADERS Frame (stream_id:%d, :status:xxx)", streamID)},
+			Actual:   res.actual.String(),
+		}
+	}
+
+	value, ok := headerFieldValue(res.Headers, "content-length")
+	if !ok {
+		return &TestSkipped{Reason: "response has no content-length header field"}
+	}
+
+	length, err := strconv.Atoi(value)
+	if err != nil || length < 0 {
+		return &TestError{
+			Expected: []string{fmt.Sprintf("HEADERS Frame (stream_id:%d) with valid content-length", streamID)},
+			Actual:   fmt.Sprintf("HEADERS Frame (stream_id:%d, content-length:%s)", streamID, value),
+		}
+	}
+
+	if len(res.Body) != length {
+		return &TestError{
+			Expected: []string{
+				fmt.Sprintf("DATA Frames (stream_id:%d) of %d octets ending with END_STREAM flag", streamID, length),
+			},
+			Actual: fmt.Sprintf("DATA Frames (stream_id:%d) of %d octets ending with END_STREAM flag", streamID, len(res.Body)),
+		}
+	}
+
+	// The PING frame is acknowledged after the frames the server has
+	// sent so far, so any DATA frame following the END_STREAM flag is
+	// received before the acknowledgement.
+	data := [8]byte{'h', '2', 's', 'p', 'e', 'c'}
+	conn.WritePing(false, data)
+
+	for !conn.Closed {
+		ev := conn.WaitEvent()
+
+		switch event := ev.(type) {
+		case DataFrameEvent:
+			if event.Header().StreamID == streamID {
+				return &TestError{
+					Expected: []string{fmt.Sprintf("No DATA Frame (stream_id:%d) after END_STREAM flag", streamID)},
+					Actual:   ev.String(),
+				}
+			}
+		case PingFrameEvent:
+			if event.IsAck() && event.Data == data {
+				return nil
+			}
+		case TimeoutEvent:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// NoConnectionSpecificResponse returns a Matcher of the response on the
+// specified stream without 101 status code and without the
+// connection-specific header fields in any of its header blocks.
+func NoConnectionSpecificResponse(streamID uint32) Matcher {
+	expected := []string{
+		fmt.Sprintf("HEADERS Frame (stream_id:%d) without connection-specific header fields", streamID),
+	}
+
+	return &responseMatcher{
+		res:      NewResponse(streamID),
+		expected: expected,
+		verify: func(conn *Conn, res *Response) error {
+			blocks := append([][]hpack.HeaderField{}, res.Interim...)
+			blocks = append(blocks, res.Headers, res.Trailers)
+			for _, fields := range blocks {
+				if isSwitchingProtocolsStatus(fields) {
+					return &TestError{
+						Expected: expected,
+						Actual:   ResponseResult{StreamID: streamID, Status: "101"}.String(),
+					}
+				}
+
+				for _, hf := range fields {
+					for _, name := range connectionSpecificFields {
+						if hf.Name == name {
+							return &TestError{
+								Expected: expected,
+								Actual:   fmt.Sprintf("HEADERS Frame (stream_id:%d) with \"%s: %s\"", streamID, hf.Name, hf.Value),
+							}
+						}
+					}
+				}
+			}
+
+			if res.Headers == nil {
+				return &TestError{
+					Expected: expected,
+					Actual:   res.actual.String(),
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+// Status returns a Matcher of the final response on the specified
+// stream whose status code matches one of the specified ones. The
+// status codes can be patterns as described in ResponseResult, and any
+// status code matches if none is specified. The informational responses
+// are skipped, and the matching ends with the header block of the final
+// response.
+func Status(streamID uint32, statuses ...string) Matcher {
+	if len(statuses) == 0 {
+		statuses = []string{"xxx"}
+	}
+
+	expected := []string{}
+	for _, s := range statuses {
+		expected = append(expected, ResponseResult{StreamID: streamID, Status: s}.String())
+	}
+
+	return &responseMatcher{
+		res:      NewResponse(streamID),
+		expected: expected,
+		done: func(res *Response) bool {
+			return res.Headers != nil || res.stopped
+		},
+		verify: func(conn *Conn, res *Response) error {
+			if res.Headers == nil {
+				return &TestError{
+					Expected: expected,
+					Actual:   res.actual.String(),
+				}
+			}
+
+			actual := ResponseResult{StreamID: streamID, Status: res.Status()}
+			if _, err := strconv.Atoi(actual.Status); err != nil {
+				if actual.Status == "" {
+					actual.Status = "(none)"
+				}
+
+				return &TestError{
+					Expected: expected,
+					Actual:   actual.String(),
+				}
+			}
+
+			for _, s := range statuses {
+				if (ResponseResult{StreamID: streamID, Status: s}).Matches(actual) {
+					return nil
+				}
+			}
+
+			return &TestError{
+				Expected: expected,
+				Actual:   actual.String(),
+			}
+		},
+	}
+}
+
+// multiplexedMatcher is a Matcher of the responses on the streams.
+type multiplexedMatcher struct {
+	streamIDs []uint32
+	responses map[uint32]*Response
+	failure   error
+}
+
+// MultiplexedResponses returns a Matcher of a complete response on each
+// of the specified streams. No frame may be received on a stream after
+// its END_STREAM flag or on a stream that has not been opened, and the
+// header block of each response must contain exactly one :status
+// pseudo-header field.
+func MultiplexedResponses(streamIDs ...uint32) Matcher {
+	m := &multiplexedMatcher{
+		streamIDs: streamIDs,
+		responses: map[uint32]*Response{},
+	}
+	for _, streamID := range streamIDs {
+		m.responses[streamID] = NewResponse(streamID)
+	}
+
+	return m
+}
+
+func (m *multiplexedMatcher) Match(ev Event) bool {
+	m.failure = m.check(ev)
+	if m.failure != nil {
+		return true
+	}
+
+	for _, res := range m.responses {
+		res.add(ev)
+	}
+
+	for _, res := range m.responses {
+		if !res.Ended {
+			return false
+		}
+	}
+	return true
+}
+
+// check returns the failure of the frame on a stream that has not been
+// opened or has already ended, or of RST_STREAM frame on the streams.
+func (m *multiplexedMatcher) check(ev Event) error {
+	switch event := ev.(type) {
+	case HeadersFrameEvent, ContinuationFrameEvent, DataFrameEvent:
+		streamID := event.(EventFrame).Header().StreamID
+		res, ok := m.responses[streamID]
+		if !ok && streamID%2 == 1 {
+			return &TestError{
+				Expected: []string{"Frames only on the streams opened by the client"},
+				Actual:   ev.String(),
+			}
+		}
+		if ok && res.Ended {
+			return &TestError{
+				Expected: []string{fmt.Sprintf("No frame on stream %d after END_STREAM flag", streamID)},
+				Actual:   ev.String(),
+			}
+		}
+	case RSTStreamFrameEvent:
+		if _, ok := m.responses[event.Header().StreamID]; ok {
+			return &TestError{
+				Expected: []string{
+					fmt.Sprintf("DATA Frame (flags:0x01, stream_id:%d)", event.Header().StreamID),
+				},
+				Actual: ev.String(),
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *multiplexedMatcher) Expected() []string {
+	expected := []string{}
+	for _, streamID := range m.streamIDs {
+		if !m.responses[streamID].Ended {
+			expected = append(expected,
+				fmt.Sprintf("HEADERS Frame (stream_id:%d, :status:xxx)", streamID),
+				fmt.Sprintf("DATA Frame (flags:0x01, stream_id:%d)", streamID),
+			)
+		}
+	}
+
+	return expected
+}
+
+func (m *multiplexedMatcher) result(conn *Conn, ev Event, history []Event) error {
+	if m.failure != nil {
+		return m.failure
+	}
+
+	for _, streamID := range m.streamIDs {
+		res := m.responses[streamID]
+
+		if res.DecodeError != nil {
+			return &TestError{
+				Expected: []string{
+					fmt.Sprintf("HEADERS Frame (stream_id:%d) decoded successfully", streamID),
+				},
+				Actual: res.DecodeError.Error(),
+			}
+		}
+
+		statuses := 0
+		for _, hf := range res.Headers {
+			if hf.Name == ":status" {
+				statuses++
+			}
+		}
+		if statuses != 1 {
+			return &TestError{
+				Expected: []string{
+					fmt.Sprintf("HEADERS Frame (stream_id:%d) with exactly one :status", streamID),
+				},
+				Actual: fmt.Sprintf("HEADERS Frame (stream_id:%d) with %d :status", streamID, statuses),
+			}
+		}
+	}
+
+	return nil
+}
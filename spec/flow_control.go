@@ -0,0 +1,96 @@
+package spec
+
+import (
+	"fmt"
+	"time"
+)
+
+// DataCounter accounts the flow-controlled length of DATA frames
+// received on the connection. The length of a DATA frame includes
+// the Pad Length field and padding as described in RFC 7540
+// Section 6.9.1.
+type DataCounter struct {
+	Total   int
+	Streams map[uint32]int
+	Ended   map[uint32]bool
+}
+
+// NewDataCounter returns an empty DataCounter.
+func NewDataCounter() *DataCounter {
+	return &DataCounter{
+		Streams: map[uint32]int{},
+		Ended:   map[uint32]bool{},
+	}
+}
+
+// Add accounts the length of the specified DATA frame.
+func (dc *DataCounter) Add(ev DataFrameEvent) {
+	len := int(ev.Header().Length)
+	streamID := ev.Header().StreamID
+
+	dc.Total += len
+	dc.Streams[streamID] += len
+	if ev.StreamEnded() {
+		dc.Ended[streamID] = true
+	}
+}
+
+// VerifyWindow verifies whether the total length of DATA frames
+// received on the specified stream does not exceed the window size.
+// The stream identifier 0x0 represents the connection window.
+func (dc *DataCounter) VerifyWindow(streamID uint32, window int) error {
+	target := fmt.Sprintf("stream_id:%d", streamID)
+	received := dc.Streams[streamID]
+	if streamID == 0 {
+		target = "all streams"
+		received = dc.Total
+	}
+
+	if received <= window {
+		return nil
+	}
+
+	return &TestError{
+		Expected: []string{
+			fmt.Sprintf("DATA Frames (%s, total length:<=%d)", target, window),
+		},
+		Actual: fmt.Sprintf("DATA Frames (%s, total length:%d, overshoot:%d bytes)", target, received, received-window),
+	}
+}
+
+// ReadData reads the frames from the connection and accounts the
+// length of DATA frames until all the specified streams are ended or
+// no frame is received within the specified duration. The last event
+// is returned, which is TimeoutEvent if the server stopped sending.
+// RST_STREAM, GOAWAY and the connection close also stop the reading.
+func (dc *DataCounter) ReadData(conn *Conn, d time.Duration, streamIDs ...uint32) Event {
+	var ev Event = TimeoutEvent{}
+
+	for !conn.Closed && !dc.streamsEnded(streamIDs) {
+		ev = conn.WaitEventWithTimeout(d)
+
+		switch event := ev.(type) {
+		case DataFrameEvent:
+			dc.Add(event)
+		case HeadersFrameEvent:
+			if event.StreamEnded() {
+				dc.Ended[event.Header().StreamID] = true
+			}
+		case TimeoutEvent, RSTStreamFrameEvent, GoAwayFrameEvent, ConnectionClosedEvent, ErrorEvent:
+			return ev
+		}
+	}
+
+	return ev
+}
+
+// streamsEnded returns true if all the specified streams are ended.
+func (dc *DataCounter) streamsEnded(streamIDs []uint32) bool {
+	for _, streamID := range streamIDs {
+		if !dc.Ended[streamID] {
+			return false
+		}
+	}
+
+	return len(streamIDs) > 0
+}
@@ -0,0 +1,56 @@
+package spec
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ParseOriginFrame parses the payload of the specified ORIGIN frame
+// described in RFC 8336 Section 2 and returns the origins of the
+// Origin-Entry sequence.
+func ParseOriginFrame(ev UnknownFrameEvent) ([]string, error) {
+	origins := []string{}
+
+	payload := ev.Payload
+	for len(payload) > 0 {
+		if len(payload) < 2 {
+			return nil, errors.New("Origin-Entry is too short to contain Origin-Len")
+		}
+
+		originLen := int(binary.BigEndian.Uint16(payload))
+		payload = payload[2:]
+		if len(payload) < originLen {
+			return nil, errors.New("Origin-Len exceeds the frame payload")
+		}
+
+		origin := string(payload[:originLen])
+		payload = payload[originLen:]
+
+		if !validASCIIOrigin(origin) {
+			return nil, fmt.Errorf("invalid ASCII-Origin: %q", origin)
+		}
+		origins = append(origins, origin)
+	}
+
+	return origins, nil
+}
+
+// validASCIIOrigin returns true if the specified origin is serialized
+// as "scheme://host[:port]" with printable ASCII characters.
+func validASCIIOrigin(origin string) bool {
+	for i := 0; i < len(origin); i++ {
+		if origin[i] <= 0x20 || origin[i] >= 0x7f {
+			return false
+		}
+	}
+
+	i := strings.Index(origin, "://")
+	if i <= 0 {
+		return false
+	}
+
+	host := origin[i+3:]
+	return host != "" && !strings.ContainsAny(host, "/?#")
+}
@@ -0,0 +1,114 @@
+package spec
+
+import (
+	"time"
+)
+
+// Reporter receives the progress of a run. The methods are called in
+// the order of the run, so that the results can be written as the run
+// progresses instead of at the end.
+type Reporter interface {
+	// RunStarted is called before the specs are run.
+	RunStarted(groups []*TestGroup)
+
+	// GroupStarted is called before the tests of the group are run.
+	GroupStarted(tg *TestGroup)
+
+	// TestStarted is called before the test case is run.
+	TestStarted(tc *TestCase, seq int)
+
+	// TestFinished is called with the result of the test case. The
+	// verdict is available as tr.Verdict().
+	TestFinished(tr *TestResult)
+
+	// RunFinished is called with the summary of the run after all
+	// the specs are run or the run is cancelled.
+	RunFinished(summary *RunSummary) error
+}
+
+// RunSummary represents the summary of a run passed to the reporters.
+type RunSummary struct {
+	Groups   []*TestGroup
+	Duration time.Duration
+
+	// Interrupted is true if the run has been cancelled before all
+	// the test cases are run.
+	Interrupted bool
+
+	// AbortReason is the reason the run has been interrupted by
+	// h2spec itself, such as "run timeout", or empty otherwise.
+	AbortReason string
+
+	// NotAttempted is the number of the selected test cases that have
+	// not been run because the run has been interrupted.
+	NotAttempted int
+
+	// Address is the address of the server chosen by the capability
+	// detection, or empty if the detection failed.
+	Address string
+}
+
+// Total returns the number of test cases that have a result.
+func (s *RunSummary) Total() int {
+	total := 0
+	for _, tg := range s.Groups {
+		total += tg.FailedCount
+		total += tg.InconclusiveCount
+		total += tg.SkippedCount
+		total += tg.PassedCount
+		total += tg.ErroredCount
+	}
+
+	return total
+}
+
+// NopReporter is a Reporter that does nothing. It is embedded in a
+// reporter that is interested in only some of the callbacks.
+type NopReporter struct{}
+
+func (NopReporter) RunStarted(groups []*TestGroup)        {}
+func (NopReporter) GroupStarted(tg *TestGroup)            {}
+func (NopReporter) TestStarted(tc *TestCase, seq int)     {}
+func (NopReporter) TestFinished(tr *TestResult)           {}
+func (NopReporter) RunFinished(summary *RunSummary) error { return nil }
+
+// Reporters is a Reporter that calls each of the reporters in order.
+type Reporters []Reporter
+
+func (rs Reporters) RunStarted(groups []*TestGroup) {
+	for _, r := range rs {
+		r.RunStarted(groups)
+	}
+}
+
+func (rs Reporters) GroupStarted(tg *TestGroup) {
+	for _, r := range rs {
+		r.GroupStarted(tg)
+	}
+}
+
+func (rs Reporters) TestStarted(tc *TestCase, seq int) {
+	for _, r := range rs {
+		r.TestStarted(tc, seq)
+	}
+}
+
+func (rs Reporters) TestFinished(tr *TestResult) {
+	for _, r := range rs {
+		r.TestFinished(tr)
+	}
+}
+
+// RunFinished calls RunFinished of all the reporters and returns the
+// first error.
+func (rs Reporters) RunFinished(summary *RunSummary) error {
+	var first error
+	for _, r := range rs {
+		err := r.RunFinished(summary)
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
@@ -0,0 +1,77 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// recordingReporter records the callbacks in the specified list.
+type recordingReporter struct {
+	name  string
+	calls *[]string
+	err   error
+}
+
+func (r *recordingReporter) record(callback string) {
+	*r.calls = append(*r.calls, fmt.Sprintf("%s.%s", r.name, callback))
+}
+
+func (r *recordingReporter) RunStarted(groups []*TestGroup)    { r.record("RunStarted") }
+func (r *recordingReporter) GroupStarted(tg *TestGroup)        { r.record("GroupStarted") }
+func (r *recordingReporter) TestStarted(tc *TestCase, seq int) { r.record("TestStarted") }
+func (r *recordingReporter) TestFinished(tr *TestResult)       { r.record("TestFinished") }
+
+func (r *recordingReporter) RunFinished(summary *RunSummary) error {
+	r.record("RunFinished")
+	return r.err
+}
+
+func TestReporters(t *testing.T) {
+	calls := []string{}
+	first := errors.New("first")
+
+	rs := Reporters{
+		&recordingReporter{name: "a", calls: &calls},
+		&recordingReporter{name: "b", calls: &calls, err: first},
+		&recordingReporter{name: "c", calls: &calls, err: errors.New("second")},
+	}
+
+	tg := &TestGroup{}
+	tc := &TestCase{}
+	rs.RunStarted([]*TestGroup{tg})
+	rs.GroupStarted(tg)
+	rs.TestStarted(tc, 1)
+	rs.TestFinished(&TestResult{TestCase: tc})
+	err := rs.RunFinished(&RunSummary{})
+
+	if err != first {
+		t.Errorf("RunFinished() - expected:%v, actual:%v", first, err)
+	}
+
+	expected := []string{}
+	for _, callback := range []string{"RunStarted", "GroupStarted", "TestStarted", "TestFinished", "RunFinished"} {
+		for _, name := range []string{"a", "b", "c"} {
+			expected = append(expected, fmt.Sprintf("%s.%s", name, callback))
+		}
+	}
+
+	if strings.Join(calls, " ") != strings.Join(expected, " ") {
+		t.Errorf("callbacks - expected:%v, actual:%v", expected, calls)
+	}
+}
+
+func TestRunSummaryTotal(t *testing.T) {
+	summary := &RunSummary{
+		Groups: []*TestGroup{
+			{PassedCount: 1, FailedCount: 2, SkippedCount: 3},
+			{InconclusiveCount: 4, ErroredCount: 5, WarnedCount: 6},
+		},
+	}
+
+	// The warned test cases are counted as passed.
+	if total := summary.Total(); total != 15 {
+		t.Errorf("Total() - expected:15, actual:%d", total)
+	}
+}
@@ -0,0 +1,46 @@
+package spec
+
+import (
+	"errors"
+	"io"
+	"net"
+)
+
+// isConnectionReset returns true if the error is the reset of the
+// connection by the peer. The error may be wrapped by net.OpError,
+// os.SyscallError or the TLS connection.
+func isConnectionReset(err error) bool {
+	for _, errno := range resetErrnos {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
+
+// isConnectionClosed returns true if the error of a read means that the
+// connection is closed: the end of the stream, the reset by the peer,
+// a read from the connection or the pipe closed locally, or a TLS alert
+// received from the peer.
+func isConnectionClosed(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) || errors.Is(err, io.ErrClosedPipe) {
+		return true
+	}
+
+	if isConnectionReset(err) {
+		return true
+	}
+
+	// crypto/tls reports the fatal alert of the peer, which closes the
+	// connection after it, as "remote error". The close_notify alert is
+	// reported as io.EOF.
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "remote error"
+}
+
+// isTimeout returns true if the error is the expiry of the deadline of
+// the read.
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
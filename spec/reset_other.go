@@ -0,0 +1,12 @@
+//go:build !windows
+
+package spec
+
+import "syscall"
+
+// resetErrnos is the errors of the system call that mean the connection
+// has been reset or aborted.
+var resetErrnos = []syscall.Errno{
+	syscall.ECONNRESET,
+	syscall.ECONNABORTED,
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package spec
+
+import (
+	"syscall"
+	"testing"
+)
+
+func TestIsConnectionResetErrno(t *testing.T) {
+	if !isConnectionReset(readError(syscall.ECONNRESET)) {
+		t.Errorf("ECONNRESET - expected:true, actual:false")
+	}
+
+	if isConnectionReset(readError(syscall.ECONNREFUSED)) {
+		t.Errorf("ECONNREFUSED - expected:false, actual:true")
+	}
+}
@@ -0,0 +1,75 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"testing"
+)
+
+// readError returns the error of a read of the socket that failed with
+// the specified error.
+func readError(err error) error {
+	return &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", err)}
+}
+
+// timeoutError is a net.Error of the expiry of the deadline.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsConnectionClosed(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		closed bool
+	}{
+		{name: "EOF", err: io.EOF, closed: true},
+		{name: "wrapped EOF", err: fmt.Errorf("tls: %w", io.EOF), closed: true},
+		{name: "closed network connection", err: &net.OpError{Op: "read", Net: "tcp", Err: net.ErrClosed}, closed: true},
+		{name: "closed pipe", err: io.ErrClosedPipe, closed: true},
+		{name: "TLS alert", err: &net.OpError{Op: "remote error", Err: errors.New("tls: internal error")}, closed: true},
+		{name: "unexpected EOF", err: io.ErrUnexpectedEOF, closed: false},
+		{name: "timeout", err: &net.OpError{Op: "read", Net: "tcp", Err: timeoutError{}}, closed: false},
+		{name: "frame error", err: errors.New("invalid frame"), closed: false},
+	}
+
+	for _, errno := range resetErrnos {
+		tests = append(tests, struct {
+			name   string
+			err    error
+			closed bool
+		}{name: errno.Error(), err: readError(errno), closed: true})
+	}
+
+	for _, test := range tests {
+		closed := isConnectionClosed(test.err)
+		if closed != test.closed {
+			t.Errorf("%s - expected:%v, actual:%v", test.name, test.closed, closed)
+		}
+	}
+}
+
+func TestIsTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		timeout bool
+	}{
+		{name: "socket", err: &net.OpError{Op: "read", Net: "tcp", Err: timeoutError{}}, timeout: true},
+		{name: "pipe", err: os.ErrDeadlineExceeded, timeout: true},
+		{name: "wrapped", err: fmt.Errorf("tls: %w", os.ErrDeadlineExceeded), timeout: true},
+		{name: "EOF", err: io.EOF, timeout: false},
+	}
+
+	for _, test := range tests {
+		timeout := isTimeout(test.err)
+		if timeout != test.timeout {
+			t.Errorf("%s - expected:%v, actual:%v", test.name, test.timeout, timeout)
+		}
+	}
+}
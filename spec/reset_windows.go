@@ -0,0 +1,12 @@
+//go:build windows
+
+package spec
+
+import "syscall"
+
+// resetErrnos is the errors of Winsock that mean the connection has
+// been reset or aborted.
+var resetErrnos = []syscall.Errno{
+	syscall.WSAECONNRESET,
+	syscall.WSAECONNABORTED,
+}
@@ -0,0 +1,23 @@
+//go:build windows
+
+package spec
+
+import (
+	"syscall"
+	"testing"
+)
+
+func TestIsConnectionResetWSA(t *testing.T) {
+	if !isConnectionReset(readError(syscall.WSAECONNRESET)) {
+		t.Errorf("WSAECONNRESET - expected:true, actual:false")
+	}
+
+	if !isConnectionReset(readError(syscall.WSAECONNABORTED)) {
+		t.Errorf("WSAECONNABORTED - expected:true, actual:false")
+	}
+
+	// WSAECONNREFUSED is not defined by the syscall package.
+	if isConnectionReset(readError(syscall.Errno(10061))) {
+		t.Errorf("WSAECONNREFUSED - expected:false, actual:true")
+	}
+}
@@ -0,0 +1,255 @@
+package spec
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http2/hpack"
+)
+
+// Response represents a response received on a stream. Fields of the
+// header block split into CONTINUATION frames are combined.
+type Response struct {
+	StreamID uint32
+	Interim  [][]hpack.HeaderField
+	Headers  []hpack.HeaderField
+	Trailers []hpack.HeaderField
+	Body     []byte
+	Ended    bool
+
+	// DecodeError is the first error occurred while decoding the
+	// header blocks of the response.
+	DecodeError error
+
+	fields      []hpack.HeaderField
+	streamEnded bool
+	stopped     bool
+	actual      Event
+}
+
+// NewResponse returns an empty Response of the specified stream.
+func NewResponse(streamID uint32) *Response {
+	return &Response{StreamID: streamID}
+}
+
+// Status returns the value of ":status" pseudo-header field.
+func (r *Response) Status() string {
+	status, _ := headerFieldValue(r.Headers, ":status")
+	return status
+}
+
+// EchoedValues returns the values of the specified header field echoed
+// by the echo endpoint. The endpoint echoes a header field as a
+// response header field or as a line of "name: value" in the body.
+func (r *Response) EchoedValues(name string) []string {
+	values := []string{}
+	for _, f := range r.Headers {
+		if f.Name == name {
+			values = append(values, f.Value)
+		}
+	}
+
+	prefix := name + ": "
+	for _, line := range strings.Split(string(r.Body), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(strings.ToLower(line), prefix) {
+			values = append(values, line[len(prefix):])
+		}
+	}
+
+	return values
+}
+
+// ReadHeaders reads the frames from the connection until the next
+// header block of the response is received or no frame is received
+// within the specified duration. The last event is returned, which is
+// TimeoutEvent if the server did not send a header block.
+func (r *Response) ReadHeaders(conn *Conn, d time.Duration) Event {
+	for !conn.Closed && !r.Ended && !r.stopped {
+		ev := conn.WaitEventWithTimeout(d)
+		if _, ok := ev.(TimeoutEvent); ok {
+			return ev
+		}
+
+		if r.add(ev) {
+			break
+		}
+	}
+
+	return r.actual
+}
+
+// ReadAll reads the frames from the connection until the response is
+// ended. The last event is returned, which is used as the actual
+// result when the response did not complete. RST_STREAM on the
+// stream, GOAWAY that does not cover the stream and the connection
+// close also stop the reading.
+func (r *Response) ReadAll(conn *Conn) Event {
+	for !conn.Closed && !r.Ended && !r.stopped {
+		ev := conn.WaitEvent()
+		if _, ok := ev.(TimeoutEvent); ok && r.actual != nil {
+			continue
+		}
+
+		r.add(ev)
+	}
+
+	return r.actual
+}
+
+// ReadResponse reads the frames from the connection and collects the
+// response on the specified stream until the stream is ended. The last
+// event is also returned as described in ReadAll.
+func ReadResponse(conn *Conn, streamID uint32) (*Response, Event) {
+	res := NewResponse(streamID)
+	actual := res.ReadAll(conn)
+	return res, actual
+}
+
+// WriteBody sends the request body of the specified size on the stream
+// of the response, honoring the flow control windows of the peer. While
+// the windows are exhausted, the frames are read and accounted to the
+// response until WINDOW_UPDATE frames make room. It returns the number
+// of octets sent and the event that stopped the sending, or nil if the
+// whole body has been sent. The sending stops if no frame is received
+// within the timeout while the windows are exhausted, or if the
+// deadline passes, so that WINDOW_UPDATE frames trickled within the
+// timeout cannot stall it forever. It also stops if the response is
+// ended or stopped, or the connection is closed.
+func (r *Response) WriteBody(conn *Conn, size int, deadline time.Time) (int, Event) {
+	data := []byte(DummyString(conn.MaxFrameSize()))
+
+	sent := 0
+	for sent < size {
+		if conn.Closed || r.Ended || r.stopped {
+			return sent, r.actual
+		}
+
+		left := time.Until(deadline)
+		if left <= 0 {
+			return sent, TimeoutEvent{}
+		}
+
+		stream, connection := conn.SendWindow(r.StreamID)
+		n := size - sent
+		for _, limit := range []int{stream, connection, len(data)} {
+			if n > limit {
+				n = limit
+			}
+		}
+
+		if n <= 0 {
+			timeout := conn.Timeout
+			if left < timeout {
+				timeout = left
+			}
+
+			ev := conn.WaitEventWithTimeout(timeout)
+			if _, ok := ev.(TimeoutEvent); ok {
+				return sent, ev
+			}
+			r.add(ev)
+			continue
+		}
+
+		err := conn.WriteData(r.StreamID, sent+n == size, data[:n])
+		if err != nil {
+			return sent, ErrorEvent{err}
+		}
+		sent += n
+	}
+
+	return sent, nil
+}
+
+// add accounts the specified event. It returns true if the event ends
+// a header block of the response.
+func (r *Response) add(ev Event) bool {
+	headersEnded := false
+
+	switch event := ev.(type) {
+	case HeadersFrameEvent:
+		if event.Header().StreamID != r.StreamID {
+			return false
+		}
+		r.fields = append([]hpack.HeaderField{}, event.Fields...)
+		r.streamEnded = event.StreamEnded()
+		r.setDecodeError(event.DecodeError)
+		headersEnded = event.HeadersEnded()
+	case ContinuationFrameEvent:
+		if event.Header().StreamID != r.StreamID {
+			return false
+		}
+		r.fields = append(r.fields, event.Fields...)
+		r.setDecodeError(event.DecodeError)
+		headersEnded = event.HeadersEnded()
+	case DataFrameEvent:
+		if event.Header().StreamID != r.StreamID {
+			return false
+		}
+		r.Body = append(r.Body, event.Data()...)
+		r.Ended = event.StreamEnded()
+	case RSTStreamFrameEvent:
+		r.stopped = (event.Header().StreamID == r.StreamID)
+	case GoAwayFrameEvent:
+		r.stopped = (event.LastStreamID < r.StreamID)
+	}
+
+	r.actual = ev
+
+	if !headersEnded {
+		return false
+	}
+
+	// Informational responses precede the final response.
+	status, _ := headerFieldValue(r.fields, ":status")
+	if r.Headers == nil && len(status) == 3 && status[0] == '1' {
+		r.Interim = append(r.Interim, r.fields)
+	} else if r.Headers == nil {
+		r.Headers = r.fields
+	} else {
+		r.Trailers = r.fields
+	}
+	r.Ended = r.streamEnded
+
+	return true
+}
+
+// setDecodeError records the specified error unless an error has
+// already been recorded.
+func (r *Response) setDecodeError(err error) {
+	if r.DecodeError == nil {
+		r.DecodeError = err
+	}
+}
+
+// ResponseResult represents the response compared as the expected and
+// the actual result of a test case.
+type ResponseResult struct {
+	StreamID uint32
+
+	// Status is the status code of the response. The expected status
+	// code can be a pattern such as "2xx", where "x" matches any digit.
+	Status string
+}
+
+func (r ResponseResult) String() string {
+	return fmt.Sprintf("Response (stream_id:%d, :status:%s)", r.StreamID, r.Status)
+}
+
+// Matches returns true if the actual response matches the expected
+// response.
+func (r ResponseResult) Matches(actual ResponseResult) bool {
+	if r.StreamID != actual.StreamID || len(r.Status) != len(actual.Status) {
+		return false
+	}
+
+	for i := 0; i < len(r.Status); i++ {
+		if r.Status[i] != 'x' && r.Status[i] != actual.Status[i] {
+			return false
+		}
+	}
+
+	return true
+}
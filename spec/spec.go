@@ -1,12 +1,16 @@
 package spec
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"runtime/debug"
 	"strings"
 	"time"
 
+	"golang.org/x/net/http2"
+
 	"github.com/summerwind/h2spec/config"
 	"github.com/summerwind/h2spec/log"
 )
@@ -24,6 +28,8 @@ type TestGroup struct {
 	Section     string
 	Name        string
 	Strict      bool
+	Profiles    []Profile
+	Tags        []string
 	Parent      *TestGroup
 	Groups      []*TestGroup
 	Tests       []*TestCase
@@ -32,6 +38,35 @@ type TestGroup struct {
 	PassedCount  int
 	FailedCount  int
 	SkippedCount int
+	WarnedCount  int
+
+	InconclusiveCount  int
+	GoAwayOmittedCount int
+
+	// ErroredCount is the number of the test cases that panicked.
+	ErroredCount int
+
+	// FlakyCount is the number of the test cases whose repeated runs
+	// had mixed verdicts.
+	FlakyCount int
+}
+
+// tagsSelected returns true if any test case of this group or the
+// sub groups is selected by the tag filters.
+func (tg *TestGroup) tagsSelected(c *config.Config) bool {
+	for _, tc := range append(tg.Tests, tg.StrictTests...) {
+		if c.TagsSelected(tc.AllTags()) {
+			return true
+		}
+	}
+
+	for _, g := range tg.Groups {
+		if g.tagsSelected(c) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // IsRoot returns bool as to whether it is the parent of all groups.
@@ -67,60 +102,117 @@ func (tg *TestGroup) Level() int {
 	return strings.Count(tg.Section, ".") + 1
 }
 
-// Test runs all the tests included in this group.
-func (tg *TestGroup) Test(c *config.Config) {
-	level := tg.Level()
-
-	if tg.Strict && !c.Strict {
+// Test runs all the tests included in this group and reports the
+// progress to the reporter. The tests are stopped when the context is
+// done.
+func (tg *TestGroup) Test(ctx context.Context, c *config.Config, r Reporter) {
+	if !tg.selected(c) {
 		return
 	}
 
-	mode := c.RunMode(tg.ID())
-	if mode == config.RunModeNone {
-		return
-	}
-
-	log.SetIndentLevel(level)
-	log.Println(tg.Title())
-	log.SetIndentLevel(level + 1)
+	r.GroupStarted(tg)
 
 	tests := append(tg.Tests, tg.StrictTests...)
-	tested := false
 
 	for i, tc := range tests {
+		if ctx.Err() != nil {
+			break
+		}
+
 		seq := i + 1
 
-		err := tc.Test(c, seq)
+		err := tc.Test(ctx, c, seq, r)
 		if err != nil {
 			fmt.Printf("\nError: %v\n", err)
 			os.Exit(1)
 		}
 
 		if tc.Result != nil {
-			if tc.Result.Failed {
+			if tc.Result.Errored {
+				tg.ErroredCount += 1
+			} else if tc.Result.Failed {
 				tg.FailedCount += 1
+			} else if tc.Result.Inconclusive {
+				tg.InconclusiveCount += 1
 			} else if tc.Result.Skipped {
 				tg.SkippedCount += 1
 			} else {
 				tg.PassedCount += 1
 			}
 
-			tested = true
-		}
-	}
+			if tc.Result.Warned {
+				tg.WarnedCount += 1
+			}
+
+			if tc.Result.GoAwayOmitted {
+				tg.GoAwayOmittedCount += 1
+			}
 
-	if tested {
-		log.PrintBlankLine()
+			if tc.Result.Flaky {
+				tg.FlakyCount += 1
+			}
+		}
 	}
 
 	for _, g := range tg.Groups {
-		g.Test(c)
+		if ctx.Err() != nil {
+			break
+		}
+
+		g.Test(ctx, c, r)
 		tg.FailedCount += g.FailedCount
 		tg.SkippedCount += g.SkippedCount
 		tg.PassedCount += g.PassedCount
+		tg.WarnedCount += g.WarnedCount
+		tg.InconclusiveCount += g.InconclusiveCount
+		tg.GoAwayOmittedCount += g.GoAwayOmittedCount
+		tg.ErroredCount += g.ErroredCount
+		tg.FlakyCount += g.FlakyCount
 	}
 }
 
+// selected returns true if this group is selected to run by the
+// configuration.
+func (tg *TestGroup) selected(c *config.Config) bool {
+	if tg.Strict && !c.Strict {
+		return false
+	}
+
+	if !appliesTo(tg.Profiles, ActiveProfile(c)) {
+		return false
+	}
+
+	if !tg.tagsSelected(c) {
+		return false
+	}
+
+	return c.RunMode(tg.ID()) != config.RunModeNone
+}
+
+// NotAttemptedCount returns the number of the test cases of this group
+// and its sub groups that are selected to run but have no result, such
+// as the test cases left when the run has been interrupted.
+func (tg *TestGroup) NotAttemptedCount(c *config.Config) int {
+	if !tg.selected(c) {
+		return 0
+	}
+
+	count := 0
+
+	tests := append(tg.Tests, tg.StrictTests...)
+	for i, tc := range tests {
+		if tc.Result == nil && tc.selected(c, i+1) {
+			count += 1
+		}
+	}
+
+	for _, g := range tg.Groups {
+		count += g.NotAttemptedCount(c)
+	}
+
+	return count
+}
+
 // AddTestGroup registers a group to this group.
 func (tg *TestGroup) AddTestGroup(stg *TestGroup) {
 	stg.Parent = tg
@@ -139,6 +231,129 @@ func (tg *TestGroup) AddTestCase(tc *TestCase) {
 	} else {
 		tg.Tests = append(tg.Tests, tc)
 	}
+
+	// The test cases for the requirements of SHOULD are less severe
+	// than the others unless the severity is declared.
+	if tc.Severity == 0 {
+		if tc.Strict {
+			tc.Severity = SeverityLow
+		} else {
+			tc.Severity = SeverityMedium
+		}
+	}
+}
+
+// FailedCountBySeverity returns the number of the failed test cases
+// of this group and its sub groups whose severity is the specified
+// severity or higher.
+func (tg *TestGroup) FailedCountBySeverity(min Severity) int {
+	count := 0
+
+	tests := append(tg.Tests, tg.StrictTests...)
+	for _, tc := range tests {
+		if tc.Result != nil && tc.Result.Failed && tc.Severity >= min {
+			count += 1
+		}
+	}
+
+	for _, g := range tg.Groups {
+		count += g.FailedCountBySeverity(min)
+	}
+
+	return count
+}
+
+// Severity represents how important the non-conformance found by a
+// test case is.
+type Severity int
+
+const (
+	SeverityLow Severity = iota + 1
+	SeverityMedium
+	SeverityHigh
+)
+
+var severityNames = map[Severity]string{
+	SeverityLow:    "low",
+	SeverityMedium: "medium",
+	SeverityHigh:   "high",
+}
+
+// String returns the name of the severity.
+func (s Severity) String() string {
+	name, ok := severityNames[s]
+	if !ok {
+		return fmt.Sprintf("unknown severity %d", int(s))
+	}
+	return name
+}
+
+// ParseSeverity returns the severity of the specified name.
+func ParseSeverity(name string) (Severity, error) {
+	for s, n := range severityNames {
+		if n == strings.ToLower(name) {
+			return s, nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid severity: %s", name)
+}
+
+// Profile represents the rule set of HTTP/2 that the server is tested
+// against.
+type Profile int
+
+const (
+	ProfileRFC7540 Profile = iota + 1
+	ProfileRFC9113
+)
+
+var profileNames = map[Profile]string{
+	ProfileRFC7540: "7540",
+	ProfileRFC9113: "9113",
+}
+
+// String returns the name of the profile.
+func (p Profile) String() string {
+	name, ok := profileNames[p]
+	if !ok {
+		return fmt.Sprintf("unknown profile %d", int(p))
+	}
+	return name
+}
+
+// ParseProfile returns the profile of the specified name.
+func ParseProfile(name string) (Profile, error) {
+	for p, n := range profileNames {
+		if n == strings.TrimPrefix(strings.ToLower(name), "rfc") {
+			return p, nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid spec profile: %s", name)
+}
+
+// ActiveProfile returns the profile specified by the configuration, or
+// zero if no profile is specified.
+func ActiveProfile(c *config.Config) Profile {
+	p, _ := ParseProfile(c.Profile)
+	return p
+}
+
+// appliesTo returns true if the profiles include the specified profile.
+// Empty profiles or the zero profile means any profile.
+func appliesTo(profiles []Profile, p Profile) bool {
+	if len(profiles) == 0 || p == 0 {
+		return true
+	}
+
+	for _, profile := range profiles {
+		if profile == p {
+			return true
+		}
+	}
+
+	return false
 }
 
 // TestCase represents a test case.
@@ -146,54 +361,265 @@ type TestCase struct {
 	Desc        string
 	Requirement string
 	Strict      bool
+	Severity    Severity
+	Timeout     time.Duration
 	Parent      *TestGroup
 	Result      *TestResult
 	Run         func(c *config.Config, conn *Conn) error
+
+	// Profiles is the list of the profiles the test case applies to.
+	// The profiles of the nearest group are used if empty, and the
+	// test case applies to any profile if no group declares them.
+	Profiles []Profile
+
+	// ProfileDependent is true if the expected outcome of the test
+	// case differs between the profiles. Run checks ActiveProfile to
+	// choose the verification.
+	ProfileDependent bool
+
+	// Tags is the list of the tags used to select the test case, in
+	// addition to the tags of the groups.
+	Tags []string
+
+	// Requires is the list of the capabilities the test case requires.
+	// The test case is skipped if any of them is not met.
+	Requires []Capability
+
+	// Settings is the list of the settings sent in the client
+	// connection preface, which override the settings of the
+	// configuration.
+	Settings []http2.Setting
 }
 
-// Test runs itself as a test case.
-func (tc *TestCase) Test(c *config.Config, seq int) error {
-	if tc.Strict && !c.Strict {
-		return nil
+// AllTags returns the tags of the test case including the tags of the
+// groups it belongs to.
+func (tc *TestCase) AllTags() []string {
+	tags := []string{}
+	for tg := tc.Parent; tg != nil; tg = tg.Parent {
+		tags = append(append([]string{}, tg.Tags...), tags...)
+	}
+	tags = append(tags, tc.Tags...)
+
+	return tags
+}
+
+// profiles returns the profiles the test case applies to.
+func (tc *TestCase) profiles() []Profile {
+	if len(tc.Profiles) > 0 {
+		return tc.Profiles
+	}
+
+	for tg := tc.Parent; tg != nil; tg = tg.Parent {
+		if len(tg.Profiles) > 0 {
+			return tg.Profiles
+		}
+	}
+
+	return nil
+}
+
+// Applicability returns the description of the profiles the test case
+// applies to, such as "profile:9113". It returns an empty string if the
+// test case applies to any profile in the same way.
+func (tc *TestCase) Applicability() string {
+	names := []string{}
+	for _, p := range tc.profiles() {
+		names = append(names, p.String())
+	}
+
+	if tc.ProfileDependent {
+		if len(names) == 0 {
+			names = []string{ProfileRFC7540.String(), ProfileRFC9113.String()}
+		}
+		return fmt.Sprintf("profile:%s, outcome differs", strings.Join(names, ","))
 	}
 
-	mode := c.RunMode(fmt.Sprintf("%s/%d", tc.Parent.ID(), seq))
-	if mode == config.RunModeNone {
+	if len(names) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("profile:%s", strings.Join(names, ","))
+}
+
+// Test runs itself as a test case and reports the result to the
+// reporter.
+func (tc *TestCase) Test(ctx context.Context, c *config.Config, seq int, r Reporter) error {
+	if !tc.selected(c, seq) {
 		return nil
 	}
 
+	r.TestStarted(tc, seq)
+
 	if c.DryRun {
-		msg := fmt.Sprintf("%s %s", seqStr(seq), tc.Desc)
-		log.Println(msg)
 		tc.Result = NewTestResult(tc, seq, nil, time.Duration(0))
+		r.TestFinished(tc.Result)
+		return nil
+	}
+
+	if reason := tc.unmetRequirement(c); reason != "" {
+		tc.Result = NewTestResult(tc, seq, &TestSkipped{Reason: reason}, time.Duration(0))
+		r.TestFinished(tc.Result)
+		logTestFinished(tc.id(seq), tc.Result)
 		return nil
 	}
 
-	if !c.Verbose {
-		msg := gray(fmt.Sprintf("  %s %s", seqStr(seq), tc.Desc))
-		log.Print(msg)
+	// The test case runs with its own timeout if it declares one.
+	timeout := c.TestTimeout(tc.Timeout)
+	if timeout != c.Timeout {
+		tcc := *c
+		tcc.Timeout = timeout
+		c = &tcc
 	}
 
-	conn, err := Dial(c)
+	logRecord(EventRecord{Test: tc.id(seq), Event: "test_started"})
+
+	// The test case is repeated on new connections to detect the
+	// intermittent failures. The most severe result of the iterations
+	// is reported, so that any failed iteration fails the run.
+	repeat := c.Repeat
+	if repeat < 1 {
+		repeat = 1
+	}
+
+	var tr *TestResult
+	verdicts := map[string]int{}
+	for i := 1; i <= repeat; i++ {
+		if repeat > 1 && !c.Verbose {
+			log.ResetLine()
+			log.Print(gray(fmt.Sprintf("  %d: %s (iteration %d/%d)", seq, tc.Desc, i, repeat)))
+		}
+
+		itr, err := tc.attempt(ctx, c, seq)
+		if err != nil {
+			return err
+		}
+
+		// The result of the interrupted test case is not reported.
+		if itr == nil {
+			return nil
+		}
+
+		verdicts[itr.Verdict()] += 1
+		if tr == nil || itr.severity() > tr.severity() {
+			tr = itr
+		}
+	}
+
+	if repeat > 1 {
+		tr.Iterations = repeat
+		tr.PassedIterations = verdicts["passed"] + verdicts["warning"]
+		tr.FailedIterations = repeat - tr.PassedIterations
+		tr.Flaky = (len(verdicts) > 1)
+	}
+
+	if tc.Timeout != 0 {
+		tr.Timeout = timeout
+	}
+	tc.Result = tr
+	r.TestFinished(tr)
+	logTestFinished(tc.id(seq), tr)
+
+	return nil
+}
+
+// selected returns true if the test case of the specified sequence
+// number is selected to run by the configuration.
+func (tc *TestCase) selected(c *config.Config, seq int) bool {
+	if tc.Strict && !c.Strict {
+		return false
+	}
+
+	if !appliesTo(tc.profiles(), ActiveProfile(c)) {
+		return false
+	}
+
+	if !c.TagsSelected(tc.AllTags()) {
+		return false
+	}
+
+	return c.RunMode(tc.id(seq)) != config.RunModeNone
+}
+
+// attempt runs the test case, and runs it again on a new connection if
+// it failed with the timeout or an error other than the verdict. It
+// returns nil result if the run has been cancelled.
+func (tc *TestCase) attempt(ctx context.Context, c *config.Config, seq int) (*TestResult, error) {
+	for attempt := 1; ; attempt++ {
+		tr, err := tc.run(ctx, c, seq)
+		if ctx.Err() != nil {
+			return nil, nil
+		}
+
+		if err != nil {
+			if attempt <= c.Retries {
+				continue
+			}
+
+			msg := red(fmt.Sprintf("%s %s %s", "×", seqStr(seq), tc.Desc))
+			log.ResetLine()
+			log.Println(msg)
+			return nil, err
+		}
+
+		if !tr.retryable() || attempt > c.Retries {
+			tr.Attempts = attempt
+			return tr, nil
+		}
+	}
+}
+
+// run runs the test case once on a new connection. The error is
+// returned only if the connection could not be established.
+func (tc *TestCase) run(ctx context.Context, c *config.Config, seq int) (*TestResult, error) {
+	id := tc.id(seq)
+
+	traffic := &Traffic{}
+	ctx = withTraffic(ctx, traffic)
+
+	conn, err := DialContext(ctx, c)
 	if err != nil {
-		msg := red(fmt.Sprintf("%s %s %s", "×", seqStr(seq), tc.Desc))
-		log.ResetLine()
-		log.Println(msg)
-		return err
+		logRecord(EventRecord{Test: id, Event: "error", Message: err.Error()})
+		return nil, err
 	}
 	defer conn.Close()
 
+	conn.testID = id
+	conn.clientSettings = mergeSettings(conn.clientSettings, tc.Settings...)
+	logRecord(EventRecord{
+		Test:    id,
+		Event:   "connection_opened",
+		Message: conn.RemoteAddr().String(),
+	})
+
 	start := time.Now()
-	err = tc.Run(c, conn)
+	err = tc.recoverRun(c, conn)
 	end := time.Now()
 
-	log.ResetLine()
-
 	tr := NewTestResult(tc, seq, err, end.Sub(start))
-	tr.Print()
-	tc.Result = tr
+	tr.GoAwayDebugData = conn.GoAwayDebugData
+	tr.SettingsAckLatency = conn.SettingsAckLatency
+	tr.Traffic = traffic.snapshot()
 
-	return nil
+	return tr, nil
+}
+
+// recoverRun runs the test case on the connection. A panic in the test
+// case is recovered and returned as TestPanic, so that the run continues
+// with the next test case after the connection is closed.
+func (tc *TestCase) recoverRun(c *config.Config, conn *Conn) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &TestPanic{Value: v, Stack: debug.Stack()}
+		}
+	}()
+
+	return tc.Run(c, conn)
+}
+
+// id returns the identifier of the test case of the specified sequence
+// number, such as "http2/6.5/1".
+func (tc *TestCase) id(seq int) string {
+	return fmt.Sprintf("%s/%d", tc.Parent.ID(), seq)
 }
 
 // TestError represents a error result of test case and implements
@@ -201,6 +627,17 @@ func (tc *TestCase) Test(c *config.Config, seq int) error {
 type TestError struct {
 	Expected []string
 	Actual   string
+
+	// History is the last events received before the actual one,
+	// which is set by ExpectFrame.
+	History []string
+
+	// ExpectedAttrs is the attributes of each acceptable alternative
+	// of the expected frame, and ActualAttrs is the attributes of the
+	// actual frame. They are set by the verifiers that compare the
+	// frames attribute by attribute, and shown as a table.
+	ExpectedAttrs []FrameAttributes
+	ActualAttrs   *FrameAttributes
 }
 
 // Returns a string containing the reason of the error.
@@ -208,6 +645,51 @@ func (e TestError) Error() string {
 	return fmt.Sprintf("%s\n%s", strings.Join(e.Expected, "\n"), e.Actual)
 }
 
+// TestWarning represents a result of test case that has passed
+// with a remark, and implements type error.
+type TestWarning struct {
+	Reason string
+}
+
+// Returns a string containing the reason of the warning.
+func (w TestWarning) Error() string {
+	return w.Reason
+}
+
+// TestInfo represents a result of test case that has passed with
+// the description of the observed behavior, and implements type error.
+type TestInfo struct {
+	Message string
+}
+
+// Returns a string describing the observed behavior.
+func (i TestInfo) Error() string {
+	return i.Message
+}
+
+// TestSkipped represents a skipped test case with the reason.
+type TestSkipped struct {
+	Reason string
+}
+
+// Returns the reason why the test case was skipped.
+func (s TestSkipped) Error() string {
+	return s.Reason
+}
+
+// TestPanic represents a test case that panicked, which is a bug of the
+// test case rather than a verdict on the server, and implements type
+// error.
+type TestPanic struct {
+	Value interface{}
+	Stack []byte
+}
+
+// Returns a string containing the value of the panic.
+func (p TestPanic) Error() string {
+	return fmt.Sprintf("panic: %v", p.Value)
+}
+
 // TestResult represents a result of test case.
 type TestResult struct {
 	TestCase *TestCase
@@ -215,52 +697,240 @@ type TestResult struct {
 	Error    error
 	Duration time.Duration
 
+	// Timeout is the timeout declared by the test case, or zero if
+	// the test case ran with the global timeout.
+	Timeout time.Duration
+
+	// Attempts is the number of times the test case was run.
+	Attempts int
+
 	Skipped bool
 	Failed  bool
+	Warned  bool
+
+	// Errored is true if the test case panicked. It is neither passed
+	// nor failed since the server was not verified.
+	Errored bool
+
+	// Inconclusive is true if the test case expected a frame but no
+	// frame was received within the timeout. It may be caused by the
+	// packet loss or an overloaded server, so that it is neither
+	// passed nor failed.
+	Inconclusive bool
+
+	// GoAwayOmitted is true if the test case has passed with the
+	// connection closed without GOAWAY frame instead of the expected
+	// connection error.
+	GoAwayOmitted bool
+
+	// GoAwayDebugData is the debug data of the last GOAWAY frame
+	// received during the test case.
+	GoAwayDebugData []byte
+
+	// SettingsAckLatency is the time the server took to acknowledge
+	// the SETTINGS frame of the client connection preface on the
+	// connection of the test case, or zero if the handshake was not
+	// performed. The other connections of the test case are not
+	// measured.
+	SettingsAckLatency time.Duration
+
+	// Traffic is the traffic of the connections opened by the test
+	// case.
+	Traffic Traffic
+
+	// Iterations is the number of times the test case was repeated,
+	// or zero if it was not repeated. PassedIterations is the number
+	// of the iterations that passed, including the warned ones, and
+	// FailedIterations is the number of the others, such as the
+	// errored and inconclusive ones, so that they sum up to
+	// Iterations. Flaky is true if the iterations had mixed verdicts.
+	Iterations       int
+	PassedIterations int
+	FailedIterations int
+	Flaky            bool
 }
 
 // NewTestResult returns a TestResult.
 func NewTestResult(tc *TestCase, seq int, err error, d time.Duration) *TestResult {
 	skipped := false
 	failed := false
+	warned := false
+	inconclusive := false
+	errored := false
 
 	if err != nil {
-		if err == ErrSkipped {
+		if _, ok := err.(*TestPanic); ok {
+			errored = true
+		} else if err == ErrSkipped {
+			skipped = true
+		} else if _, ok := err.(*TestSkipped); ok {
 			skipped = true
+		} else if _, ok := err.(*TestWarning); ok {
+			warned = true
+		} else if _, ok := err.(*TestInfo); ok {
+			// The test case has passed.
+		} else if e, ok := err.(*TestError); ok && e.Actual == (TimeoutEvent{}).String() {
+			inconclusive = true
 		} else {
 			failed = true
 		}
 	}
 
+	goAwayOmitted := false
+	if w, ok := err.(*TestWarning); ok && w.Reason == ReasonGoAwayOmitted {
+		goAwayOmitted = true
+	}
+
 	tr := TestResult{
-		TestCase: tc,
-		Sequence: seq,
-		Error:    err,
-		Duration: d,
-		Skipped:  skipped,
-		Failed:   failed,
+		TestCase:     tc,
+		Sequence:     seq,
+		Error:        err,
+		Duration:     d,
+		Skipped:      skipped,
+		Failed:       failed,
+		Warned:       warned,
+		Inconclusive: inconclusive,
+		Errored:      errored,
+
+		GoAwayOmitted: goAwayOmitted,
 	}
 
 	return &tr
 }
 
+// Verdict returns the verdict of the test result, which is one of
+// "errored", "skipped", "failed", "inconclusive", "warning" and
+// "passed".
+func (tr *TestResult) Verdict() string {
+	switch {
+	case tr.Errored:
+		return "errored"
+	case tr.Skipped:
+		return "skipped"
+	case tr.Failed:
+		return "failed"
+	case tr.Inconclusive:
+		return "inconclusive"
+	case tr.Warned:
+		return "warning"
+	default:
+		return "passed"
+	}
+}
+
+// severity returns the rank of the verdict used to choose the result
+// reported for the repeated test case. The higher rank is reported.
+func (tr *TestResult) severity() int {
+	switch {
+	case tr.Errored:
+		return 5
+	case tr.Failed:
+		return 4
+	case tr.Inconclusive:
+		return 3
+	case tr.Warned:
+		return 2
+	case tr.Skipped:
+		return 0
+	default:
+		return 1
+	}
+}
+
+// retryable returns true if the test case was inconclusive or failed
+// with an error other than the verdict. The verdict that an unexpected
+// frame was received is never retried.
+func (tr *TestResult) retryable() bool {
+	if tr.Inconclusive {
+		return true
+	}
+
+	if !tr.Failed {
+		return false
+	}
+
+	_, ok := tr.Error.(*TestError)
+	return !ok
+}
+
 // Print prints the result of test case.
 func (tr *TestResult) Print() {
 	tc := tr.TestCase
 	desc := tc.Desc
 	seq := seqStr(tr.Sequence)
 
+	if tr.Timeout != 0 {
+		desc = fmt.Sprintf("%s (timeout:%s)", desc, tr.Timeout)
+	}
+	if tr.Attempts > 1 {
+		desc = fmt.Sprintf("%s (attempts:%d)", desc, tr.Attempts)
+	}
+	if tr.Iterations > 0 {
+		desc = fmt.Sprintf("%s (iterations:%d, passed:%d, failed:%d)", desc, tr.Iterations, tr.PassedIterations, tr.FailedIterations)
+	}
+	if tr.Flaky {
+		desc = fmt.Sprintf("%s (flaky)", desc)
+	}
+
+	if tr.Errored {
+		log.Println(red(fmt.Sprintf("%s %s %s (errored)", "!", seq, desc)))
+
+		level := log.IndentLevel
+		log.SetIndentLevel(level + 1)
+		log.Println(red(fmt.Sprintf("-> Error: %v", tr.Error)))
+		log.SetIndentLevel(level)
+		return
+	}
+
 	if tr.Skipped {
 		log.Println(cyan(fmt.Sprintf("%s %s", seq, desc)))
+
+		if skipped, ok := tr.Error.(*TestSkipped); ok {
+			level := log.IndentLevel
+			log.SetIndentLevel(level + 1)
+			log.Println(cyan(fmt.Sprintf("-> Skipped: %s", skipped.Reason)))
+			log.SetIndentLevel(level)
+		}
+		return
+	}
+
+	if tr.Warned {
+		log.Println(fmt.Sprintf("%s %s %s", yellow("✔"), gray(seq), gray(desc)))
+
+		level := log.IndentLevel
+		log.SetIndentLevel(level + 1)
+		log.Println(yellow(fmt.Sprintf("-> Warning: %v", tr.Error)))
+		log.SetIndentLevel(level)
+		return
+	}
+
+	if tr.Inconclusive {
+		log.Println(fmt.Sprintf("%s %s %s", yellow("?"), gray(seq), gray(desc)))
+
+		level := log.IndentLevel
+		log.SetIndentLevel(level + 1)
+		log.Println(yellow("-> Inconclusive: no frame received within the timeout"))
+		if err, ok := tr.Error.(*TestError); ok {
+			log.Println(yellow(fmt.Sprintf("   Expected: %s", strings.Join(err.Expected, ", "))))
+		}
+		log.SetIndentLevel(level)
 		return
 	}
 
 	if !tr.Failed {
 		log.Println(fmt.Sprintf("%s %s %s", green("✔"), gray(seq), gray(desc)))
+
+		if info, ok := tr.Error.(*TestInfo); ok {
+			level := log.IndentLevel
+			log.SetIndentLevel(level + 1)
+			log.Println(gray(fmt.Sprintf("-> %s", info.Message)))
+			log.SetIndentLevel(level)
+		}
+		tr.printTraffic()
 		return
 	}
 
-	log.Println(red(fmt.Sprintf("%s %s %s", "×", seq, desc)))
+	log.Println(red(fmt.Sprintf("%s %s %s (severity:%s)", "×", seq, desc, tc.Severity)))
 	err, ok := tr.Error.(*TestError)
 	if ok {
 		level := log.IndentLevel
@@ -279,6 +949,29 @@ func (tr *TestResult) Print() {
 		}
 		log.Println(green(fmt.Sprintf("     Actual: %s", err.Actual)))
 
+		if err.ActualAttrs != nil && len(err.ExpectedAttrs) > 0 {
+			printAttributes(err.ExpectedAttrs, err.ActualAttrs)
+		}
+
+		// The last event of the history is the actual one.
+		if len(err.History) > 1 {
+			label = "History: "
+			for i, ev := range err.History {
+				if i != 0 {
+					label = strings.Repeat(" ", len(label))
+				}
+				log.Println(gray(fmt.Sprintf("    %s%s", label, ev)))
+			}
+		}
+
+		if len(tr.GoAwayDebugData) > 0 {
+			log.Println(gray(fmt.Sprintf(" Debug data: %s", DebugDataString(tr.GoAwayDebugData))))
+		}
+
+		if tr.isFlood() {
+			log.Println(gray(fmt.Sprintf("    Traffic: %s", tr.Traffic)))
+		}
+
 		return
 	}
 	if err == nil {
@@ -288,6 +981,29 @@ func (tr *TestResult) Print() {
 	}
 }
 
+// isFlood returns true if the test case is tagged "flood", so that its
+// traffic is shown along with the result.
+func (tr *TestResult) isFlood() bool {
+	for _, tag := range tr.TestCase.AllTags() {
+		if tag == "flood" {
+			return true
+		}
+	}
+	return false
+}
+
+// printTraffic prints the traffic of the passed flood test case.
+func (tr *TestResult) printTraffic() {
+	if !tr.isFlood() {
+		return
+	}
+
+	level := log.IndentLevel
+	log.SetIndentLevel(level + 1)
+	log.Println(gray(fmt.Sprintf("-> Traffic: %s", tr.Traffic)))
+	log.SetIndentLevel(level)
+}
+
 func seqStr(seq int) string {
 	return fmt.Sprintf("%d:", seq)
 }
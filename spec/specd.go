@@ -206,6 +206,8 @@ func NewClientTestResult(tc *ClientTestCase, err error, d time.Duration) *Client
 	if err != nil {
 		if err == ErrSkipped {
 			skipped = true
+		} else if _, ok := err.(*TestSkipped); ok {
+			skipped = true
 		} else {
 			failed = true
 		}
@@ -230,6 +232,13 @@ func (tr *ClientTestResult) Print() {
 
 	if tr.Skipped {
 		log.Println(cyan(fmt.Sprintf("%s %s", seq, desc)))
+
+		if skipped, ok := tr.Error.(*TestSkipped); ok {
+			level := log.IndentLevel
+			log.SetIndentLevel(level + 1)
+			log.Println(cyan(fmt.Sprintf("-> Skipped: %s", skipped.Reason)))
+			log.SetIndentLevel(level)
+		}
 		return
 	}
 
@@ -0,0 +1,81 @@
+package spec
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// Traffic represents the traffic of the connections opened by a test
+// case. The counters are updated by the connections, including the raw
+// data sent by Send, so that they are accurate for any test case.
+type Traffic struct {
+	Connections    int64 `json:"connections"`
+	FramesSent     int64 `json:"frames_sent"`
+	FramesReceived int64 `json:"frames_received"`
+	BytesSent      int64 `json:"bytes_sent"`
+	BytesReceived  int64 `json:"bytes_received"`
+}
+
+// Add adds the counters of the traffic.
+func (t *Traffic) Add(o Traffic) {
+	t.Connections += o.Connections
+	t.FramesSent += o.FramesSent
+	t.FramesReceived += o.FramesReceived
+	t.BytesSent += o.BytesSent
+	t.BytesReceived += o.BytesReceived
+}
+
+// snapshot returns a copy of the counters that are updated
+// concurrently by the connections.
+func (t *Traffic) snapshot() Traffic {
+	return Traffic{
+		Connections:    atomic.LoadInt64(&t.Connections),
+		FramesSent:     atomic.LoadInt64(&t.FramesSent),
+		FramesReceived: atomic.LoadInt64(&t.FramesReceived),
+		BytesSent:      atomic.LoadInt64(&t.BytesSent),
+		BytesReceived:  atomic.LoadInt64(&t.BytesReceived),
+	}
+}
+
+// String returns a string representation of the traffic, such as
+// "1 connections, sent 3 frames (120 bytes), received 5 frames (300
+// bytes)".
+func (t Traffic) String() string {
+	return fmt.Sprintf(
+		"%d connections, sent %d frames (%d bytes), received %d frames (%d bytes)",
+		t.Connections,
+		t.FramesSent,
+		t.BytesSent,
+		t.FramesReceived,
+		t.BytesReceived,
+	)
+}
+
+type trafficKey struct{}
+
+// withTraffic returns the context whose connections count their
+// traffic on the specified Traffic.
+func withTraffic(ctx context.Context, t *Traffic) context.Context {
+	return context.WithValue(ctx, trafficKey{}, t)
+}
+
+// trafficFromContext returns the Traffic of the context, or a new one
+// if the context has none.
+func trafficFromContext(ctx context.Context) *Traffic {
+	if t, ok := ctx.Value(trafficKey{}).(*Traffic); ok {
+		return t
+	}
+	return &Traffic{}
+}
+
+// frameWriter counts the frames written by the framer, which writes
+// each frame in a single call.
+type frameWriter struct {
+	conn *Conn
+}
+
+func (w frameWriter) Write(b []byte) (int, error) {
+	atomic.AddInt64(&w.conn.traffic.FramesSent, 1)
+	return w.conn.Write(b)
+}
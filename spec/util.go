@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/fatih/color"
 	"github.com/summerwind/h2spec/config"
@@ -44,6 +45,63 @@ func HeaderField(name, value string) hpack.HeaderField {
 	return hpack.HeaderField{Name: name, Value: value}
 }
 
+// LiteralHeaderField returns the literal header field representation
+// without indexing and Huffman encoding of the specified name and
+// value. This is used to send the octets of a header field as is.
+func LiteralHeaderField(name, value string) []byte {
+	rep := []byte{0x00}
+	rep = appendStringLiteral(rep, name)
+	rep = appendStringLiteral(rep, value)
+	return rep
+}
+
+// LiteralHeaderFieldWithIndexing returns the literal header field
+// representation with incremental indexing and without Huffman
+// encoding of the specified name and value.
+func LiteralHeaderFieldWithIndexing(name, value string) []byte {
+	rep := []byte{0x40}
+	rep = appendStringLiteral(rep, name)
+	rep = appendStringLiteral(rep, value)
+	return rep
+}
+
+// IndexedHeaderField returns the indexed header field representation
+// of the specified index.
+func IndexedHeaderField(index uint64) []byte {
+	return appendInteger(nil, 0x80, 7, index)
+}
+
+// DynamicTableSizeUpdate returns the dynamic table size update of the
+// specified size.
+func DynamicTableSizeUpdate(size uint64) []byte {
+	return appendInteger(nil, 0x20, 5, size)
+}
+
+// appendStringLiteral appends the string literal representation of
+// the specified string without Huffman encoding.
+func appendStringLiteral(dst []byte, s string) []byte {
+	dst = appendInteger(dst, 0x00, 7, uint64(len(s)))
+	return append(dst, s...)
+}
+
+// appendInteger appends the integer representation of the specified
+// value with N-bit prefix. The first octet is combined with the
+// specified flags.
+func appendInteger(dst []byte, flags byte, n uint8, i uint64) []byte {
+	max := uint64(1)<<n - 1
+	if i < max {
+		return append(dst, flags|byte(i))
+	}
+
+	dst = append(dst, flags|byte(max))
+	i -= max
+	for i >= 128 {
+		dst = append(dst, byte(i%128)|0x80)
+		i /= 128
+	}
+	return append(dst, byte(i))
+}
+
 // CommonHeaders returns a array of header field of HPACK contained
 // common http headers used in various test case.
 func CommonHeaders(c *config.Config) []hpack.HeaderField {
@@ -76,6 +134,163 @@ func CommonHeaders(c *config.Config) []hpack.HeaderField {
 	}
 }
 
+// RequestHeaders returns a array of header field of HPACK of the
+// canonical request loaded from the request file, which is used by the
+// test cases that only need a normal request to exercise the server.
+// The header fields of CommonHeaders are returned if no request file
+// is specified. "content-length" header field is derived from the body
+// of the request if it has one, and the test cases send the body with
+// WriteRequestBody then. The test cases that craft a malformed request
+// use CommonHeaders instead.
+func RequestHeaders(c *config.Config) []hpack.HeaderField {
+	req := c.Request
+	if req == nil {
+		return CommonHeaders(c)
+	}
+
+	headers := RequestHeadersWithMethod(c, req.Method)
+	if len(req.Body) > 0 {
+		headers = append(headers, HeaderField("content-length", strconv.Itoa(len(req.Body))))
+	}
+
+	return headers
+}
+
+// RequestHeadersWithMethod returns a array of header field of HPACK of
+// the canonical request as RequestHeaders does, with the method
+// replaced by the specified one. It is used by the test cases of a
+// specific method, which send the body of their own, so that
+// "content-length" header field is not included.
+func RequestHeadersWithMethod(c *config.Config, method string) []hpack.HeaderField {
+	headers := CommonHeaders(c)
+	headers[0].Value = method
+
+	req := c.Request
+	if req == nil {
+		return headers
+	}
+
+	headers[2].Value = req.Path
+	if req.Authority != "" {
+		headers[3].Value = req.Authority
+	}
+
+	return append(headers, req.Headers...)
+}
+
+// HasRequestBody returns true if the canonical request has a body. The
+// header block of the request must not end the stream then, and the
+// body follows it by WriteRequestBody.
+func HasRequestBody(c *config.Config) bool {
+	return len(requestBody(c)) > 0
+}
+
+// WriteRequestBody writes the body of the canonical request in DATA
+// frames on the specified stream, ending the stream with the last one.
+// Nothing is written if the request has no body.
+func WriteRequestBody(conn *Conn, c *config.Config, streamID uint32) error {
+	return writeBody(conn, streamID, requestBody(c))
+}
+
+// WriteRequest writes the canonical request on the specified stream.
+// The body of the request is sent in DATA frames following the HEADERS
+// frame.
+func WriteRequest(conn *Conn, c *config.Config, streamID uint32) error {
+	hp := http2.HeadersFrameParam{
+		StreamID:      streamID,
+		EndStream:     !HasRequestBody(c),
+		EndHeaders:    true,
+		BlockFragment: conn.EncodeHeaders(RequestHeaders(c)),
+	}
+	err := conn.WriteHeaders(hp)
+	if err != nil {
+		return err
+	}
+
+	return WriteRequestBody(conn, c, streamID)
+}
+
+// requestBody returns the body of the canonical request, or nil if no
+// request file is specified.
+func requestBody(c *config.Config) []byte {
+	if c.Request == nil {
+		return nil
+	}
+	return c.Request.Body
+}
+
+// writeBody writes the body in DATA frames on the specified stream,
+// ending the stream with the last one.
+func writeBody(conn *Conn, streamID uint32, body []byte) error {
+	max := conn.MaxFrameSize()
+	for len(body) > 0 {
+		n := len(body)
+		if n > max {
+			n = max
+		}
+
+		err := conn.WriteData(streamID, n == len(body), body[:n])
+		if err != nil {
+			return err
+		}
+		body = body[n:]
+	}
+
+	return nil
+}
+
+// ResourceHeaders returns a array of header field of HPACK contained
+// common http headers to request the large resource used in the flow
+// control test cases. The target path is used if the path of resource
+// is not configured.
+func ResourceHeaders(c *config.Config) []hpack.HeaderField {
+	headers := CommonHeaders(c)
+	if c.ResourcePath != "" {
+		headers[2].Value = c.ResourcePath
+	}
+	return headers
+}
+
+// MultiplexHeaders returns a array of header field of HPACK contained
+// common http headers to request the i-th path of the multiplexing
+// test cases. The paths are used in turn, and the target path is used
+// if no path is configured.
+func MultiplexHeaders(c *config.Config, i int) []hpack.HeaderField {
+	headers := CommonHeaders(c)
+	if len(c.MultiplexPaths) > 0 {
+		headers[2].Value = c.MultiplexPaths[i%len(c.MultiplexPaths)]
+	}
+	return headers
+}
+
+// UploadHeaders returns a array of header field of HPACK contained
+// common http headers to upload a request body of the specified size
+// used in the flow control test cases.
+func UploadHeaders(c *config.Config, size int) []hpack.HeaderField {
+	headers := CommonHeaders(c)
+	headers[0].Value = "POST"
+	headers[2].Value = c.UploadPath
+	return append(headers, HeaderField("content-length", strconv.Itoa(size)))
+}
+
+// EchoHeaders returns a array of header field of HPACK contained
+// common http headers to request the echo endpoint.
+func EchoHeaders(c *config.Config) []hpack.HeaderField {
+	headers := CommonHeaders(c)
+	headers[2].Value = c.EchoPath
+	return headers
+}
+
+// TrailersHeaders returns a array of header field of HPACK contained
+// common http headers to request the endpoint that sends trailers.
+// "te: trailers" is included since some endpoints, such as gRPC,
+// require it to send trailers.
+func TrailersHeaders(c *config.Config) []hpack.HeaderField {
+	headers := CommonHeaders(c)
+	headers[2].Value = c.TrailersPath
+	return append(headers, HeaderField("te", "trailers"))
+}
+
 // CommonHeaders returns a array of header field of HPACK contained
 // common http headers used in various test case.
 func CommonRespHeaders(c *config.Config) []hpack.HeaderField {
@@ -113,6 +328,24 @@ func DummyRespHeaders(c *config.Config, len int) []hpack.HeaderField {
 
 // ServerDataLength returns the total length of the DATA frame of /.
 func ServerDataLength(c *config.Config) (int, error) {
+	return dataLength(c, CommonHeaders(c), nil)
+}
+
+// RequestDataLength returns the total length of the DATA frame of the
+// response to the canonical request.
+func RequestDataLength(c *config.Config) (int, error) {
+	return dataLength(c, RequestHeaders(c), requestBody(c))
+}
+
+// ResourceDataLength returns the total length of the DATA frame of
+// the large resource used in the flow control test cases.
+func ResourceDataLength(c *config.Config) (int, error) {
+	return dataLength(c, ResourceHeaders(c), nil)
+}
+
+// dataLength returns the total length of the DATA frame of the
+// response for the specified request headers and body.
+func dataLength(c *config.Config, headers []hpack.HeaderField, body []byte) (int, error) {
 	conn, err := Dial(c)
 	if err != nil {
 		return 0, err
@@ -123,14 +356,14 @@ func ServerDataLength(c *config.Config) (int, error) {
 		return 0, err
 	}
 
-	headers := CommonHeaders(c)
 	hp := http2.HeadersFrameParam{
 		StreamID:      1,
-		EndStream:     true,
+		EndStream:     len(body) == 0,
 		EndHeaders:    true,
 		BlockFragment: conn.EncodeHeaders(headers),
 	}
 	conn.WriteHeaders(hp)
+	writeBody(conn, 1, body)
 
 	len := 0
 	done := false
@@ -1,10 +1,15 @@
 package spec
 
 import (
+	"encoding/binary"
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
 	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
 )
 
 const (
@@ -12,8 +17,26 @@ const (
 	ExpectedStreamClosed     = "Stream closed"
 	ExpectedGoAwayFrame      = "GOAWAY Frame (Error Code: %s)"
 	ExpectedRSTStreamFrame   = "RST_STREAM Frame (Error Code: %s)"
+
+	ExpectedGoAwayFrameWithLastStreamID = "GOAWAY Frame (Last Stream ID: >=%d, Error Code: %s)"
+	ExpectedClientErrorResponse         = "HEADERS Frame (stream_id:%d, :status:4xx)"
+
+	// ReasonGoAwayOmitted is the reason of the warning reported when
+	// the connection was closed without GOAWAY frame instead of the
+	// expected connection error.
+	ReasonGoAwayOmitted = "The connection was closed without GOAWAY frame"
+
+	// ReasonStreamErrorOmitted is the reason of the warning reported
+	// when the connection was closed without RST_STREAM or GOAWAY
+	// frame instead of the expected stream error.
+	ReasonStreamErrorOmitted = "The connection was closed without RST_STREAM or GOAWAY frame"
 )
 
+// AnyStreamID is the stream identifier that matches a frame on any
+// stream. No RST_STREAM frame can be sent on the stream 0x0, so it is
+// never mistaken for the actual stream.
+const AnyStreamID uint32 = 0
+
 // VerifyConnectionClose verifies whether the connection was closed.
 func VerifyConnectionClose(conn *Conn) error {
 	var actual Event
@@ -22,6 +45,10 @@ func VerifyConnectionClose(conn *Conn) error {
 	for !conn.Closed {
 		event := conn.WaitEvent()
 
+		if conn.skipBackground(event) {
+			continue
+		}
+
 		switch ev := event.(type) {
 		case ConnectionClosedEvent:
 			passed = true
@@ -49,19 +76,496 @@ func VerifyConnectionClose(conn *Conn) error {
 }
 
 // VerifyConnectionError verifies whether a connection error of HTTP/2
-// has occurred.
+// has occurred. A connection close without GOAWAY frame of the
+// specified error code is treated as a warning, or as a failure in
+// strict mode since the error code cannot be observed.
 func VerifyConnectionError(conn *Conn, codes ...http2.ErrCode) error {
+	var actual, goAway Event
+
+	passed := false
+	closed := false
+	for !conn.Closed {
+		ev := conn.WaitEvent()
+
+		if conn.skipBackground(ev) {
+			continue
+		}
+
+		switch event := ev.(type) {
+		case ConnectionClosedEvent:
+			closed = true
+		case GoAwayFrameEvent:
+			passed = VerifyErrorCode(codes, event.ErrCode)
+			goAway = event
+		case TimeoutEvent:
+			if actual == nil {
+				actual = event
+			}
+		default:
+			actual = event
+		}
+
+		if passed || closed {
+			break
+		}
+	}
+
+	if passed {
+		return nil
+	}
+
+	if closed {
+		return verifyGoAwayOmitted(conn, goAway, codes)
+	}
+
+	expected := []string{}
+	attrs := []FrameAttributes{}
+	for _, code := range codes {
+		expected = append(expected, fmt.Sprintf(ExpectedGoAwayFrame, code))
+		attrs = append(attrs, GoAwayAttributes(code))
+	}
+	expected = append(expected, ExpectedConnectionClosed)
+	attrs = append(attrs, ClosedAttributes())
+
+	return &TestError{
+		Expected:      expected,
+		Actual:        actual.String(),
+		ExpectedAttrs: attrs,
+		ActualAttrs:   EventAttributes(actual),
+	}
+}
+
+// verifyGoAwayOmitted returns the result of the connection closed
+// without GOAWAY frame of the specified error code. The close without
+// GOAWAY frame and the close after GOAWAY frame of another error code
+// are reported as a warning, as a connection error may be handled by
+// closing the connection. In strict mode, both fail since the error
+// code is not observable.
+func verifyGoAwayOmitted(conn *Conn, goAway Event, codes []http2.ErrCode) error {
+	if !conn.Strict {
+		if event, ok := goAway.(GoAwayFrameEvent); ok {
+			return &TestWarning{
+				Reason: fmt.Sprintf("The connection was closed after %s", goAwayString(event)),
+			}
+		}
+		return &TestWarning{Reason: ReasonGoAwayOmitted}
+	}
+
+	expected := []string{}
+	attrs := []FrameAttributes{}
+	for _, code := range codes {
+		expected = append(expected, fmt.Sprintf(ExpectedGoAwayFrame, code))
+		attrs = append(attrs, GoAwayAttributes(code))
+	}
+
+	actual := ExpectedConnectionClosed
+	actualAttrs := EventAttributes(ConnectionClosedEvent{})
+	if event, ok := goAway.(GoAwayFrameEvent); ok {
+		actual = goAwayString(event)
+		actualAttrs = EventAttributes(event)
+	}
+
+	return &TestError{
+		Expected:      expected,
+		Actual:        actual,
+		ExpectedAttrs: attrs,
+		ActualAttrs:   actualAttrs,
+	}
+}
+
+// VerifyGoAwayFrame verifies whether a GOAWAY frame with specified
+// error code has received and its last stream ID is greater than or
+// equal to the specified stream ID. A connection close without GOAWAY
+// frame is treated as a warning, or as a failure in strict mode.
+func VerifyGoAwayFrame(conn *Conn, lastStreamID uint32, codes ...http2.ErrCode) error {
+	var actual Event
+	var actualStr string
+	var actualAttrs *FrameAttributes
+
+	passed := false
+	closed := false
+	for !conn.Closed {
+		ev := conn.WaitEvent()
+
+		if conn.skipBackground(ev) {
+			continue
+		}
+
+		switch event := ev.(type) {
+		case ConnectionClosedEvent:
+			closed = true
+		case GoAwayFrameEvent:
+			passed = VerifyErrorCode(codes, event.ErrCode) &&
+				event.LastStreamID >= lastStreamID
+			if !passed {
+				actualStr = goAwayString(event)
+				actualAttrs = EventAttributes(event)
+			}
+		case TimeoutEvent:
+			if actual == nil {
+				actual = event
+			}
+		default:
+			actual = event
+		}
+
+		if passed || closed || actualStr != "" {
+			break
+		}
+	}
+
+	if passed {
+		return nil
+	}
+
+	if closed && !conn.Strict {
+		return &TestWarning{Reason: ReasonGoAwayOmitted}
+	}
+
+	if actualStr == "" {
+		if closed {
+			actualStr = ExpectedConnectionClosed
+			actualAttrs = EventAttributes(ConnectionClosedEvent{})
+		} else {
+			actualStr = actual.String()
+			actualAttrs = EventAttributes(actual)
+		}
+	}
+
+	expected := []string{}
+	attrs := []FrameAttributes{}
+	for _, code := range codes {
+		expected = append(expected, fmt.Sprintf(ExpectedGoAwayFrameWithLastStreamID, lastStreamID, code))
+
+		attr := GoAwayAttributes(code)
+		attr.LastStreamID = fmt.Sprintf(">=%d", lastStreamID)
+		attrs = append(attrs, attr)
+	}
+
+	return &TestError{
+		Expected:      expected,
+		Actual:        actualStr,
+		ExpectedAttrs: attrs,
+		ActualAttrs:   actualAttrs,
+	}
+}
+
+// VerifyConnectionErrorWithoutResponse verifies whether a connection
+// error of HTTP/2 has occurred and no response has been sent for the
+// frames that followed the error. All events are accumulated until
+// the connection is closed, and any HEADERS or DATA frame on the
+// specified stream or PING frame with ACK flag fails the test. The
+// GOAWAY frame must be followed by the connection close, and the
+// connection close without GOAWAY frame is treated as described in
+// VerifyConnectionError.
+func VerifyConnectionErrorWithoutResponse(conn *Conn, streamID uint32, codes ...http2.ErrCode) error {
+	var actual, goAway Event
+	var unexpected, last Event
+
+	passed := false
+	closed := false
+	for !conn.Closed {
+		ev := conn.WaitEvent()
+		last = ev
+
+		if conn.skipBackground(ev) {
+			continue
+		}
+
+		switch event := ev.(type) {
+		case ConnectionClosedEvent:
+			closed = true
+		case GoAwayFrameEvent:
+			if VerifyErrorCode(codes, event.ErrCode) {
+				passed = true
+			} else {
+				actual = event
+				goAway = event
+			}
+		case HeadersFrameEvent:
+			if event.Header().StreamID == streamID {
+				unexpected = event
+			}
+			actual = event
+		case DataFrameEvent:
+			if event.Header().StreamID == streamID {
+				unexpected = event
+			}
+			actual = event
+		case PingFrameEvent:
+			if event.IsAck() {
+				unexpected = event
+			}
+			actual = event
+		case TimeoutEvent:
+			if actual == nil {
+				actual = event
+			}
+		default:
+			actual = event
+		}
+
+		if unexpected != nil {
+			break
+		}
+	}
+
+	if unexpected != nil {
+		return &TestError{
+			Expected: []string{"No response to the frames sent after the connection error"},
+			Actual:   unexpected.String(),
+		}
+	}
+
+	// The timeout after GOAWAY frame means that the connection has been
+	// left open, so the frames that followed may still be processed.
+	if passed && !closed {
+		return &TestError{
+			Expected:      []string{ExpectedConnectionClosed},
+			Actual:        last.String(),
+			ExpectedAttrs: []FrameAttributes{ClosedAttributes()},
+			ActualAttrs:   EventAttributes(last),
+		}
+	}
+
+	if passed {
+		return nil
+	}
+
+	if closed {
+		return verifyGoAwayOmitted(conn, goAway, codes)
+	}
+
+	expected := []string{}
+	attrs := []FrameAttributes{}
+	for _, code := range codes {
+		expected = append(expected, fmt.Sprintf(ExpectedGoAwayFrame, code))
+		attrs = append(attrs, GoAwayAttributes(code))
+	}
+	expected = append(expected, ExpectedConnectionClosed)
+	attrs = append(attrs, ClosedAttributes())
+
+	return &TestError{
+		Expected:      expected,
+		Actual:        actual.String(),
+		ExpectedAttrs: attrs,
+		ActualAttrs:   EventAttributes(actual),
+	}
+}
+
+// VerifyConnectionErrorBeforePing verifies whether a connection error
+// of HTTP/2 has occurred instead of acknowledging the PING frame that
+// has been sent as a probe. The acknowledgement of the PING frame
+// means that the endpoint has ignored the preceding frame.
+func VerifyConnectionErrorBeforePing(conn *Conn, codes ...http2.ErrCode) error {
+	return VerifyConnectionErrorWithoutResponse(conn, 0, codes...)
+}
+
+// VerifyStreamError verifies whether a stream error of HTTP/2
+// has occurred on any stream.
+func VerifyStreamError(conn *Conn, codes ...http2.ErrCode) error {
+	return VerifyStreamErrorOnStream(conn, AnyStreamID, codes...)
+}
+
+// VerifyStreamErrorOnStream verifies whether a stream error of HTTP/2
+// has occurred on the specified stream. RST_STREAM frame on another
+// stream is treated as the actual frame unless the stream identifier
+// is AnyStreamID.
+func VerifyStreamErrorOnStream(conn *Conn, streamID uint32, codes ...http2.ErrCode) error {
 	var actual Event
 
 	passed := false
 	for !conn.Closed {
 		ev := conn.WaitEvent()
 
+		if conn.skipBackground(ev) {
+			continue
+		}
+
 		switch event := ev.(type) {
 		case ConnectionClosedEvent:
 			passed = true
 		case GoAwayFrameEvent:
 			passed = VerifyErrorCode(codes, event.ErrCode)
+		case RSTStreamFrameEvent:
+			passed = matchesStream(streamID, event.StreamID) && VerifyErrorCode(codes, event.ErrCode)
+			if !passed {
+				actual = event
+			}
+		case TimeoutEvent:
+			if actual == nil {
+				actual = event
+			}
+		default:
+			actual = event
+		}
+
+		if passed {
+			break
+		}
+	}
+
+	if !passed {
+		expected := []string{}
+		attrs := []FrameAttributes{}
+		for _, code := range codes {
+			expected = append(expected, fmt.Sprintf(ExpectedGoAwayFrame, code))
+			expected = append(expected, expectedRSTStreamFrame(streamID, code))
+			attrs = append(attrs, GoAwayAttributes(code), RSTStreamAttributes(streamID, code))
+		}
+		expected = append(expected, ExpectedConnectionClosed)
+		attrs = append(attrs, ClosedAttributes())
+
+		return &TestError{
+			Expected:      expected,
+			Actual:        actual.String(),
+			ExpectedAttrs: attrs,
+			ActualAttrs:   EventAttributes(actual),
+		}
+	}
+
+	return nil
+}
+
+// matchesStream returns true if the actual stream identifier is the
+// expected one, or the expected one is AnyStreamID.
+func matchesStream(expected, actual uint32) bool {
+	return expected == AnyStreamID || expected == actual
+}
+
+// expectedRSTStreamFrame returns the expected RST_STREAM frame with the
+// error code on the specified stream.
+func expectedRSTStreamFrame(streamID uint32, code http2.ErrCode) string {
+	if streamID == AnyStreamID {
+		return fmt.Sprintf(ExpectedRSTStreamFrame, code)
+	}
+	return fmt.Sprintf("RST_STREAM Frame (stream_id:%d, Error Code: %s)", streamID, code)
+}
+
+// VerifyStreamErrorOrConnectionError verifies whether a stream error
+// of HTTP/2 has occurred. The escalation to a connection error with
+// the same error code is also accepted, and the observed behavior is
+// reported. RST_STREAM or GOAWAY frame of another error code fails the
+// test. A connection close without either frame is treated as a
+// warning, or as a failure in strict mode since the error code cannot
+// be observed.
+func VerifyStreamErrorOrConnectionError(conn *Conn, codes ...http2.ErrCode) error {
+	var actual Event
+	var observed string
+
+	closed := false
+	for !conn.Closed {
+		ev := conn.WaitEvent()
+
+		if conn.skipBackground(ev) {
+			continue
+		}
+
+		switch event := ev.(type) {
+		case ConnectionClosedEvent:
+			closed = true
+		case GoAwayFrameEvent:
+			if VerifyErrorCode(codes, event.ErrCode) {
+				observed = fmt.Sprintf("Connection error: %s", goAwayString(event))
+			} else {
+				actual = event
+			}
+		case RSTStreamFrameEvent:
+			if VerifyErrorCode(codes, event.ErrCode) {
+				observed = fmt.Sprintf("Stream error: %s", rstStreamString(event))
+			} else {
+				actual = event
+			}
+		case TimeoutEvent:
+			if actual == nil {
+				actual = event
+			}
+		default:
+			actual = event
+		}
+
+		if observed != "" || closed {
+			break
+		}
+	}
+
+	if observed != "" {
+		return &TestInfo{Message: observed}
+	}
+
+	_, goAway := actual.(GoAwayFrameEvent)
+	_, rstStream := actual.(RSTStreamFrameEvent)
+	if closed && !goAway && !rstStream {
+		if !conn.Strict {
+			return &TestWarning{Reason: ReasonStreamErrorOmitted}
+		}
+		actual = ConnectionClosedEvent{}
+	}
+
+	expected := []string{}
+	for _, code := range codes {
+		expected = append(expected, fmt.Sprintf(ExpectedGoAwayFrame, code))
+		expected = append(expected, fmt.Sprintf(ExpectedRSTStreamFrame, code))
+	}
+
+	return &TestError{
+		Expected: expected,
+		Actual:   actual.String(),
+	}
+}
+
+// VerifyMalformedRequest verifies whether the request on the specified
+// stream was treated as malformed. Besides a stream error or connection
+// error of type PROTOCOL_ERROR, a response with 4xx status code is
+// accepted since the endpoint MAY send a HTTP response prior to
+// resetting the stream. The observed behavior is reported. A response
+// with 101 status code fails the verification at once, since HTTP/2
+// has no upgrade mechanism to switch to.
+func VerifyMalformedRequest(conn *Conn, streamID uint32) error {
+	var actual Event
+	var actualStr string
+	var actualAttrs *FrameAttributes
+	var observed string
+
+	passed := false
+	upgraded := false
+	for !conn.Closed {
+		ev := conn.WaitEvent()
+
+		if conn.skipBackground(ev) {
+			continue
+		}
+
+		switch event := ev.(type) {
+		case ConnectionClosedEvent:
+			passed = true
+			observed = "Connection closed"
+		case GoAwayFrameEvent:
+			passed = (event.ErrCode == http2.ErrCodeProtocol)
+			observed = fmt.Sprintf("Connection error: %s", goAwayString(event))
+			actual = event
+		case RSTStreamFrameEvent:
+			passed = event.StreamID == streamID && event.ErrCode == http2.ErrCodeProtocol
+			observed = fmt.Sprintf("Stream error: %s", rstStreamString(event))
+			actual = event
+		case HeadersFrameEvent:
+			if event.Header().StreamID == streamID && event.HeadersEnded() {
+				passed = isClientErrorStatus(event.Fields)
+				upgraded = isSwitchingProtocolsStatus(event.Fields)
+				actualStr = headersString(event.Header(), event.Fields)
+				observed = fmt.Sprintf("Error response: %s", actualStr)
+				actualAttrs = EventAttributes(event)
+			}
+			actual = event
+		case ContinuationFrameEvent:
+			if event.Header().StreamID == streamID && event.HeadersEnded() {
+				passed = isClientErrorStatus(event.Fields)
+				upgraded = isSwitchingProtocolsStatus(event.Fields)
+				actualStr = headersString(event.Header(), event.Fields)
+				observed = fmt.Sprintf("Error response: %s", actualStr)
+				actualAttrs = EventAttributes(event)
+			}
+			actual = event
 		case TimeoutEvent:
 			if actual == nil {
 				actual = event
@@ -70,132 +574,1204 @@ func VerifyConnectionError(conn *Conn, codes ...http2.ErrCode) error {
 			actual = event
 		}
 
-		if passed {
-			break
+		if passed || upgraded {
+			break
+		}
+	}
+
+	if !passed {
+		code := http2.ErrCodeProtocol
+		expected := []string{
+			fmt.Sprintf(ExpectedGoAwayFrame, code),
+			expectedRSTStreamFrame(streamID, code),
+			fmt.Sprintf(ExpectedClientErrorResponse, streamID),
+			ExpectedConnectionClosed,
+		}
+
+		attrs := []FrameAttributes{
+			GoAwayAttributes(code),
+			RSTStreamAttributes(streamID, code),
+			{Type: http2.FrameHeaders.String(), StreamID: fmt.Sprintf("%d", streamID)},
+			ClosedAttributes(),
+		}
+
+		if actualStr == "" {
+			actualStr = actual.String()
+			actualAttrs = EventAttributes(actual)
+		}
+
+		return &TestError{
+			Expected:      expected,
+			Actual:        actualStr,
+			ExpectedAttrs: attrs,
+			ActualAttrs:   actualAttrs,
+		}
+	}
+
+	return &TestInfo{Message: observed}
+}
+
+// VerifyStreamClose verifies whether a stream close of HTTP/2
+// has occurred.
+func VerifyStreamClose(conn *Conn) error {
+	var actual Event
+
+	passed := false
+	for !conn.Closed {
+		ev := conn.WaitEvent()
+
+		if conn.skipBackground(ev) {
+			continue
+		}
+
+		switch event := ev.(type) {
+		case DataFrameEvent:
+			if event.StreamEnded() {
+				passed = true
+			}
+		case HeadersFrameEvent:
+			if event.StreamEnded() {
+				passed = true
+			}
+		case RSTStreamFrameEvent:
+			if event.ErrCode == http2.ErrCodeNo {
+				passed = true
+			}
+		case TimeoutEvent:
+			if actual == nil {
+				actual = event
+			}
+		default:
+			actual = event
+		}
+
+		if passed {
+			break
+		}
+	}
+
+	if !passed {
+		return &TestError{
+			Expected: []string{ExpectedStreamClosed},
+			Actual:   actual.String(),
+		}
+	}
+
+	return nil
+}
+
+// VerifyHeadersFrame verifies whether a HEADERS frame with specified
+// stream ID has received.
+func VerifyHeadersFrame(conn *Conn, streamID uint32) error {
+	actual, passed := conn.WaitEventByType(EventHeadersFrame)
+	switch event := actual.(type) {
+	case HeadersFrameEvent:
+		passed = (event.Header().StreamID == streamID)
+	default:
+		passed = false
+	}
+
+	if !passed {
+		expected := []string{
+			fmt.Sprintf("HEADERS Frame (stream_id:%d)", streamID),
+		}
+
+		return &TestError{
+			Expected: expected,
+			Actual:   actual.String(),
+		}
+	}
+
+	return nil
+}
+
+// VerifyHeaderBlockDecoding verifies whether a header block on the
+// specified stream has received and decoded without decoding error.
+// If tableSizeUpdate is true, the header block must begin with a
+// dynamic table size update.
+func VerifyHeaderBlockDecoding(conn *Conn, streamID uint32, tableSizeUpdate bool) error {
+	var actual Event
+
+	expected := []string{
+		fmt.Sprintf("HEADERS Frame (stream_id:%d) decoded successfully", streamID),
+	}
+	if tableSizeUpdate {
+		expected[0] = fmt.Sprintf("HEADERS Frame (stream_id:%d) beginning with a dynamic table size update", streamID)
+	}
+
+	started := false
+	for !conn.Closed {
+		ev := conn.WaitEvent()
+		actual = ev
+
+		var decodeErr error
+		ended := false
+
+		switch event := ev.(type) {
+		case HeadersFrameEvent:
+			if event.Header().StreamID != streamID {
+				continue
+			}
+
+			block := event.HeaderBlockFragment()
+			if tableSizeUpdate && (len(block) == 0 || block[0]&0xe0 != 0x20) {
+				return &TestError{
+					Expected: expected,
+					Actual:   fmt.Sprintf("HEADERS Frame (stream_id:%d) without dynamic table size update", streamID),
+				}
+			}
+
+			started = true
+			decodeErr = event.DecodeError
+			ended = event.HeadersEnded()
+		case ContinuationFrameEvent:
+			if !started || event.Header().StreamID != streamID {
+				continue
+			}
+
+			decodeErr = event.DecodeError
+			ended = event.HeadersEnded()
+		default:
+			continue
+		}
+
+		if decodeErr != nil {
+			return &TestError{
+				Expected: expected,
+				Actual:   fmt.Sprintf("Header block decoding error: %v", decodeErr),
+			}
+		}
+
+		if ended {
+			return nil
+		}
+	}
+
+	if actual == nil {
+		actual = ConnectionClosedEvent{}
+	}
+
+	return &TestError{
+		Expected: expected,
+		Actual:   actual.String(),
+	}
+}
+
+// VerifyNoPushPromise verifies whether the response on the specified
+// stream has completed without receiving any PUSH_PROMISE frame.
+func VerifyNoPushPromise(conn *Conn, streamID uint32) error {
+	var actual Event
+
+	passed := false
+	for !conn.Closed {
+		ev := conn.WaitEvent()
+
+		switch event := ev.(type) {
+		case PushPromiseFrameEvent:
+			actual = event
+		case DataFrameEvent:
+			if event.Header().StreamID == streamID {
+				passed = event.StreamEnded()
+			}
+		case HeadersFrameEvent:
+			if event.Header().StreamID == streamID {
+				passed = event.StreamEnded()
+			}
+		case TimeoutEvent:
+			if actual == nil {
+				actual = event
+			}
+		default:
+			actual = event
+		}
+
+		if passed || actual != nil && actual.Type() == EventPushPromiseFrame {
+			break
+		}
+	}
+
+	if !passed {
+		return &TestError{
+			Expected: []string{
+				fmt.Sprintf("Response on stream %d without PUSH_PROMISE Frame", streamID),
+			},
+			Actual: actual.String(),
+		}
+	}
+
+	return nil
+}
+
+// VerifyStreamResponse verifies whether a complete response with the
+// :status pseudo-header field has received on the specified stream.
+func VerifyStreamResponse(conn *Conn, streamID uint32) error {
+	var actual Event
+
+	status := false
+	completed := false
+	for !conn.Closed {
+		ev := conn.WaitEvent()
+
+		failed := false
+		switch event := ev.(type) {
+		case HeadersFrameEvent:
+			if event.Header().StreamID == streamID {
+				_, ok := headerFieldValue(event.Fields, ":status")
+				status = status || (event.HeadersEnded() && ok)
+				completed = status && event.StreamEnded()
+			}
+		case ContinuationFrameEvent:
+			if event.Header().StreamID == streamID {
+				_, ok := headerFieldValue(event.Fields, ":status")
+				status = status || (event.HeadersEnded() && ok)
+			}
+		case DataFrameEvent:
+			if event.Header().StreamID == streamID {
+				completed = status && event.StreamEnded()
+			}
+		case RSTStreamFrameEvent:
+			failed = (event.Header().StreamID == streamID)
+		case GoAwayFrameEvent:
+			failed = (event.LastStreamID < streamID)
+		case TimeoutEvent:
+			if actual != nil {
+				continue
+			}
+		}
+
+		actual = ev
+
+		if completed || failed {
+			break
+		}
+	}
+
+	if !completed {
+		return &TestError{
+			Expected: []string{
+				fmt.Sprintf("HEADERS Frame (stream_id:%d, :status:xxx)", streamID),
+				fmt.Sprintf("DATA Frame (flags:0x01, stream_id:%d)", streamID),
+			},
+			Actual: actual.String(),
+		}
+	}
+
+	return nil
+}
+
+// VerifyMultiplexedResponses verifies whether a complete response has
+// been received on each of the specified streams, as described in
+// MultiplexedResponses.
+func VerifyMultiplexedResponses(conn *Conn, streamIDs ...uint32) error {
+	return ExpectFrame(conn, MultiplexedResponses(streamIDs...))
+}
+
+// VerifyTrailers verifies whether the response on the specified stream
+// ends with the trailers, as described in Trailers.
+func VerifyTrailers(conn *Conn, streamID uint32) error {
+	return ExpectFrame(conn, Trailers(streamID))
+}
+
+// VerifyHeadResponse verifies whether the response to HEAD request on
+// the specified stream has been received without payload, as described
+// in HeadResponse.
+func VerifyHeadResponse(conn *Conn, streamID uint32) error {
+	return ExpectFrame(conn, HeadResponse(streamID))
+}
+
+// VerifyResponseContentLength verifies whether the payload of the
+// response on the specified stream matches the content-length header
+// field of the response, as described in ResponseContentLength.
+func VerifyResponseContentLength(conn *Conn, streamID uint32) error {
+	return ExpectFrame(conn, ResponseContentLength(streamID))
+}
+
+// connectionSpecificFields is the connection-specific header fields that
+// must not appear in HTTP/2 messages.
+var connectionSpecificFields = []string{
+	"connection",
+	"keep-alive",
+	"proxy-connection",
+	"transfer-encoding",
+	"upgrade",
+}
+
+// VerifyNoConnectionSpecificResponse verifies whether the response on
+// the specified stream has been received without 101 status code and
+// without connection-specific header fields.
+func VerifyNoConnectionSpecificResponse(conn *Conn, streamID uint32) error {
+	return ExpectFrame(conn, NoConnectionSpecificResponse(streamID))
+}
+
+// VerifyStatus verifies whether the final response on the specified
+// stream has one of the status codes, as described in Status.
+func VerifyStatus(conn *Conn, streamID uint32, statuses ...string) error {
+	return ExpectFrame(conn, Status(streamID, statuses...))
+}
+
+// VerifyEchoedHeaderField verifies whether the response of the echo
+// endpoint contains the specified header field as a response header
+// field or as a line of the response body.
+func VerifyEchoedHeaderField(conn *Conn, streamID uint32, name, value string) error {
+	res, actual := ReadResponse(conn, streamID)
+	if !res.Ended {
+		return &TestError{
+			Expected: []string{
+				fmt.Sprintf("HEADERS Frame (stream_id:%d, :status:xxx)", streamID),
+				fmt.Sprintf("DATA Frame (flags:0x01, stream_id:%d)", streamID),
+			},
+			Actual: actual.String(),
+		}
+	}
+
+	echoed := "(none)"
+	for _, v := range res.EchoedValues(name) {
+		if v == value {
+			return nil
+		}
+		echoed = v
+	}
+
+	return &TestError{
+		Expected: []string{
+			fmt.Sprintf("Echoed header field (%s: %s)", name, value),
+		},
+		Actual: fmt.Sprintf("Echoed header field (%s: %s)", name, echoed),
+	}
+}
+
+// VerifyInterimResponse verifies whether the informational responses
+// received on the stream are followed by a final response. The result
+// describes the observed responses, and is TestSkipped if the server
+// sent no interim response, so that the case is not counted as passed
+// without being exercised.
+func VerifyInterimResponse(conn *Conn, res *Response) error {
+	actual := res.ReadAll(conn)
+
+	if res.Ended && res.Headers == nil && len(res.Interim) > 0 {
+		status, _ := headerFieldValue(res.Interim[len(res.Interim)-1], ":status")
+		return &TestError{
+			Expected: []string{
+				fmt.Sprintf("HEADERS Frame (flags:0x04, stream_id:%d, :status:1xx)", res.StreamID),
+				fmt.Sprintf("HEADERS Frame (stream_id:%d, :status:xxx)", res.StreamID),
+			},
+			Actual: fmt.Sprintf("HEADERS Frame (flags:0x05, stream_id:%d, :status:%s)", res.StreamID, status),
+		}
+	}
+
+	if !res.Ended || res.Headers == nil {
+		return &TestError{
+			Expected: []string{
+				fmt.Sprintf("HEADERS Frame (stream_id:%d, :status:xxx)", res.StreamID),
+				fmt.Sprintf("DATA Frame (flags:0x01, stream_id:%d)", res.StreamID),
+			},
+			Actual: actual.String(),
+		}
+	}
+
+	if len(res.Interim) == 0 {
+		return &TestSkipped{
+			Reason: fmt.Sprintf("Not exercised: no interim response (final :status:%s)", res.Status()),
+		}
+	}
+
+	interim := []string{}
+	for _, fields := range res.Interim {
+		status, _ := headerFieldValue(fields, ":status")
+		interim = append(interim, status)
+	}
+
+	return &TestInfo{
+		Message: fmt.Sprintf("Interim response (:status:%s), final response (:status:%s)", strings.Join(interim, ", "), res.Status()),
+	}
+}
+
+// VerifyExpectContinue verifies whether the server reacted to the
+// request with "expect: 100-continue" header field while the client
+// withheld the body. The event is the last one received while waiting
+// for the reaction. An interim response, a final response and a stream
+// error are all acceptable, and the result describes which one was
+// observed.
+func VerifyExpectContinue(conn *Conn, res *Response, ev Event) error {
+	if len(res.Interim) > 0 {
+		return VerifyInterimResponse(conn, res)
+	}
+
+	if res.Headers != nil {
+		res.ReadAll(conn)
+		return &TestInfo{
+			Message: fmt.Sprintf("Final response (:status:%s)", res.Status()),
+		}
+	}
+
+	rst, ok := ev.(RSTStreamFrameEvent)
+	if ok && rst.Header().StreamID == res.StreamID {
+		return &TestInfo{
+			Message: fmt.Sprintf("Stream error: %s", rstStreamString(rst)),
+		}
+	}
+
+	return &TestError{
+		Expected: []string{
+			fmt.Sprintf("HEADERS Frame (stream_id:%d, :status:100)", res.StreamID),
+			fmt.Sprintf("HEADERS Frame (stream_id:%d, :status:xxx)", res.StreamID),
+			fmt.Sprintf("RST_STREAM Frame (stream_id:%d)", res.StreamID),
+		},
+		Actual: ev.String(),
+	}
+}
+
+// VerifyConnectRequest verifies whether the server reacted to the
+// CONNECT request without treating it as malformed. The event is the
+// last one received while waiting for the reaction. Establishing the
+// tunnel, an error response and a stream error other than
+// PROTOCOL_ERROR are all acceptable, and the result describes which
+// one was observed. The response without a valid ":status"
+// pseudo-header field fails the test.
+func VerifyConnectRequest(conn *Conn, res *Response, ev Event) error {
+	if res.Headers != nil {
+		status := res.Status()
+		if !isValidStatus(status) {
+			if status == "" {
+				status = "(none)"
+			}
+			return &TestError{
+				Expected: []string{
+					fmt.Sprintf("HEADERS Frame (stream_id:%d, :status:xxx)", res.StreamID),
+				},
+				Actual: fmt.Sprintf("HEADERS Frame (stream_id:%d, :status:%s)", res.StreamID, status),
+			}
+		}
+
+		if status[0] == '2' && !res.Ended {
+			return &TestInfo{
+				Message: fmt.Sprintf("Tunnel established (:status:%s)", status),
+			}
+		}
+
+		res.ReadAll(conn)
+		if status[0] == '2' {
+			return &TestInfo{
+				Message: fmt.Sprintf("Tunnel closed (:status:%s)", status),
+			}
+		}
+		return &TestInfo{
+			Message: fmt.Sprintf("Request refused (:status:%s)", status),
+		}
+	}
+
+	rst, ok := ev.(RSTStreamFrameEvent)
+	if ok && rst.Header().StreamID == res.StreamID && rst.ErrCode != http2.ErrCodeProtocol {
+		return &TestInfo{
+			Message: fmt.Sprintf("Request refused: %s", rstStreamString(rst)),
+		}
+	}
+
+	return &TestError{
+		Expected: []string{
+			fmt.Sprintf("HEADERS Frame (stream_id:%d, :status:2xx)", res.StreamID),
+			fmt.Sprintf("HEADERS Frame (stream_id:%d, :status:xxx)", res.StreamID),
+			fmt.Sprintf("RST_STREAM Frame (stream_id:%d, error_code:other than PROTOCOL_ERROR)", res.StreamID),
+		},
+		Actual: ev.String(),
+	}
+}
+
+// VerifyNoErrorBeforePing verifies whether the PING frame with ACK
+// flag is received without any stream error on the specified stream
+// or connection error.
+func VerifyNoErrorBeforePing(conn *Conn, streamID uint32, data [8]byte) error {
+	var actual Event
+
+	passed := false
+	for !conn.Closed {
+		ev := conn.WaitEvent()
+
+		if conn.skipBackground(ev) {
+			continue
+		}
+
+		failed := false
+		switch event := ev.(type) {
+		case PingFrameEvent:
+			passed = event.IsAck() && reflect.DeepEqual(event.Data, data)
+		case RSTStreamFrameEvent:
+			failed = (event.Header().StreamID == streamID)
+		case GoAwayFrameEvent:
+			failed = true
+		case TimeoutEvent:
+			if actual != nil {
+				continue
+			}
+		}
+
+		actual = ev
+
+		if passed || failed {
+			break
+		}
+	}
+
+	if !passed {
+		return &TestError{
+			Expected: []string{
+				fmt.Sprintf("PING Frame (length:8, flags:0x01, stream_id:0, opaque_data:%s)", data),
+			},
+			Actual: actual.String(),
+		}
+	}
+
+	return nil
+}
+
+// VerifyAuthorityMismatch verifies whether the server treated the
+// request that contains the host header field differing from the
+// ":authority" pseudo-header field as malformed. If the echo endpoint
+// is used, the host echoed by the endpoint is also verified. Accepting
+// the request using the value of ":authority" is treated as a warning,
+// or as a failure in strict mode.
+func VerifyAuthorityMismatch(conn *Conn, streamID uint32, authority string, echo bool) error {
+	res, actual := ReadResponse(conn, streamID)
+
+	observed := ""
+	switch event := actual.(type) {
+	case ConnectionClosedEvent:
+		observed = "Connection closed"
+	case GoAwayFrameEvent:
+		if event.ErrCode == http2.ErrCodeProtocol {
+			observed = fmt.Sprintf("Connection error: %s", goAwayString(event))
+		}
+	case RSTStreamFrameEvent:
+		if event.Header().StreamID == streamID && event.ErrCode == http2.ErrCodeProtocol {
+			observed = fmt.Sprintf("Stream error: %s", rstStreamString(event))
+		}
+	}
+	if res.Headers != nil && isClientErrorStatus(res.Headers) {
+		observed = fmt.Sprintf("Error response (:status:%s)", res.Status())
+	}
+
+	if observed != "" {
+		return &TestInfo{Message: observed}
+	}
+
+	expected := []string{
+		fmt.Sprintf(ExpectedGoAwayFrame, http2.ErrCodeProtocol),
+		fmt.Sprintf(ExpectedRSTStreamFrame, http2.ErrCodeProtocol),
+		fmt.Sprintf(ExpectedClientErrorResponse, streamID),
+		ExpectedConnectionClosed,
+	}
+
+	if !res.Ended {
+		return &TestError{
+			Expected: expected,
+			Actual:   actual.String(),
+		}
+	}
+
+	observed = fmt.Sprintf("Accepted (:status:%s)", res.Status())
+	if echo {
+		host := "(none)"
+		for _, v := range res.EchoedValues("host") {
+			host = v
+		}
+
+		if host != authority {
+			return &TestError{
+				Expected: []string{
+					fmt.Sprintf("Echoed header field (host: %s)", authority),
+				},
+				Actual: fmt.Sprintf("Echoed header field (host: %s)", host),
+			}
+		}
+
+		observed = fmt.Sprintf("%s using \":authority\" (host: %s)", observed, host)
+	}
+
+	if !conn.Strict {
+		return &TestWarning{Reason: observed}
+	}
+
+	return &TestError{
+		Expected: expected,
+		Actual:   observed,
+	}
+}
+
+// VerifyOversizedHeaderList verifies whether the server handled the
+// request with the header list exceeding its limit without stalling
+// the connection, and answered the following request sent on the same
+// connection. Rejecting the request with a 4xx response, a stream
+// error or a connection error, and accepting the request are reported
+// as the observed result.
+func VerifyOversizedHeaderList(conn *Conn, streamID, nextStreamID uint32) error {
+	var actual, rst Event
+
+	res := NewResponse(streamID)
+	next := NewResponse(nextStreamID)
+	done := func(r *Response) bool {
+		return r.Ended || r.stopped
+	}
+
+	for !conn.Closed && !(done(res) && done(next)) {
+		ev := conn.WaitEvent()
+		if _, ok := ev.(TimeoutEvent); ok && actual != nil {
+			continue
+		}
+
+		switch event := ev.(type) {
+		case GoAwayFrameEvent:
+			if event.ErrCode != http2.ErrCodeNo {
+				return &TestInfo{
+					Message: fmt.Sprintf("Connection error: %s", goAwayString(event)),
+				}
+			}
+			if event.LastStreamID < nextStreamID {
+				return &TestInfo{
+					Message: fmt.Sprintf("Connection closed: %s", goAwayString(event)),
+				}
+			}
+		case RSTStreamFrameEvent:
+			if event.Header().StreamID == streamID {
+				rst = event
+			}
+		}
+
+		res.add(ev)
+		next.add(ev)
+		actual = ev
+	}
+
+	observed := ""
+	switch {
+	case rst != nil:
+		observed = fmt.Sprintf("Stream error: %s", rst)
+	case res.Headers != nil && isClientErrorStatus(res.Headers):
+		observed = fmt.Sprintf("Error response (:status:%s)", res.Status())
+	case res.Ended:
+		observed = fmt.Sprintf("Accepted (:status:%s)", res.Status())
+	default:
+		return &TestError{
+			Expected: []string{
+				fmt.Sprintf(ExpectedClientErrorResponse, streamID),
+				fmt.Sprintf("RST_STREAM Frame (stream_id:%d)", streamID),
+				"GOAWAY Frame",
+			},
+			Actual: actual.String(),
+		}
+	}
+
+	if next.Headers == nil || !next.Ended {
+		return &TestError{
+			Expected: []string{
+				fmt.Sprintf("HEADERS Frame (stream_id:%d, :status:xxx)", nextStreamID),
+				fmt.Sprintf("DATA Frame (flags:0x01, stream_id:%d)", nextStreamID),
+			},
+			Actual: actual.String(),
+		}
+	}
+
+	return &TestInfo{
+		Message: fmt.Sprintf("%s, then the next request answered (:status:%s)", observed, next.Status()),
+	}
+}
+
+// VerifyAltSvcFrames verifies whether the ALTSVC frames received until
+// the response on the specified stream has completed are valid. An
+// ALTSVC frame on stream 0 must contain the origin, and one on the
+// other streams must not. The field value must conform to the syntax
+// of Alt-Svc header field. The number of verified frames is reported.
+func VerifyAltSvcFrames(conn *Conn, streamID uint32) error {
+	return verifyExtensionFrames(conn, streamID, FrameAltSvc, verifyAltSvcFrame)
+}
+
+// VerifyOriginFrames verifies whether the ORIGIN frames received until
+// the response on the specified stream has completed are valid. An
+// ORIGIN frame must be sent on stream 0 and contain a sequence of
+// Origin-Entry. The number of verified frames is reported.
+func VerifyOriginFrames(conn *Conn, streamID uint32) error {
+	return verifyExtensionFrames(conn, streamID, FrameOrigin, verifyOriginFrame)
+}
+
+// verifyExtensionFrames verifies the frames of the specified extension
+// type with verify until the response on the specified stream has
+// completed.
+func verifyExtensionFrames(conn *Conn, streamID uint32, t http2.FrameType, verify func(UnknownFrameEvent) error) error {
+	var actual Event
+
+	received := 0
+	res := NewResponse(streamID)
+	for !conn.Closed && !res.Ended && !res.stopped {
+		ev := conn.WaitEvent()
+		if _, ok := ev.(TimeoutEvent); ok && actual != nil {
+			continue
+		}
+
+		event, ok := ev.(UnknownFrameEvent)
+		if ok && event.Header().Type == t {
+			err := verify(event)
+			if err != nil {
+				return err
+			}
+			received++
+		}
+
+		res.add(ev)
+		actual = ev
+	}
+
+	if actual == nil {
+		actual = ConnectionClosedEvent{}
+	}
+
+	if !res.Ended {
+		return &TestError{
+			Expected: []string{
+				fmt.Sprintf("HEADERS Frame (stream_id:%d, :status:xxx)", streamID),
+				fmt.Sprintf("DATA Frame (flags:0x01, stream_id:%d)", streamID),
+			},
+			Actual: actual.String(),
+		}
+	}
+
+	name := extensionFrameName[t]
+	if received == 0 {
+		return &TestInfo{Message: fmt.Sprintf("Not exercised: no %s frame received", name)}
+	}
+
+	return &TestInfo{Message: fmt.Sprintf("%d %s frame(s) verified", received, name)}
+}
+
+// verifyAltSvcFrame verifies the payload of the specified ALTSVC
+// frame.
+func verifyAltSvcFrame(ev UnknownFrameEvent) error {
+	streamID := ev.Header().StreamID
+
+	f, err := ParseAltSvcFrame(ev)
+	if err != nil {
+		return &TestError{
+			Expected: []string{
+				fmt.Sprintf("ALTSVC Frame (stream_id:%d) with valid Origin-Len", streamID),
+			},
+			Actual: fmt.Sprintf("%s: %v", ev, err),
+		}
+	}
+
+	if streamID == 0 && f.Origin == "" {
+		return &TestError{
+			Expected: []string{"ALTSVC Frame (stream_id:0, origin:non-empty)"},
+			Actual:   "ALTSVC Frame (stream_id:0, origin:empty)",
+		}
+	}
+
+	if streamID != 0 && f.Origin != "" {
+		return &TestError{
+			Expected: []string{
+				fmt.Sprintf("ALTSVC Frame (stream_id:%d, origin:empty)", streamID),
+			},
+			Actual: fmt.Sprintf("ALTSVC Frame (stream_id:%d, origin:%s)", streamID, f.Origin),
+		}
+	}
+
+	if !ValidAltSvcFieldValue(f.FieldValue) {
+		return &TestError{
+			Expected: []string{
+				fmt.Sprintf("ALTSVC Frame (stream_id:%d) with valid Alt-Svc field value", streamID),
+			},
+			Actual: fmt.Sprintf("ALTSVC Frame (stream_id:%d, field_value:%q)", streamID, f.FieldValue),
+		}
+	}
+
+	return nil
+}
+
+// verifyOriginFrame verifies the stream and the payload of the
+// specified ORIGIN frame.
+func verifyOriginFrame(ev UnknownFrameEvent) error {
+	streamID := ev.Header().StreamID
+	if streamID != 0 {
+		return &TestError{
+			Expected: []string{"ORIGIN Frame (stream_id:0)"},
+			Actual:   ev.String(),
+		}
+	}
+
+	_, err := ParseOriginFrame(ev)
+	if err != nil {
+		return &TestError{
+			Expected: []string{"ORIGIN Frame (stream_id:0) with valid Origin-Entry sequence"},
+			Actual:   fmt.Sprintf("%s: %v", ev, err),
+		}
+	}
+
+	return nil
+}
+
+// VerifyWebSocketHandshake verifies whether the server accepted the
+// extended CONNECT request to bootstrap the WebSocket protocol with
+// 200 response, keeping the stream open. The event is the last one
+// received while waiting for the response.
+func VerifyWebSocketHandshake(conn *Conn, res *Response, ev Event) error {
+	if res.Headers != nil && res.Status() == "200" && !res.Ended {
+		return nil
+	}
+
+	actual := ev.String()
+	if res.Headers != nil {
+		actual = fmt.Sprintf("HEADERS Frame (stream_id:%d, :status:%s)", res.StreamID, res.Status())
+		if res.Ended {
+			actual = fmt.Sprintf("%s, stream ended", actual)
+		}
+	}
+
+	return &TestError{
+		Expected: []string{
+			fmt.Sprintf("HEADERS Frame (stream_id:%d, :status:200)", res.StreamID),
+		},
+		Actual: actual,
+	}
+}
+
+// VerifyWebSocketClose verifies whether the server echoed the text
+// message or closed the WebSocket connection cleanly after receiving
+// the text frame and the close frame on the stream of the specified
+// response. A stream error or connection error fails the verification.
+// The observed behavior is reported.
+func VerifyWebSocketClose(conn *Conn, res *Response, text string) error {
+	var actual Event
+	var buf []byte
+
+	echoed := false
+	closed := ""
+	for !conn.Closed && !res.Ended && !res.stopped && closed == "" {
+		ev := conn.WaitEvent()
+		if _, ok := ev.(TimeoutEvent); ok && actual != nil {
+			continue
+		}
+
+		switch event := ev.(type) {
+		case DataFrameEvent:
+			if event.Header().StreamID == res.StreamID {
+				buf = append(buf, event.Data()...)
+			}
+		case RSTStreamFrameEvent:
+			code := event.ErrCode
+			if event.Header().StreamID == res.StreamID && code != http2.ErrCodeNo && code != http2.ErrCodeCancel {
+				return &TestError{
+					Expected: []string{
+						fmt.Sprintf("RST_STREAM Frame (Error Code: %s)", http2.ErrCodeNo),
+						fmt.Sprintf("RST_STREAM Frame (Error Code: %s)", http2.ErrCodeCancel),
+					},
+					Actual: event.String(),
+				}
+			}
+		case GoAwayFrameEvent:
+			if event.ErrCode != http2.ErrCodeNo {
+				return &TestError{
+					Expected: []string{
+						fmt.Sprintf(ExpectedGoAwayFrame, http2.ErrCodeNo),
+					},
+					Actual: goAwayString(event),
+				}
+			}
+		}
+
+		res.add(ev)
+		actual = ev
+
+		frames, rest, err := ParseWebSocketFrames(buf)
+		if err != nil {
+			return &TestError{
+				Expected: []string{"DATA Frames containing valid WebSocket frames"},
+				Actual:   err.Error(),
+			}
+		}
+		buf = rest
+
+		for _, f := range frames {
+			if f.Masked {
+				return &TestError{
+					Expected: []string{"WebSocket frame without mask"},
+					Actual:   fmt.Sprintf("Masked WebSocket frame (opcode:0x%x)", f.Opcode),
+				}
+			}
+
+			switch f.Opcode {
+			case WebSocketOpcodeText:
+				echoed = echoed || string(f.Payload) == text
+			case WebSocketOpcodeClose:
+				closed = "close frame received"
+				if len(f.Payload) >= 2 {
+					closed = fmt.Sprintf("%s (status code:%d)", closed, binary.BigEndian.Uint16(f.Payload))
+				}
+			}
+		}
+	}
+
+	if actual == nil {
+		actual = ConnectionClosedEvent{}
+	}
+
+	observed := []string{}
+	if echoed {
+		observed = append(observed, "text frame echoed")
+	}
+	if closed != "" {
+		observed = append(observed, closed)
+	}
+	if res.Ended || res.stopped {
+		observed = append(observed, "stream closed")
+	}
+
+	if len(observed) == 0 {
+		return &TestError{
+			Expected: []string{
+				"DATA Frame containing the echoed text frame",
+				"DATA Frame containing a close frame",
+				fmt.Sprintf("DATA Frame (flags:0x01, stream_id:%d)", res.StreamID),
+			},
+			Actual: actual.String(),
+		}
+	}
+
+	message := strings.Join(observed, ", ")
+	return &TestInfo{Message: strings.ToUpper(message[:1]) + message[1:]}
+}
+
+// VerifyFloodResilience verifies whether the server still answers the
+// request on the specified stream after the flood, or defended itself
+// by resetting the stream or terminating the connection deliberately
+// with GOAWAY frame of ENHANCE_YOUR_CALM or NO_ERROR. The other
+// results such as the connection close without GOAWAY frame and the
+// timeout are reported as a warning. The elapsed time since the flood
+// has started is included in the result.
+func VerifyFloodResilience(conn *Conn, streamID uint32, started time.Time, metrics string) error {
+	var actual Event
+
+	res := NewResponse(streamID)
+	for !conn.Closed && !res.Ended && !res.stopped {
+		ev := conn.WaitEvent()
+		if _, ok := ev.(TimeoutEvent); ok {
+			actual = ev
+			break
+		}
+
+		elapsed := time.Since(started).Round(time.Millisecond)
+
+		switch event := ev.(type) {
+		case GoAwayFrameEvent:
+			observed := fmt.Sprintf("%s (%s, %s elapsed)", goAwayString(event), metrics, elapsed)
+
+			if event.ErrCode == http2.ErrCodeEnhanceYourCalm || event.ErrCode == http2.ErrCodeNo {
+				return &TestInfo{Message: fmt.Sprintf("Terminated deliberately: %s", observed)}
+			}
+			return &TestWarning{Reason: fmt.Sprintf("Terminated with error: %s", observed)}
+		case RSTStreamFrameEvent:
+			if event.Header().StreamID == streamID {
+				return &TestInfo{
+					Message: fmt.Sprintf("Stream reset: %s (%s, %s elapsed)", event, metrics, elapsed),
+				}
+			}
+		}
+
+		res.add(ev)
+		actual = ev
+	}
+
+	elapsed := time.Since(started).Round(time.Millisecond)
+	if res.Ended {
+		return &TestInfo{
+			Message: fmt.Sprintf("Request answered (:status:%s, %s, %s elapsed)", res.Status(), metrics, elapsed),
+		}
+	}
+
+	if actual == nil {
+		actual = ConnectionClosedEvent{}
+	}
+
+	return &TestWarning{
+		Reason: fmt.Sprintf("Request not answered: %s (%s, %s elapsed)", actual, metrics, elapsed),
+	}
+}
+
+// VerifyFloodAcks verifies whether the server has reacted after the
+// flood of the frames of the specified type, by reading the frames
+// until no frame is received within the timeout. Acknowledging all the
+// frames, throttling them and terminating the connection with GOAWAY
+// frame of ENHANCE_YOUR_CALM or NO_ERROR are reported as the observed
+// result with the number of the ACKs received. The connection close
+// without GOAWAY frame and the other error codes are reported as a
+// warning. Receiving no frame fails the verification.
+func VerifyFloodAcks(conn *Conn, et EventType, sent int, metrics string) error {
+	var goAway *GoAwayFrameEvent
+
+	acks := 0
+	received := 0
+	for !conn.Closed && acks < sent {
+		ev := conn.WaitEventWithTimeout(conn.Timeout)
+		if ev.Type() == EventTimeout {
+			break
+		}
+		received++
+
+		switch event := ev.(type) {
+		case PingFrameEvent:
+			if et == EventPingFrame && event.IsAck() {
+				acks++
+			}
+		case SettingsFrameEvent:
+			if et == EventSettingsFrame && event.IsAck() {
+				acks++
+			}
+		case GoAwayFrameEvent:
+			goAway = &event
+		}
+	}
+
+	metrics = fmt.Sprintf("%s, %d/%d ACKs received", metrics, acks, sent)
+
+	if goAway != nil {
+		observed := fmt.Sprintf("%s (%s)", goAwayString(*goAway), metrics)
+		if goAway.ErrCode == http2.ErrCodeEnhanceYourCalm || goAway.ErrCode == http2.ErrCodeNo {
+			return &TestInfo{Message: fmt.Sprintf("Terminated deliberately: %s", observed)}
 		}
+		return &TestWarning{Reason: fmt.Sprintf("Terminated with error: %s", observed)}
 	}
 
-	if !passed {
-		expected := []string{}
-		for _, code := range codes {
-			expected = append(expected, fmt.Sprintf(ExpectedGoAwayFrame, code))
-		}
-		expected = append(expected, ExpectedConnectionClosed)
+	if conn.Closed {
+		return &TestWarning{Reason: fmt.Sprintf("Connection closed without GOAWAY frame (%s)", metrics)}
+	}
 
-		return &TestError{
-			Expected: expected,
-			Actual:   actual.String(),
-		}
+	switch {
+	case acks == sent:
+		return &TestInfo{Message: fmt.Sprintf("Kept up (%s)", metrics)}
+	case received > 0:
+		return &TestInfo{Message: fmt.Sprintf("Throttled (%s)", metrics)}
 	}
 
-	return nil
+	return &TestError{
+		Expected: []string{
+			fmt.Sprintf("%s with ACK flag", et),
+			fmt.Sprintf(ExpectedGoAwayFrame, http2.ErrCodeEnhanceYourCalm),
+		},
+		Actual: fmt.Sprintf("No frame received within the timeout (%s)", metrics),
+	}
 }
 
-// VerifyStreamError verifies whether a stream error of HTTP/2
-// has occurred.
-func VerifyStreamError(conn *Conn, codes ...http2.ErrCode) error {
+// VerifyFloodTermination verifies whether the server has terminated
+// the connection with GOAWAY frame or closed it after the flood. The
+// observed result is reported with the specified metrics.
+func VerifyFloodTermination(conn *Conn, metrics string) error {
 	var actual Event
 
-	passed := false
 	for !conn.Closed {
 		ev := conn.WaitEvent()
 
 		switch event := ev.(type) {
-		case ConnectionClosedEvent:
-			passed = true
 		case GoAwayFrameEvent:
-			passed = VerifyErrorCode(codes, event.ErrCode)
-		case RSTStreamFrameEvent:
-			passed = VerifyErrorCode(codes, event.ErrCode)
+			return &TestInfo{Message: fmt.Sprintf("Terminated: %s (%s)", goAwayString(event), metrics)}
+		case ConnectionClosedEvent:
+			return &TestInfo{Message: fmt.Sprintf("Connection closed (%s)", metrics)}
 		case TimeoutEvent:
-			if actual == nil {
-				actual = event
+			if actual != nil {
+				continue
 			}
-		default:
-			actual = event
 		}
 
-		if passed {
-			break
-		}
+		actual = ev
 	}
 
-	if !passed {
-		expected := []string{}
-		for _, code := range codes {
-			expected = append(expected, fmt.Sprintf(ExpectedGoAwayFrame, code))
-			expected = append(expected, fmt.Sprintf(ExpectedRSTStreamFrame, code))
-		}
-		expected = append(expected, ExpectedConnectionClosed)
-
-		return &TestError{
-			Expected: expected,
-			Actual:   actual.String(),
-		}
+	return &TestError{
+		Expected: []string{
+			"GOAWAY Frame",
+			ExpectedConnectionClosed,
+		},
+		Actual: fmt.Sprintf("%s (%s)", actual, metrics),
 	}
-
-	return nil
 }
 
-// VerifyStreamClose verifies whether a stream close of HTTP/2
-// has occurred.
-func VerifyStreamClose(conn *Conn) error {
+// VerifyResponseAfterGoAway verifies whether the response with the
+// :status pseudo-header field on the specified stream has completed
+// after sending a GOAWAY frame, and the connection has not been
+// terminated with an error.
+func VerifyResponseAfterGoAway(conn *Conn, streamID uint32) error {
 	var actual Event
 
-	passed := false
+	status := false
+	completed := false
 	for !conn.Closed {
 		ev := conn.WaitEvent()
 
 		switch event := ev.(type) {
-		case DataFrameEvent:
-			if event.StreamEnded() {
-				passed = true
-			}
 		case HeadersFrameEvent:
-			if event.StreamEnded() {
-				passed = true
+			if event.Header().StreamID == streamID {
+				_, ok := headerFieldValue(event.Fields, ":status")
+				status = status || (event.HeadersEnded() && ok)
+				completed = completed || event.StreamEnded()
+			}
+		case ContinuationFrameEvent:
+			if event.Header().StreamID == streamID {
+				_, ok := headerFieldValue(event.Fields, ":status")
+				status = status || (event.HeadersEnded() && ok)
+			}
+		case DataFrameEvent:
+			if event.Header().StreamID == streamID {
+				completed = completed || event.StreamEnded()
 			}
 		case RSTStreamFrameEvent:
-			if event.ErrCode == http2.ErrCodeNo {
-				passed = true
+			if event.Header().StreamID == streamID {
+				return &TestError{
+					Expected: []string{
+						fmt.Sprintf("HEADERS Frame (stream_id:%d, :status:xxx)", streamID),
+					},
+					Actual: event.String(),
+				}
+			}
+		case GoAwayFrameEvent:
+			if event.ErrCode != http2.ErrCodeNo {
+				return &TestError{
+					Expected: []string{
+						fmt.Sprintf(ExpectedGoAwayFrame, http2.ErrCodeNo),
+						ExpectedConnectionClosed,
+					},
+					Actual: goAwayString(event),
+				}
 			}
 		case TimeoutEvent:
-			if actual == nil {
-				actual = event
+			if actual != nil {
+				continue
 			}
-		default:
-			actual = event
 		}
 
-		if passed {
-			break
-		}
+		actual = ev
 	}
 
-	if !passed {
+	if !status || !completed {
 		return &TestError{
-			Expected: []string{ExpectedStreamClosed},
-			Actual:   actual.String(),
+			Expected: []string{
+				fmt.Sprintf("HEADERS Frame (stream_id:%d, :status:xxx)", streamID),
+				fmt.Sprintf("DATA Frame (flags:0x01, stream_id:%d)", streamID),
+			},
+			Actual: actual.String(),
 		}
 	}
 
 	return nil
 }
 
-// VerifyHeadersFrame verifies whether a HEADERS frame with specified
-// stream ID has received.
-func VerifyHeadersFrame(conn *Conn, streamID uint32) error {
-	actual, passed := conn.WaitEventByType(EventHeadersFrame)
+// VerifySettingsFrameWithAck verifies whether a SETTINGS frame with
+// ACK flag has received.
+func VerifySettingsFrameWithAck(conn *Conn) error {
+	actual, passed := conn.WaitEventByType(EventSettingsFrame)
 	switch event := actual.(type) {
-	case HeadersFrameEvent:
-		passed = (event.Header().StreamID == streamID)
+	case SettingsFrameEvent:
+		passed = event.IsAck()
 	default:
 		passed = false
 	}
 
 	if !passed {
 		expected := []string{
-			fmt.Sprintf("HEADERS Frame (stream_id:%d)", streamID),
+			"SETTINGS Frame (length:0, flags:0x01, stream_id:0)",
 		}
 
 		return &TestError{
@@ -207,29 +1783,85 @@ func VerifyHeadersFrame(conn *Conn, streamID uint32) error {
 	return nil
 }
 
-// VerifySettingsFrameWithAck verifies whether a SETTINGS frame with
-// ACK flag has received.
-func VerifySettingsFrameWithAck(conn *Conn) error {
-	actual, passed := conn.WaitEventByType(EventSettingsFrame)
-	switch event := actual.(type) {
-	case SettingsFrameEvent:
-		passed = event.IsAck()
-	default:
-		passed = false
+// VerifySettingsFrameWithAcks verifies whether exactly the specified
+// number of SETTINGS frames with ACK flag has been received. Only
+// SETTINGS frames with ACK flag are counted, other frames are ignored.
+// The frames are read until the timeout, so that an extra ACK fails
+// the test.
+func VerifySettingsFrameWithAcks(conn *Conn, count int) error {
+	var actual Event
+
+	expected := []string{
+		fmt.Sprintf("%d SETTINGS Frames (length:0, flags:0x01, stream_id:0)", count),
 	}
 
-	if !passed {
-		expected := []string{
-			"SETTINGS Frame (length:0, flags:0x01, stream_id:0)",
+	acks := 0
+	for !conn.Closed {
+		ev := conn.WaitEvent()
+
+		switch event := ev.(type) {
+		case SettingsFrameEvent:
+			if event.IsAck() {
+				acks++
+			}
+		case TimeoutEvent:
+			if acks == count {
+				return nil
+			}
 		}
 
-		return &TestError{
-			Expected: expected,
-			Actual:   actual.String(),
+		if acks > count {
+			return &TestError{
+				Expected: expected,
+				Actual:   fmt.Sprintf("%d SETTINGS Frames (length:0, flags:0x01, stream_id:0)", acks),
+			}
 		}
+
+		actual = ev
 	}
 
-	return nil
+	if actual == nil {
+		actual = ConnectionClosedEvent{}
+	}
+
+	return &TestError{
+		Expected: expected,
+		Actual:   fmt.Sprintf("%d SETTINGS Frames (length:0, flags:0x01, stream_id:0), last event: %s", acks, actual),
+	}
+}
+
+// VerifyNoEventWithin verifies whether no event of the specified type
+// has occured on the connection within the specified duration. The
+// connection can still be used after this verification.
+func VerifyNoEventWithin(conn *Conn, et EventType, d time.Duration) error {
+	deadline := time.Now().Add(d)
+
+	for !conn.Closed {
+		remaining := deadline.Sub(time.Now())
+		if remaining <= 0 {
+			return nil
+		}
+
+		ev := conn.WaitEventWithTimeout(remaining)
+		switch ev.Type() {
+		case EventTimeout:
+			return nil
+		case et, EventConnectionClosed, EventError:
+			return &TestError{
+				Expected: []string{
+					fmt.Sprintf("No %s within %v", et, d),
+				},
+				Actual: ev.String(),
+			}
+		}
+	}
+
+	return &TestError{
+		Expected: []string{
+			fmt.Sprintf("No %s within %v", et, d),
+		},
+		Actual: ExpectedConnectionClosed,
+	}
 }
 
 // VerifyPingFrameWithAck verifies whether a PING frame with ACK flag
@@ -254,7 +1886,7 @@ func VerifyPingFrameWithAck(conn *Conn, data [8]byte) error {
 		if ok {
 			header := f.Header()
 			actualStr = fmt.Sprintf(
-				"PING Frame ((length:%d, flags:0x%02x, stream_id:%d, opaque_data: %s)",
+				"PING Frame (length:%d, flags:0x%02x, stream_id:%d, opaque_data:%s)",
 				header.Length,
 				header.Flags,
 				header.StreamID,
@@ -273,6 +1905,184 @@ func VerifyPingFrameWithAck(conn *Conn, data [8]byte) error {
 	return nil
 }
 
+// VerifyConnectionErrorOrPingFrame verifies whether a connection error
+// has occurred or a PING frame with ACK flag has received. Both are
+// acceptable, and the result describes which one was observed.
+func VerifyConnectionErrorOrPingFrame(conn *Conn, data [8]byte, codes ...http2.ErrCode) error {
+	var actual Event
+
+	observed := ""
+	for !conn.Closed {
+		ev := conn.WaitEvent()
+
+		if conn.skipBackground(ev) {
+			continue
+		}
+
+		switch event := ev.(type) {
+		case ConnectionClosedEvent:
+			observed = ExpectedConnectionClosed
+		case GoAwayFrameEvent:
+			if VerifyErrorCode(codes, event.ErrCode) {
+				observed = fmt.Sprintf("Connection error: %s", goAwayString(event))
+			}
+		case PingFrameEvent:
+			if event.IsAck() && reflect.DeepEqual(event.Data, data) {
+				observed = "Ignored: PING Frame acknowledged"
+			}
+		case TimeoutEvent:
+			if actual != nil {
+				continue
+			}
+		}
+
+		actual = ev
+
+		if observed != "" {
+			break
+		}
+	}
+
+	if observed == "" {
+		expected := []string{}
+		for _, code := range codes {
+			expected = append(expected, fmt.Sprintf(ExpectedGoAwayFrame, code))
+		}
+		expected = append(expected, ExpectedConnectionClosed)
+		expected = append(expected, fmt.Sprintf("PING Frame (length:8, flags:0x01, stream_id:0, opaque_data:%s)", data))
+
+		return &TestError{
+			Expected: expected,
+			Actual:   actual.String(),
+		}
+	}
+
+	return &TestInfo{Message: observed}
+}
+
+// VerifyStreamErrorOrPingFrame verifies whether a stream error has
+// occurred or a PING frame with ACK flag has received. The connection
+// error with the same error code is also accepted. The result
+// describes which one was observed.
+func VerifyStreamErrorOrPingFrame(conn *Conn, data [8]byte, codes ...http2.ErrCode) error {
+	var actual Event
+
+	observed := ""
+	for !conn.Closed {
+		ev := conn.WaitEvent()
+
+		if conn.skipBackground(ev) {
+			continue
+		}
+
+		switch event := ev.(type) {
+		case ConnectionClosedEvent:
+			observed = ExpectedConnectionClosed
+		case GoAwayFrameEvent:
+			if VerifyErrorCode(codes, event.ErrCode) {
+				observed = fmt.Sprintf("Connection error: %s", goAwayString(event))
+			}
+		case RSTStreamFrameEvent:
+			if VerifyErrorCode(codes, event.ErrCode) {
+				observed = fmt.Sprintf("Stream error: %s", rstStreamString(event))
+			}
+		case PingFrameEvent:
+			if event.IsAck() && reflect.DeepEqual(event.Data, data) {
+				observed = "Ignored: PING Frame acknowledged"
+			}
+		case TimeoutEvent:
+			if actual != nil {
+				continue
+			}
+		}
+
+		actual = ev
+
+		if observed != "" {
+			break
+		}
+	}
+
+	if observed == "" {
+		expected := []string{}
+		for _, code := range codes {
+			expected = append(expected, fmt.Sprintf(ExpectedGoAwayFrame, code))
+			expected = append(expected, fmt.Sprintf(ExpectedRSTStreamFrame, code))
+		}
+		expected = append(expected, ExpectedConnectionClosed)
+		expected = append(expected, fmt.Sprintf("PING Frame (length:8, flags:0x01, stream_id:0, opaque_data:%s)", data))
+
+		return &TestError{
+			Expected: expected,
+			Actual:   actual.String(),
+		}
+	}
+
+	return &TestInfo{Message: observed}
+}
+
+// VerifySettingUnchanged verifies whether the value of the specified
+// setting has not been changed in the SETTINGS frames received from
+// the peer over the connection.
+func VerifySettingUnchanged(conn *Conn, id http2.SettingID) error {
+	advertised := false
+	var val uint32
+
+	for _, settings := range conn.SettingsHistory {
+		for _, setting := range settings {
+			if setting.ID != id {
+				continue
+			}
+
+			if advertised && setting.Val != val {
+				return &TestError{
+					Expected: []string{
+						fmt.Sprintf("SETTINGS Frame (0x%02x: %d)", uint16(id), val),
+					},
+					Actual: fmt.Sprintf("SETTINGS Frame (0x%02x: %d)", uint16(id), setting.Val),
+				}
+			}
+
+			advertised = true
+			val = setting.Val
+		}
+	}
+
+	return nil
+}
+
+// VerifySettingValues verifies whether the value of the specified
+// setting received from the peer over the connection is always one of
+// the specified values.
+func VerifySettingValues(conn *Conn, id http2.SettingID, values ...uint32) error {
+	for _, settings := range conn.SettingsHistory {
+		for _, setting := range settings {
+			if setting.ID != id {
+				continue
+			}
+
+			valid := false
+			for _, val := range values {
+				valid = valid || (setting.Val == val)
+			}
+
+			if !valid {
+				expected := []string{}
+				for _, val := range values {
+					expected = append(expected, fmt.Sprintf("SETTINGS Frame (0x%02x: %d)", uint16(id), val))
+				}
+
+				return &TestError{
+					Expected: expected,
+					Actual:   fmt.Sprintf("SETTINGS Frame (0x%02x: %d)", uint16(id), setting.Val),
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
 // VerifyPingFrameOrConnectionClose verifies whether a PING frame with
 // ACK flag has received or the connection was closed.
 func VerifyPingFrameOrConnectionClose(conn *Conn, data [8]byte) error {
@@ -282,6 +2092,10 @@ func VerifyPingFrameOrConnectionClose(conn *Conn, data [8]byte) error {
 	for !conn.Closed {
 		event := conn.WaitEvent()
 
+		if conn.skipBackground(event) {
+			continue
+		}
+
 		switch ev := event.(type) {
 		case ConnectionClosedEvent:
 			passed = true
@@ -358,3 +2172,99 @@ func VerifyErrorCode(codes []http2.ErrCode, code http2.ErrCode) bool {
 	}
 	return false
 }
+
+// goAwayString returns a string representation of the GOAWAY frame
+// including its last stream ID, error code and debug data.
+func goAwayString(ev GoAwayFrameEvent) string {
+	header := ev.Header()
+	debug := ""
+	if len(ev.Debug) > 0 {
+		debug = fmt.Sprintf(", debug_data:%s", DebugDataString(ev.Debug))
+	}
+
+	return fmt.Sprintf(
+		"GOAWAY Frame (length:%d, flags:0x%02x, stream_id:%d, last_stream_id:%d, error_code:%s%s)",
+		header.Length,
+		header.Flags,
+		header.StreamID,
+		ev.LastStreamID,
+		ev.ErrCode,
+		debug,
+	)
+}
+
+// rstStreamString returns a string representation of the RST_STREAM
+// frame including its error code.
+func rstStreamString(ev RSTStreamFrameEvent) string {
+	header := ev.Header()
+	return fmt.Sprintf(
+		"RST_STREAM Frame (length:%d, flags:0x%02x, stream_id:%d, error_code:%s)",
+		header.Length,
+		header.Flags,
+		header.StreamID,
+		ev.ErrCode,
+	)
+}
+
+// maxDebugDataLength is the maximum length of the debug data of GOAWAY
+// frame shown in the results.
+const maxDebugDataLength = 256
+
+// DebugDataString returns the debug data of GOAWAY frame as a quoted
+// string with the non-printable characters escaped. The debug data
+// longer than maxDebugDataLength is truncated.
+func DebugDataString(data []byte) string {
+	if len(data) > maxDebugDataLength {
+		return strconv.Quote(string(data[:maxDebugDataLength])) + "..."
+	}
+	return strconv.Quote(string(data))
+}
+
+// headersString returns a string representation of the HEADERS frame
+// including its :status pseudo-header field.
+func headersString(header http2.FrameHeader, fields []hpack.HeaderField) string {
+	status, ok := headerFieldValue(fields, ":status")
+	if !ok {
+		status = "(none)"
+	}
+
+	return fmt.Sprintf(
+		"HEADERS Frame (length:%d, flags:0x%02x, stream_id:%d, :status:%s)",
+		header.Length,
+		header.Flags,
+		header.StreamID,
+		status,
+	)
+}
+
+// headerFieldValue returns the value of the first header field that
+// has the specified name.
+func headerFieldValue(fields []hpack.HeaderField, name string) (string, bool) {
+	for _, f := range fields {
+		if f.Name == name {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// isSwitchingProtocolsStatus returns bool as to whether the header
+// fields contain the :status pseudo-header field with 101 status code.
+func isSwitchingProtocolsStatus(fields []hpack.HeaderField) bool {
+	status, ok := headerFieldValue(fields, ":status")
+	return ok && status == "101"
+}
+
+// isValidStatus returns true if the value of ":status" pseudo-header
+// field is a three-digit status code from 100 to 599.
+func isValidStatus(status string) bool {
+	code, err := strconv.Atoi(status)
+	return err == nil && len(status) == 3 && code >= 100 && code <= 599
+}
+
+// isClientErrorStatus returns bool as to whether the header fields
+// contain the :status pseudo-header field with 4xx status code.
+func isClientErrorStatus(fields []hpack.HeaderField) bool {
+	status, ok := headerFieldValue(fields, ":status")
+	return ok && len(status) == 3 && status[0] == '4'
+}
@@ -0,0 +1,105 @@
+package spec
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	// WebSocketOpcodeText is the opcode of a text frame.
+	WebSocketOpcodeText byte = 0x1
+	// WebSocketOpcodeClose is the opcode of a close frame.
+	WebSocketOpcodeClose byte = 0x8
+)
+
+// WebSocketFrame represents a frame of the WebSocket protocol
+// described in RFC 6455 Section 5.2.
+type WebSocketFrame struct {
+	Fin     bool
+	Opcode  byte
+	Masked  bool
+	Payload []byte
+}
+
+// EncodeWebSocketFrame returns the final frame of the WebSocket
+// protocol with the specified opcode and payload. The payload is
+// masked with the specified key since all frames sent from the client
+// must be masked.
+func EncodeWebSocketFrame(opcode byte, payload []byte, key [4]byte) []byte {
+	frame := []byte{0x80 | opcode}
+
+	switch {
+	case len(payload) < 126:
+		frame = append(frame, 0x80|byte(len(payload)))
+	case len(payload) < 65536:
+		frame = append(frame, 0x80|126, byte(len(payload)>>8), byte(len(payload)))
+	default:
+		frame = append(frame, 0x80|127)
+		frame = append(frame, make([]byte, 8)...)
+		binary.BigEndian.PutUint64(frame[len(frame)-8:], uint64(len(payload)))
+	}
+
+	frame = append(frame, key[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^key[i%4])
+	}
+
+	return frame
+}
+
+// ParseWebSocketFrames parses the frames of the WebSocket protocol in
+// the specified data. The bytes of the incomplete frame at the end of
+// the data are returned as the rest.
+func ParseWebSocketFrames(data []byte) ([]WebSocketFrame, []byte, error) {
+	frames := []WebSocketFrame{}
+
+	for len(data) >= 2 {
+		f := WebSocketFrame{
+			Fin:    data[0]&0x80 != 0,
+			Opcode: data[0] & 0x0f,
+			Masked: data[1]&0x80 != 0,
+		}
+		if data[0]&0x70 != 0 {
+			return nil, nil, errors.New("reserved bits of WebSocket frame are set")
+		}
+
+		header := 2
+		length := uint64(data[1] & 0x7f)
+		switch length {
+		case 126:
+			header += 2
+			if len(data) >= header {
+				length = uint64(binary.BigEndian.Uint16(data[2:]))
+			}
+		case 127:
+			header += 8
+			if len(data) >= header {
+				length = binary.BigEndian.Uint64(data[2:])
+			}
+		}
+
+		var key []byte
+		if f.Masked {
+			header += 4
+			if len(data) >= header {
+				key = data[header-4 : header]
+			}
+		}
+
+		if uint64(len(data)) < uint64(header)+length {
+			break
+		}
+
+		f.Payload = append([]byte{}, data[header:header+int(length)]...)
+		for i := range key {
+			for j := i; j < len(f.Payload); j += 4 {
+				f.Payload[j] ^= key[i]
+			}
+		}
+
+		frames = append(frames, f)
+		data = data[header+int(length):]
+	}
+
+	return frames, data, nil
+}